@@ -8,8 +8,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	http_delivery "dailynews/internal/delivery/http"
+	"dailynews/internal/domain"
 	"dailynews/internal/infrastructure"
 	"dailynews/internal/repository"
 	"dailynews/internal/usecase"
@@ -173,10 +175,96 @@ func main() {
 	countryRepo := repository.NewCountryRepository(db.DB)
 	newsSourceRepo := repository.NewNewsSourceRepository(db.DB)
 	fallbackImageRepo := repository.NewFallbackImageRepository(db.DB) // NUEVO
+	httpProfileRepo := repository.NewHTTPProfileRepository(db.DB)
+	sourceHealthRepo := repository.NewSourceHealthRepository(db.DB)
+	webSubRepo := repository.NewWebSubSubscriptionRepository(db.DB)
+	cachedImageRepo := repository.NewCachedImageRepository(db.DB)
+	imageValidationRepo := repository.NewImageValidationRepository(db.DB)
+	imagePlaceholderRepo := repository.NewImagePlaceholderRepository(db.DB)
 
 	// 6. Instanciar Componentes de Infraestructura
-	imageDownloader := infrastructure.NewImageDownloader(cfg.Filters.TargetAspect, cfg.Filters.AspectTolerance, 800, 450)
-	rssFetcher := infrastructure.NewRSSFetcher()
+	// Transporte compartido: límites por host, robots.txt y reintentos para feeds e imágenes
+	hostTransport := infrastructure.NewHostTransport(infrastructure.HostPolicy{
+		RequestsPerSecond: cfg.Politeness.RequestsPerSecondPerHost,
+		MaxConcurrent:     cfg.Politeness.MaxConcurrentPerHost,
+		RespectRobots:     cfg.Politeness.RespectRobots,
+		MaxRetries:        cfg.Politeness.MaxRetries,
+		RetryBaseDelay:    time.Duration(cfg.Politeness.RetryBaseDelayMs) * time.Millisecond,
+		RetryMaxDelay:     time.Duration(cfg.Politeness.RetryMaxDelaySeconds) * time.Second,
+
+		CircuitFailureThreshold: cfg.Politeness.CircuitFailureThreshold,
+		CircuitCooldown:         time.Duration(cfg.Politeness.CircuitCooldownSeconds) * time.Second,
+	})
+	// El recorte de imágenes con otro aspecto solo tiene sentido con la caché local, que guarda la versión recortada
+	var imageCrop infrastructure.ImageCropPolicy
+	if cfg.ImageCache.Enabled {
+		imageCrop = infrastructure.ImageCropPolicy{
+			Mode:            cfg.ImageCache.Crop,
+			MinSourceWidth:  cfg.ImageCache.MinSourceWidth,
+			MinSourceHeight: cfg.ImageCache.MinSourceHeight,
+		}
+	}
+	imageOutput := infrastructure.ImageOutputPolicy{
+		Resampling:     cfg.ImageCache.Resampling,
+		WebPQuality:    cfg.ImageCache.WebPQuality,
+		JPEGQuality:    cfg.ImageCache.JPEGQuality,
+		LetterboxColor: cfg.ImageCache.LetterboxColor,
+	}
+	imageDownloader := infrastructure.NewImageDownloader(cfg.Filters.TargetAspect, cfg.Filters.AspectTolerance, 800, 450,
+		cfg.Filters.MaxImageBytes, cfg.Filters.MaxImagePixels, imageCrop, imageOutput, hostTransport)
+	// Caché persistente de validaciones: las imágenes ya comprobadas no se vuelven a descargar hasta que caducan
+	validationTTL := time.Duration(cfg.ImageValidation.TTLHours) * time.Hour
+	validationFailureTTL := time.Duration(cfg.ImageValidation.FailureTTLHours) * time.Hour
+	if validationTTL > 0 {
+		imageDownloader = infrastructure.NewCachedImageValidator(imageDownloader, imageValidationRepo, validationTTL, validationFailureTTL)
+	}
+	rssFetcher := infrastructure.NewRSSFetcher(hostTransport, time.Duration(cfg.FeedCache.TTLSeconds)*time.Second,
+		cfg.Filters.TargetAspect, 800, 450)
+	sourceFetcher := infrastructure.NewSourceFetcher(map[string]domain.RSSFetcher{
+		domain.SourceTypeRSS:  rssFetcher,
+		domain.SourceTypeHTML: infrastructure.NewHTMLFetcher(hostTransport),
+		domain.SourceTypeJSON: infrastructure.NewJSONFetcher(hostTransport),
+		domain.SourceTypeSitemap: infrastructure.NewSitemapFetcher(
+			hostTransport,
+			cfg.Sitemap.MaxURLs,
+			time.Duration(cfg.Sitemap.MaxAgeHours)*time.Hour,
+			cfg.Sitemap.MaxChildSitemaps,
+		),
+	})
+
+	// Imágenes de la página de la noticia (og:image, twitter:image, JSON-LD) para items sin imagen válida
+	articleImageExtractor := infrastructure.NewArticleImageExtractor(hostTransport, int64(cfg.ImageEnrich.MaxBytes),
+		time.Duration(cfg.ImageEnrich.TimeoutSeconds)*time.Second, cfg.Filters.TargetAspect, 800, 450)
+
+	// Caché local de imágenes: las tarjetas sirven copias optimizadas en lugar de enlazar al medio
+	var imageCache domain.ImageCache
+	if cfg.ImageCache.Enabled {
+		imageCache = infrastructure.NewImageCache(imageDownloader, cachedImageRepo,
+			filepath.Join("frontend", "assets", "images", "cache"), cfg.ImageCache.Format, cfg.ImageCache.Renditions, imageOutput,
+			time.Duration(cfg.ImageCache.MaxAgeDays)*24*time.Hour, int64(cfg.ImageCache.MaxSizeMB)*1024*1024)
+	}
+
+	// Imágenes de marca generadas para las noticias sin imagen cuando no hay ningún fallback subido
+	var imageGenerator domain.FallbackImageGenerator
+	if cfg.GeneratedImages.Enabled {
+		imageGenerator = infrastructure.NewFallbackImageGenerator(filepath.Join("frontend", "assets", "images", "generated"),
+			cfg.ImageCache.Format, 800, 450, cfg.GeneratedImages.IncludeTitle, imageOutput,
+			time.Duration(cfg.GeneratedImages.MaxAgeDays)*24*time.Hour, newsItemRepo)
+	}
+
+	// Texto completo de las noticias: solo si está activado globalmente (y después en cada fuente)
+	var articleContentExtractor domain.ArticleContentExtractor
+	if cfg.ArticleContent.Enabled {
+		articleContentExtractor = infrastructure.NewArticleContentExtractor(hostTransport, int64(cfg.ArticleContent.MaxBytes),
+			time.Duration(cfg.ArticleContent.TimeoutSeconds)*time.Second, cfg.ArticleContent.WordsPerMinute)
+	}
+
+	// Suscripciones WebSub: solo si hay una URL pública a la que el hub pueda llamar
+	var webSubManager domain.WebSubManager
+	if cfg.WebSub.Enabled && cfg.WebSub.CallbackBaseURL != "" {
+		webSubManager = infrastructure.NewWebSubManager(webSubRepo, newsSourceRepo, hostTransport,
+			cfg.WebSub.CallbackBaseURL, time.Duration(cfg.WebSub.LeaseHours)*time.Hour)
+	}
 
 	// 7. Instanciar Caso de Uso
 	fetchNewsUseCase := usecase.NewFetchNewsUseCase(
@@ -185,8 +273,15 @@ func main() {
 		countryRepo,
 		newsSourceRepo,
 		fallbackImageRepo, // NUEVO
-		rssFetcher,
+		sourceHealthRepo,
+		webSubManager,
+		sourceFetcher,
 		imageDownloader,
+		articleImageExtractor,
+		articleContentExtractor,
+		imageCache,
+		imagePlaceholderRepo,
+		imageGenerator,
 		cfg,
 	)
 
@@ -217,6 +312,39 @@ func main() {
 		}
 		log.Println("Tarea cron de extracción de noticias finalizada.")
 	})
+	if webSubManager != nil {
+		cronScheduler.ScheduleJob("websub_renewal", "@hourly", func() {
+			if err := webSubManager.RenewExpiring(context.Background()); err != nil {
+				log.Printf("Error renovando suscripciones WebSub: %v", err)
+			}
+		})
+	}
+	if validationTTL > 0 {
+		cronScheduler.ScheduleJob("image_validation_purge", "@daily", func() {
+			// Las entradas caducadas ya no se usan: se borran para que la tabla no crezca sin límite
+			maxTTL := validationTTL
+			if validationFailureTTL > maxTTL {
+				maxTTL = validationFailureTTL
+			}
+			if _, err := imageValidationRepo.DeleteCheckedBefore(context.Background(), time.Now().Add(-maxTTL)); err != nil {
+				log.Printf("Error purgando la caché de validaciones de imágenes: %v", err)
+			}
+		})
+	}
+	if imageCache != nil {
+		cronScheduler.ScheduleJob("image_cache_gc", "@daily", func() {
+			if _, err := imageCache.CollectGarbage(context.Background()); err != nil {
+				log.Printf("Error limpiando la caché de imágenes: %v", err)
+			}
+		})
+	}
+	if imageGenerator != nil {
+		cronScheduler.ScheduleJob("generated_images_gc", "@daily", func() {
+			if _, err := imageGenerator.CollectGarbage(context.Background()); err != nil {
+				log.Printf("Error limpiando las imágenes generadas: %v", err)
+			}
+		})
+	}
 	cronScheduler.Start()
 	log.Println("Cron scheduler iniciado.")
 
@@ -236,6 +364,18 @@ func main() {
 		newsSourceRepo,
 		fallbackImageRepo, // NUEVO
 		rssFetcher,
+		sourceFetcher,
+		infrastructure.NewFeedDiscoverer(hostTransport),
+		imageDownloader,
+		cfg,
+		httpProfileRepo,
+		sourceHealthRepo,
+		hostTransport,
+		webSubRepo,
+		webSubManager,
+		imageValidationRepo,
+		imagePlaceholderRepo,
+		infrastructure.HTTPProfileClients(),
 	)
 	log.Printf("Iniciando servidor HTTP en el puerto %d...", cfg.Server.HTTP.Port)
 	http_delivery.StartHTTPServer(httpHandler, "./noticias", fmt.Sprintf("%d", cfg.Server.HTTP.Port))
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/circuit-breakers - Estado del circuit breaker de cada host contactado
+func (h *Handler) ListCircuitBreakersHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, h.CircuitMonitor.CircuitStates())
+}
+
+// POST /api/circuit-breakers/:host/reset - Cerrar manualmente el circuito de un host
+func (h *Handler) ResetCircuitBreakerHandler(c *gin.Context) {
+	host := c.Param("host")
+	if !h.CircuitMonitor.ResetCircuit(host) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No hay circuito para ese host"})
+		return
+	}
+
+	utils.AppInfo("CIRCUIT_BREAKER", "Circuito cerrado manualmente", map[string]interface{}{
+		"host": host,
+	})
+	c.JSON(http.StatusOK, gin.H{"success": true, "host": host})
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// discoveredSource es un candidato encontrado por la búsqueda de feeds, ya probado y puntuado
+type discoveredSource struct {
+	URL           string  `json:"url"`
+	Type          string  `json:"type"`
+	Title         string  `json:"title"`
+	Language      string  `json:"language"`
+	Origin        string  `json:"origin"`
+	Pattern       string  `json:"pattern,omitempty"`
+	ValidItems    int     `json:"valid_items"`
+	TotalItems    int     `json:"total_items"`
+	ImageCoverage float64 `json:"image_coverage"` // Porcentaje de noticias válidas con imagen (0-100)
+	LanguageMatch bool    `json:"language_match"`
+	Score         float64 `json:"score"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// POST /api/sources/discover - Buscar feeds y sitemaps a partir de la URL de una web.
+// body: { "url": "...", "language": "es" (opcional, para priorizar feeds en ese idioma) }
+func (h *Handler) DiscoverSourcesHandler(c *gin.Context) {
+	var req struct {
+		URL      string `json:"url" binding:"required"`
+		Language string `json:"language"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "URL requerida"})
+		return
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	req.Language = strings.TrimSpace(req.Language)
+
+	ctx := c.Request.Context()
+
+	candidates, err := h.FeedDiscoverer.Discover(ctx, req.URL)
+	if err != nil {
+		utils.AppError("DISCOVER_SOURCES", "Error al buscar feeds", err, map[string]interface{}{
+			"url": req.URL,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "No se pudo analizar la web indicada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Los candidatos se prueban de uno en uno: el RSSFetcher comparte un único parser
+	results := make([]discoveredSource, 0, len(candidates))
+	for _, candidate := range candidates {
+		results = append(results, h.evaluateCandidate(ctx, candidate, req.Language))
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	utils.AppInfo("DISCOVER_SOURCES", "Búsqueda de feeds completada", map[string]interface{}{
+		"url":        req.URL,
+		"candidates": len(results),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    len(results) > 0,
+		"url":        req.URL,
+		"candidates": results,
+	})
+}
+
+// evaluateCandidate detecta el patrón del candidato y calcula su puntuación:
+// noticias válidas (hasta 50 puntos), cobertura de imagen (hasta 30) e idioma (hasta 20)
+func (h *Handler) evaluateCandidate(ctx context.Context, candidate domain.FeedCandidate, language string) discoveredSource {
+	result := discoveredSource{
+		URL:      candidate.URL,
+		Type:     candidate.Type,
+		Title:    candidate.Title,
+		Language: candidate.Language,
+		Origin:   candidate.Origin,
+	}
+
+	var items []domain.NewsItem
+	var err error
+	if candidate.Type == domain.SourceTypeRSS {
+		result.Pattern, err = h.detectBestPattern(ctx, candidate.URL)
+		if err == nil {
+			items, err = h.RSSFetcher.Fetch(ctx, candidate.URL, result.Pattern, "", "", "", "")
+		}
+	} else {
+		result.Pattern = candidate.Type
+		items, err = h.SourceFetcher.FetchSource(ctx, &domain.NewsSource{
+			RSSURL:     candidate.URL,
+			SourceType: candidate.Type,
+		})
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	withImage := 0
+	for _, item := range items {
+		if item.Title != "" && item.Link != "" && len(item.Title) > 10 {
+			result.ValidItems++
+			if item.Image != "" {
+				withImage++
+			}
+		}
+	}
+	result.TotalItems = len(items)
+	if result.ValidItems > 0 {
+		result.ImageCoverage = math.Round(float64(withImage)*1000/float64(result.ValidItems)) / 10
+	}
+
+	languageScore := 0.0
+	if language != "" {
+		switch {
+		case sameLanguage(candidate.Language, language):
+			result.LanguageMatch = true
+			languageScore = 1
+		case candidate.Language == "":
+			languageScore = 0.5
+		}
+	}
+
+	score := math.Min(float64(result.ValidItems), 20)/20*50 + result.ImageCoverage/100*30 + languageScore*20
+	result.Score = math.Round(score*10) / 10
+	return result
+}
+
+// sameLanguage compara códigos de idioma ignorando la región ("es-ES" equivale a "es")
+func sameLanguage(a, b string) bool {
+	primary := func(code string) string {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if idx := strings.IndexAny(code, "-_"); idx != -1 {
+			code = code[:idx]
+		}
+		return code
+	}
+	return a != "" && primary(a) == primary(b)
+}
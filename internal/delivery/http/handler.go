@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"dailynews/internal/domain"
+	"dailynews/pkg/config"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,14 +20,33 @@ type Handler struct {
 	CountryRepo           domain.CountryRepository
 	SourceRepo            domain.NewsSourceRepository
 	FallbackImageRepo     domain.FallbackImageRepository // NUEVO
+	HTTPProfileRepo       domain.HTTPProfileRepository
+	HTTPProfileClients    domain.HTTPProfileClientCache
+	SourceHealthRepo      domain.SourceHealthRepository
+	WebSubRepo            domain.WebSubSubscriptionRepository
+	ImageValidationRepo   domain.ImageValidationRepository
+	ImagePlaceholderRepo  domain.ImagePlaceholderRepository
 	RSSFetcher            domain.RSSFetcher
+	SourceFetcher         domain.SourceFetcher
+	FeedDiscoverer        domain.FeedDiscoverer
+	ImageDownloader       domain.ImageDownloader
+	CircuitMonitor        domain.CircuitBreakerMonitor
+	WebSub                domain.WebSubManager // nil si WebSub está desactivado
+	Config                *config.Config
 }
 
 func NewHandler(fetchUseCase func(ctx context.Context) error,
 	fetchUseCaseForSource func(ctx context.Context, sourceID uint) error,
 	newsRepo domain.NewsItemRepository, categoryRepo domain.CategoryRepository,
 	countryRepo domain.CountryRepository, sourceRepo domain.NewsSourceRepository,
-	fallbackImageRepo domain.FallbackImageRepository, rssFetcher domain.RSSFetcher) *Handler {
+	fallbackImageRepo domain.FallbackImageRepository, rssFetcher domain.RSSFetcher,
+	sourceFetcher domain.SourceFetcher, feedDiscoverer domain.FeedDiscoverer,
+	imageDownloader domain.ImageDownloader, cfg *config.Config,
+	httpProfileRepo domain.HTTPProfileRepository, sourceHealthRepo domain.SourceHealthRepository,
+	circuitMonitor domain.CircuitBreakerMonitor, webSubRepo domain.WebSubSubscriptionRepository,
+	webSub domain.WebSubManager, imageValidationRepo domain.ImageValidationRepository,
+	imagePlaceholderRepo domain.ImagePlaceholderRepository,
+	httpProfileClients domain.HTTPProfileClientCache) *Handler {
 	return &Handler{
 		FetchUseCase:          fetchUseCase,
 		FetchUseCaseForSource: fetchUseCaseForSource,
@@ -36,6 +56,18 @@ func NewHandler(fetchUseCase func(ctx context.Context) error,
 		SourceRepo:            sourceRepo,
 		FallbackImageRepo:     fallbackImageRepo, // NUEVO
 		RSSFetcher:            rssFetcher,
+		SourceFetcher:         sourceFetcher,
+		FeedDiscoverer:        feedDiscoverer,
+		ImageDownloader:       imageDownloader,
+		Config:                cfg,
+		HTTPProfileRepo:       httpProfileRepo,
+		SourceHealthRepo:      sourceHealthRepo,
+		CircuitMonitor:        circuitMonitor,
+		WebSubRepo:            webSubRepo,
+		WebSub:                webSub,
+		ImageValidationRepo:   imageValidationRepo,
+		ImagePlaceholderRepo:  imagePlaceholderRepo,
+		HTTPProfileClients:    httpProfileClients,
 	}
 }
 
@@ -82,6 +114,14 @@ func (h *Handler) GetNewsHandler(c *gin.Context) {
 			"source": item.Source.SourceName,
 			"date":   item.PubDate.Format(time.RFC3339),
 		}
+		if item.ImageBlurHash != "" {
+			newsItem["blurhash"] = item.ImageBlurHash
+		}
+		if item.Content != "" {
+			newsItem["id"] = item.ID
+			newsItem["word_count"] = item.WordCount
+			newsItem["reading_time"] = item.ReadingTime
+		}
 		response = append(response, newsItem)
 	}
 
@@ -97,6 +137,38 @@ func (h *Handler) GetNewsHandler(c *gin.Context) {
 	})
 }
 
+// GET /api/news/item/:id/content - Texto completo de la noticia para la vista de lectura
+func (h *Handler) GetNewsContentHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	item, err := h.NewsRepo.FindByID(c.Request.Context(), uint(id))
+	if err != nil || item == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+		return
+	}
+	if item.Content == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "La noticia no tiene texto completo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":           item.ID,
+		"title":        item.Title,
+		"link":         item.Link,
+		"image":        item.Image,
+		"blurhash":     item.ImageBlurHash,
+		"source":       item.Source.SourceName,
+		"date":         item.PubDate.Format(time.RFC3339),
+		"content":      item.Content,
+		"word_count":   item.WordCount,
+		"reading_time": item.ReadingTime,
+	})
+}
+
 // GET /api/news/search
 func (h *Handler) SearchNewsHandler(c *gin.Context) {
 	query := c.Query("q")
@@ -133,6 +205,9 @@ func (h *Handler) SearchNewsHandler(c *gin.Context) {
 				"source": item.Source.SourceName,
 				"date":   item.PubDate.Format(time.RFC3339),
 			}
+			if item.ImageBlurHash != "" {
+				newsItem["blurhash"] = item.ImageBlurHash
+			}
 			results = append(results, newsItem)
 		}
 	}
@@ -0,0 +1,350 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpProfileRequest es el cuerpo para crear o actualizar un perfil HTTP.
+// Las credenciales no viajan en claro: secretEnv y las referencias ${VARIABLE} apuntan a variables de entorno,
+// que deben empezar por domain.SecretEnvPrefix para que ninguna otra variable del servidor salga en una petición.
+type httpProfileRequest struct {
+	Name               string            `json:"name" binding:"required"`
+	UserAgent          string            `json:"userAgent"`
+	Headers            map[string]string `json:"headers"`
+	AuthType           string            `json:"authType"`        // "", "basic" o "bearer"
+	Username           string            `json:"username"`        // Usuario para "basic"
+	SecretEnv          string            `json:"secretEnv"`       // Variable de entorno con la contraseña o token
+	CredentialHosts    []string          `json:"credentialHosts"` // Otros hosts que reciben cabeceras y autenticación
+	ProxyURL           string            `json:"proxyUrl"`
+	TimeoutSeconds     int               `json:"timeoutSeconds"`
+	InsecureSkipVerify bool              `json:"insecureSkipVerify"`
+	MinTLSVersion      string            `json:"minTlsVersion"`  // "", "1.0", "1.1", "1.2" o "1.3"
+	RedirectPolicy     string            `json:"redirectPolicy"` // "follow", "same-host" o "none"
+	MaxRedirects       int               `json:"maxRedirects"`
+}
+
+// httpProfileResponse es la representación pública de un perfil HTTP
+type httpProfileResponse struct {
+	ID                 uint              `json:"id"`
+	Name               string            `json:"name"`
+	UserAgent          string            `json:"userAgent"`
+	Headers            map[string]string `json:"headers"`
+	AuthType           string            `json:"authType"`
+	Username           string            `json:"username"`
+	SecretEnv          string            `json:"secretEnv"`
+	SecretConfigured   bool              `json:"secretConfigured"` // La variable de entorno existe en el servidor
+	CredentialHosts    []string          `json:"credentialHosts"`
+	ProxyURL           string            `json:"proxyUrl"`
+	TimeoutSeconds     int               `json:"timeoutSeconds"`
+	InsecureSkipVerify bool              `json:"insecureSkipVerify"`
+	MinTLSVersion      string            `json:"minTlsVersion"`
+	RedirectPolicy     string            `json:"redirectPolicy"`
+	MaxRedirects       int               `json:"maxRedirects"`
+	UpdatedAt          time.Time         `json:"updatedAt"`
+}
+
+// validate comprueba los valores permitidos del perfil
+func (r *httpProfileRequest) validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.AuthType = strings.ToLower(strings.TrimSpace(r.AuthType))
+	r.RedirectPolicy = strings.ToLower(strings.TrimSpace(r.RedirectPolicy))
+	r.SecretEnv = strings.TrimSpace(r.SecretEnv)
+	r.ProxyURL = strings.TrimSpace(r.ProxyURL)
+
+	if r.Name == "" {
+		return fmt.Errorf("el nombre es requerido")
+	}
+	switch r.AuthType {
+	case "", domain.HTTPAuthBasic, domain.HTTPAuthBearer:
+	default:
+		return fmt.Errorf("tipo de autenticación no soportado: %s", r.AuthType)
+	}
+	if r.AuthType != "" && r.SecretEnv == "" {
+		return fmt.Errorf("la autenticación requiere secretEnv (variable de entorno con la credencial)")
+	}
+	if r.SecretEnv != "" && !domain.IsSecretEnv(r.SecretEnv) {
+		return fmt.Errorf("secretEnv debe empezar por %s: %s", domain.SecretEnvPrefix, r.SecretEnv)
+	}
+	for _, value := range append([]string{r.ProxyURL}, headerValues(r.Headers)...) {
+		for _, ref := range domain.SecretRefPattern.FindAllStringSubmatch(value, -1) {
+			if !domain.IsSecretEnv(ref[1]) {
+				return fmt.Errorf("las referencias ${VARIABLE} deben empezar por %s: %s", domain.SecretEnvPrefix, ref[1])
+			}
+		}
+	}
+	hosts := make([]string, 0, len(r.CredentialHosts))
+	for _, host := range r.CredentialHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+		if strings.ContainsAny(host, "/:, ") || strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+			return fmt.Errorf("host inválido en credentialHosts: %s", host)
+		}
+		hosts = append(hosts, host)
+	}
+	r.CredentialHosts = hosts
+	switch r.RedirectPolicy {
+	case "", domain.RedirectFollow, domain.RedirectSameHost, domain.RedirectNone:
+	default:
+		return fmt.Errorf("política de redirección no soportada: %s", r.RedirectPolicy)
+	}
+	switch r.MinTLSVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		return fmt.Errorf("versión de TLS no soportada: %s", r.MinTLSVersion)
+	}
+	if r.TimeoutSeconds < 0 || r.TimeoutSeconds > 300 {
+		return fmt.Errorf("el timeout debe estar entre 0 y 300 segundos")
+	}
+	if r.MaxRedirects < 0 || r.MaxRedirects > 30 {
+		return fmt.Errorf("el máximo de redirecciones debe estar entre 0 y 30")
+	}
+	if r.ProxyURL != "" {
+		if u, err := url.Parse(r.ProxyURL); err != nil || u.Host == "" {
+			return fmt.Errorf("URL de proxy inválida")
+		}
+	}
+	return nil
+}
+
+// headerValues devuelve los valores de las cabeceras
+func headerValues(headers map[string]string) []string {
+	values := make([]string, 0, len(headers))
+	for _, value := range headers {
+		values = append(values, value)
+	}
+	return values
+}
+
+// applyTo copia los datos de la petición al perfil
+func (r *httpProfileRequest) applyTo(profile *domain.HTTPProfile) {
+	profile.Name = r.Name
+	profile.UserAgent = strings.TrimSpace(r.UserAgent)
+	profile.Headers = nil
+	if len(r.Headers) > 0 {
+		if data, err := json.Marshal(r.Headers); err == nil {
+			headers := string(data)
+			profile.Headers = &headers
+		}
+	}
+	profile.AuthType = r.AuthType
+	profile.Username = strings.TrimSpace(r.Username)
+	profile.SecretEnv = r.SecretEnv
+	profile.CredentialHosts = strings.Join(r.CredentialHosts, ",")
+	profile.ProxyURL = r.ProxyURL
+	profile.TimeoutSeconds = r.TimeoutSeconds
+	profile.InsecureSkipVerify = r.InsecureSkipVerify
+	profile.MinTLSVersion = r.MinTLSVersion
+	profile.RedirectPolicy = r.RedirectPolicy
+	profile.MaxRedirects = r.MaxRedirects
+}
+
+// newHTTPProfileResponse convierte un perfil en su representación pública
+func newHTTPProfileResponse(profile *domain.HTTPProfile) httpProfileResponse {
+	headers := map[string]string{}
+	if profile.Headers != nil {
+		_ = json.Unmarshal([]byte(*profile.Headers), &headers)
+	}
+	credentialHosts := []string{}
+	if profile.CredentialHosts != "" {
+		credentialHosts = strings.Split(profile.CredentialHosts, ",")
+	}
+	return httpProfileResponse{
+		ID:                 profile.ID,
+		Name:               profile.Name,
+		UserAgent:          profile.UserAgent,
+		Headers:            headers,
+		AuthType:           profile.AuthType,
+		Username:           profile.Username,
+		SecretEnv:          profile.SecretEnv,
+		SecretConfigured:   domain.IsSecretEnv(profile.SecretEnv) && os.Getenv(profile.SecretEnv) != "",
+		CredentialHosts:    credentialHosts,
+		ProxyURL:           profile.ProxyURL,
+		TimeoutSeconds:     profile.TimeoutSeconds,
+		InsecureSkipVerify: profile.InsecureSkipVerify,
+		MinTLSVersion:      profile.MinTLSVersion,
+		RedirectPolicy:     profile.RedirectPolicy,
+		MaxRedirects:       profile.MaxRedirects,
+		UpdatedAt:          profile.UpdatedAt,
+	}
+}
+
+// GET /api/http-profiles - Listar perfiles HTTP
+func (h *Handler) ListHTTPProfilesHandler(c *gin.Context) {
+	profiles, err := h.HTTPProfileRepo.ListAll(c.Request.Context())
+	if err != nil {
+		utils.AppError("HTTP_PROFILES", "Error al listar perfiles", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener perfiles HTTP"})
+		return
+	}
+
+	response := make([]httpProfileResponse, 0, len(profiles))
+	for i := range profiles {
+		response = append(response, newHTTPProfileResponse(&profiles[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /api/http-profiles - Crear perfil HTTP
+func (h *Handler) CreateHTTPProfileHandler(c *gin.Context) {
+	var req httpProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile := &domain.HTTPProfile{}
+	req.applyTo(profile)
+	if err := h.HTTPProfileRepo.Create(c.Request.Context(), profile); err != nil {
+		utils.AppError("HTTP_PROFILES", "Error al crear perfil", err, map[string]interface{}{
+			"name": req.Name,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el perfil: " + err.Error()})
+		return
+	}
+
+	utils.AppInfo("HTTP_PROFILES", "Perfil HTTP creado", map[string]interface{}{
+		"id":   profile.ID,
+		"name": profile.Name,
+	})
+	c.JSON(http.StatusOK, newHTTPProfileResponse(profile))
+}
+
+// PUT /api/http-profiles/:id - Actualizar perfil HTTP
+func (h *Handler) UpdateHTTPProfileHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req httpProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	profile, err := h.HTTPProfileRepo.FindByID(ctx, uint(id))
+	if err != nil || profile == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Perfil no encontrado"})
+		return
+	}
+
+	req.applyTo(profile)
+	if err := h.HTTPProfileRepo.Update(ctx, profile); err != nil {
+		utils.AppError("HTTP_PROFILES", "Error al actualizar perfil", err, map[string]interface{}{
+			"id": profile.ID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el perfil: " + err.Error()})
+		return
+	}
+	h.HTTPProfileClients.Invalidate(profile.ID)
+
+	utils.AppInfo("HTTP_PROFILES", "Perfil HTTP actualizado", map[string]interface{}{
+		"id":   profile.ID,
+		"name": profile.Name,
+	})
+	c.JSON(http.StatusOK, newHTTPProfileResponse(profile))
+}
+
+// DELETE /api/http-profiles/:id - Eliminar perfil HTTP (las fuentes que lo usaban quedan sin perfil)
+func (h *Handler) DeleteHTTPProfileHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := h.HTTPProfileRepo.Delete(c.Request.Context(), uint(id)); err != nil {
+		utils.AppError("HTTP_PROFILES", "Error al eliminar perfil", err, map[string]interface{}{
+			"id": id,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el perfil"})
+		return
+	}
+	h.HTTPProfileClients.Invalidate(uint(id))
+
+	utils.AppInfo("HTTP_PROFILES", "Perfil HTTP eliminado", map[string]interface{}{
+		"id": id,
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Perfil eliminado"})
+}
+
+// PUT /api/sources/:id/http-profile - Asociar (o quitar con null) el perfil HTTP de una fuente
+func (h *Handler) UpdateSourceHTTPProfileHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req struct {
+		HTTPProfileID *uint `json:"httpProfileId"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	source, err := h.SourceRepo.FindByID(ctx, uint(id))
+	if err != nil || source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+		return
+	}
+
+	if _, err := h.loadHTTPProfile(ctx, req.HTTPProfileID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source.HTTPProfileID = req.HTTPProfileID
+	source.HTTPProfile = nil
+	if err := h.SourceRepo.Update(ctx, source); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la fuente"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Perfil HTTP actualizado",
+		"httpProfileId": source.HTTPProfileID,
+	})
+}
+
+// loadHTTPProfile obtiene el perfil indicado (nil si no se indica ninguno)
+func (h *Handler) loadHTTPProfile(ctx context.Context, id *uint) (*domain.HTTPProfile, error) {
+	if id == nil {
+		return nil, nil
+	}
+	profile, err := h.HTTPProfileRepo.FindByID(ctx, *id)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener el perfil HTTP: %w", err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("perfil HTTP no encontrado")
+	}
+	return profile, nil
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"reflect"
+	"testing"
+
+	"dailynews/internal/domain"
+)
+
+func TestHTTPProfileRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     httpProfileRequest
+		wantErr bool
+	}{
+		{"mínimo", httpProfileRequest{Name: "Medio"}, false},
+		{"bearer con variable permitida", httpProfileRequest{Name: "Medio", AuthType: "Bearer", SecretEnv: "DAILYNEWS_SECRET_MEDIO"}, false},
+		{"autenticación sin variable", httpProfileRequest{Name: "Medio", AuthType: "basic"}, true},
+		{"secretEnv fuera de la lista", httpProfileRequest{Name: "Medio", AuthType: "bearer", SecretEnv: "DB_PASSWORD"}, true},
+		{"solo el prefijo", httpProfileRequest{Name: "Medio", SecretEnv: "DAILYNEWS_SECRET_"}, true},
+		{"cabecera con variable permitida", httpProfileRequest{Name: "Medio", Headers: map[string]string{"Cookie": "s=${DAILYNEWS_SECRET_COOKIE}"}}, false},
+		{"cabecera con variable fuera de la lista", httpProfileRequest{Name: "Medio", Headers: map[string]string{"X": "${DB_PASSWORD}"}}, true},
+		{"proxy con variable fuera de la lista", httpProfileRequest{Name: "Medio", ProxyURL: "http://${HOME}@proxy:3128"}, true},
+		{"hosts de credenciales", httpProfileRequest{Name: "Medio", CredentialHosts: []string{"api.medio.com", "*.cdn-medio.net"}}, false},
+		{"host con esquema", httpProfileRequest{Name: "Medio", CredentialHosts: []string{"https://api.medio.com"}}, true},
+		{"comodín en medio", httpProfileRequest{Name: "Medio", CredentialHosts: []string{"api.*.com"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPProfileCredentialHostsRoundTrip(t *testing.T) {
+	req := httpProfileRequest{Name: "Medio", CredentialHosts: []string{" API.medio.com ", "", "*.cdn-medio.net"}}
+	if err := req.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	profile := &domain.HTTPProfile{}
+	req.applyTo(profile)
+	if profile.CredentialHosts != "api.medio.com,*.cdn-medio.net" {
+		t.Errorf("CredentialHosts = %q", profile.CredentialHosts)
+	}
+	if got := newHTTPProfileResponse(profile).CredentialHosts; !reflect.DeepEqual(got, []string{"api.medio.com", "*.cdn-medio.net"}) {
+		t.Errorf("respuesta = %v", got)
+	}
+	if got := newHTTPProfileResponse(&domain.HTTPProfile{}).CredentialHosts; got == nil || len(got) != 0 {
+		t.Errorf("sin hosts = %#v, want lista vacía", got)
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/sources/:id/image-placeholders - Imágenes genéricas (logos, marcadores) de una fuente
+func (h *Handler) ListImagePlaceholdersHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	placeholders, err := h.ImagePlaceholderRepo.ListBySource(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener las imágenes genéricas"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"placeholders": placeholders})
+}
+
+// POST /api/sources/:id/image-placeholders - Añadir una imagen genérica a la fuente.
+// Body: { "imageUrl": "..." } (se descarga para calcular su hash) o { "hash": "..." } (dHash en hexadecimal)
+func (h *Handler) AddImagePlaceholderHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req struct {
+		ImageURL string `json:"imageUrl"`
+		Hash     string `json:"hash"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON inválido"})
+		return
+	}
+	ctx := c.Request.Context()
+	source, err := h.SourceRepo.FindByID(ctx, uint(id))
+	if err != nil || source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+		return
+	}
+
+	hash := strings.ToLower(strings.TrimSpace(req.Hash))
+	imageURL := strings.TrimSpace(req.ImageURL)
+	if hash == "" {
+		if imageURL == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere 'imageUrl' o 'hash'"})
+			return
+		}
+		// Con el perfil HTTP de la fuente, como en la extracción
+		validation, err := h.ImageDownloader.InspectImage(domain.WithHTTPProfile(ctx, source.HTTPProfile, source.RSSURL), imageURL)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "No se pudo descargar la imagen: " + err.Error()})
+			return
+		}
+		if validation.PHash == "" {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No se pudo calcular el hash de la imagen", "reason": validation.Reason})
+			return
+		}
+		hash = validation.PHash
+	} else if len(hash) != 16 || domain.ImageHashDistance(hash, hash) != 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hash debe ser un dHash de 16 caracteres hexadecimales"})
+		return
+	}
+
+	placeholder := &domain.ImagePlaceholder{SourceID: source.ID, Hash: hash, ImageURL: imageURL}
+	if err := h.ImagePlaceholderRepo.Save(ctx, placeholder); err != nil {
+		utils.AppError("IMAGE_PLACEHOLDER", "Error al guardar la imagen genérica", err, map[string]interface{}{
+			"source_id": source.ID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al guardar la imagen genérica"})
+		return
+	}
+
+	utils.AppInfo("IMAGE_PLACEHOLDER", "Imagen genérica añadida", map[string]interface{}{
+		"source_id": source.ID,
+		"hash":      hash,
+	})
+	c.JSON(http.StatusOK, placeholder)
+}
+
+// DELETE /api/image-placeholders/:id - Quitar una imagen genérica (por ejemplo, aprendida por error)
+func (h *Handler) DeleteImagePlaceholderHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	if err := h.ImagePlaceholderRepo.Delete(c.Request.Context(), uint(id)); err != nil {
+		utils.AppError("IMAGE_PLACEHOLDER", "Error al eliminar la imagen genérica", err, map[string]interface{}{
+			"id": id,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la imagen genérica"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Imagen genérica eliminada"})
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"image/png"
+
+	"dailynews/pkg/imaging"
+)
+
+// Tamaño de la vista previa generada a partir del BlurHash: el navegador la escala al de la tarjeta
+const (
+	imagePreviewWidth  = 16
+	imagePreviewHeight = 9
+)
+
+// blurHashDataURI convierte un BlurHash en una imagen PNG diminuta como data URI, para usarla de fondo
+// mientras carga la imagen real sin depender de JavaScript ("" si no hay BlurHash o no es válido)
+func blurHashDataURI(hash string) template.URL {
+	if hash == "" {
+		return ""
+	}
+	img, err := imaging.DecodeBlurHash(hash, imagePreviewWidth, imagePreviewHeight)
+	if err != nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	// Generado en el servidor a partir de un PNG propio: es seguro marcarlo como URL de confianza
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
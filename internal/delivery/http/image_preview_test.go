@@ -0,0 +1,61 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestBlurHashDataURI(t *testing.T) {
+	tests := []struct {
+		name  string
+		hash  string
+		valid bool
+	}{
+		{"válido", "LEHV6nWB2yk8pyo0adR*.7kCMdnj", true},
+		{"vacío", "", false},
+		{"inválido", "no-es-blurhash", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := string(blurHashDataURI(tt.hash))
+			if !tt.valid {
+				if uri != "" {
+					t.Errorf("blurHashDataURI = %q, want vacío", uri)
+				}
+				return
+			}
+			const prefix = "data:image/png;base64,"
+			if !strings.HasPrefix(uri, prefix) {
+				t.Fatalf("blurHashDataURI = %q", uri)
+			}
+			data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, prefix))
+			if err != nil {
+				t.Fatalf("base64 inválido: %v", err)
+			}
+			cfg, err := png.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("PNG inválido: %v", err)
+			}
+			if cfg.Width != imagePreviewWidth || cfg.Height != imagePreviewHeight {
+				t.Errorf("vista previa de %dx%d, want %dx%d", cfg.Width, cfg.Height, imagePreviewWidth, imagePreviewHeight)
+			}
+		})
+	}
+}
+
+func TestNewsCardPreview(t *testing.T) {
+	preview := blurHashDataURI("LEHV6nWB2yk8pyo0adR*.7kCMdnj")
+	img := renderNewsCard(t, NewsData{Title: "Noticia", Image: "/images/cache/ab/abcd.webp", ImagePreview: preview})
+	// La data URI se marca como segura: html/template no debe sustituirla por #ZgotmplZ
+	if !strings.Contains(html.UnescapeString(img), "background-image: url('"+string(preview)+"')") || strings.Contains(img, "ZgotmplZ") {
+		t.Errorf("la imagen no lleva la vista previa de fondo:\n%s", img)
+	}
+
+	if img := renderNewsCard(t, NewsData{Title: "Noticia", Image: "/images/cache/ab/abcd.webp"}); strings.Contains(img, "background-image") {
+		t.Errorf("la imagen sin BlurHash lleva fondo:\n%s", img)
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/image-validations?url=... - Resultado guardado de la validación de una imagen
+func (h *Handler) GetImageValidationHandler(c *gin.Context) {
+	imageURL := strings.TrimSpace(c.Query("url"))
+	if imageURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parámetro 'url' requerido"})
+		return
+	}
+
+	validation, err := h.ImageValidationRepo.FindByURLHash(c.Request.Context(), domain.HashURL(imageURL))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error consultando la caché de validaciones"})
+		return
+	}
+	if validation == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "La imagen no está en la caché de validaciones"})
+		return
+	}
+	c.JSON(http.StatusOK, validation)
+}
+
+// DELETE /api/image-validations - Invalidar la caché de validaciones de imágenes.
+// Con ?url=... solo esa imagen; con ?status=invalid solo las rechazadas; sin parámetros, todas.
+func (h *Handler) InvalidateImageValidationsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	imageURL := strings.TrimSpace(c.Query("url"))
+	status := c.Query("status")
+	if status != "" && status != "invalid" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status solo admite 'invalid'"})
+		return
+	}
+
+	var removed int64
+	var err error
+	if imageURL != "" {
+		removed, err = h.ImageValidationRepo.Delete(ctx, domain.HashURL(imageURL))
+	} else {
+		removed, err = h.ImageValidationRepo.DeleteAll(ctx, status == "invalid")
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error invalidando la caché de validaciones"})
+		return
+	}
+
+	utils.AppInfo("IMAGE_VALIDATION", "Caché de validaciones invalidada", map[string]interface{}{
+		"url":     imageURL,
+		"status":  status,
+		"removed": removed,
+	})
+	c.JSON(http.StatusOK, gin.H{"success": true, "removed": removed})
+}
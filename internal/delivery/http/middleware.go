@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +13,18 @@ func SetupMiddlewares(router *gin.Engine) {
 	router.Use(corsMiddleware())
 	router.Use(loggingMiddleware())
 	router.Use(timeoutMiddleware(30 * time.Second))
+	router.Use(imageCacheHeadersMiddleware())
+}
+
+// imageCacheHeadersMiddleware marca las imágenes de la caché local como inmutables: su nombre es
+// el hash del contenido, así que una URL nunca cambia de imagen
+func imageCacheHeadersMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, "/images/cache/") {
+			c.Writer.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		}
+		c.Next()
+	}
 }
 
 func corsMiddleware() gin.HandlerFunc {
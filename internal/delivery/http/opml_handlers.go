@@ -0,0 +1,284 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxOPMLSize limita el tamaño del documento OPML que se acepta en la importación (2MB)
+const maxOPMLSize = 2 * 1024 * 1024
+
+// opmlDocument representa un documento OPML 2.0
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// opmlOutline es una fuente (o una carpeta de fuentes) del OPML.
+// category y language son los códigos internos; el resto de atributos describen las fuentes que no son RSS.
+type opmlOutline struct {
+	Text         string        `xml:"text,attr,omitempty"`
+	Title        string        `xml:"title,attr,omitempty"`
+	Type         string        `xml:"type,attr,omitempty"`
+	XMLURL       string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL      string        `xml:"htmlUrl,attr,omitempty"`
+	Category     string        `xml:"category,attr,omitempty"`
+	Language     string        `xml:"language,attr,omitempty"`
+	SourceType   string        `xml:"sourceType,attr,omitempty"`
+	ItemSelector string        `xml:"itemSelector,attr,omitempty"`
+	TitleField   string        `xml:"titleField,attr,omitempty"`
+	ImageField   string        `xml:"imageField,attr,omitempty"`
+	LinkField    string        `xml:"linkField,attr,omitempty"`
+	DateField    string        `xml:"dateField,attr,omitempty"`
+	FetchOptions string        `xml:"fetchOptions,attr,omitempty"`
+	Outlines     []opmlOutline `xml:"outline"`
+}
+
+// opmlImportResult es el resultado de importar una fuente del OPML
+type opmlImportResult struct {
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Category string `json:"category"`
+	Language string `json:"language"`
+	Status   string `json:"status"` // "imported", "duplicate", "invalid" o "error"
+	Pattern  string `json:"pattern,omitempty"`
+	ID       uint   `json:"id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// GET /api/sources/export.opml - Exportar todas las fuentes como OPML
+func (h *Handler) ExportSourcesOPMLHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sources, err := h.SourceRepo.ListAll(ctx)
+	if err != nil {
+		utils.AppError("EXPORT_OPML", "Error al obtener fuentes", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener fuentes"})
+		return
+	}
+
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       "DailyNews - fuentes",
+			DateCreated: time.Now().Format(time.RFC1123Z),
+		},
+	}
+	for _, src := range sources {
+		outline := opmlOutline{
+			Text:     src.SourceName,
+			Title:    src.SourceName,
+			Type:     "rss",
+			XMLURL:   src.RSSURL,
+			Category: src.News.Code,
+			Language: src.Lang.Code,
+		}
+		if src.GetType() != domain.SourceTypeRSS {
+			outline.SourceType = src.GetType()
+			outline.ItemSelector = derefString(src.ItemSelector)
+			outline.TitleField = derefString(src.TitleField)
+			outline.ImageField = derefString(src.ImageField)
+			outline.LinkField = derefString(src.LinkField)
+			outline.DateField = derefString(src.CampoFecha)
+			outline.FetchOptions = derefString(src.FetchOptions)
+		}
+		doc.Body.Outlines = append(doc.Body.Outlines, outline)
+	}
+
+	output, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		utils.AppError("EXPORT_OPML", "Error al generar OPML", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al generar OPML"})
+		return
+	}
+
+	utils.AppInfo("EXPORT_OPML", "Fuentes exportadas", map[string]interface{}{
+		"total_sources": len(sources),
+	})
+
+	c.Header("Content-Disposition", `attachment; filename="dailynews-sources.opml"`)
+	c.Data(http.StatusOK, "text/x-opml; charset=utf-8", append([]byte(xml.Header), output...))
+}
+
+// POST /api/sources/import - Importar fuentes desde un OPML (campo de formulario "file" o cuerpo de la petición).
+// Los parámetros de query "category" y "language" se usan para las fuentes que no traen esos atributos.
+func (h *Handler) ImportSourcesOPMLHandler(c *gin.Context) {
+	var reader io.Reader = c.Request.Body
+	if file, _, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		reader = file
+	}
+
+	var doc opmlDocument
+	if err := xml.NewDecoder(io.LimitReader(reader, maxOPMLSize)).Decode(&doc); err != nil {
+		utils.AppError("IMPORT_OPML", "Error al parsear OPML", err, nil)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "OPML inválido: " + err.Error()})
+		return
+	}
+
+	defaultCategory := strings.TrimSpace(c.Query("category"))
+	defaultLanguage := strings.TrimSpace(c.Query("language"))
+
+	var outlines []opmlOutline
+	flattenOPMLOutlines(doc.Body.Outlines, defaultCategory, defaultLanguage, &outlines)
+
+	utils.AppInfo("IMPORT_OPML", "Importación de OPML iniciada", map[string]interface{}{
+		"total_feeds": len(outlines),
+	})
+
+	ctx := c.Request.Context()
+	results := make([]opmlImportResult, 0, len(outlines))
+	counts := map[string]int{}
+	for _, outline := range outlines {
+		result := h.importOPMLOutline(ctx, outline)
+		counts[result.Status]++
+		results = append(results, result)
+	}
+
+	utils.AppInfo("IMPORT_OPML", "Importación de OPML completada", map[string]interface{}{
+		"total_feeds": len(outlines),
+		"imported":    counts["imported"],
+		"duplicates":  counts["duplicate"],
+		"invalid":     counts["invalid"],
+		"errors":      counts["error"],
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":      len(outlines),
+		"imported":   counts["imported"],
+		"duplicates": counts["duplicate"],
+		"invalid":    counts["invalid"],
+		"errors":     counts["error"],
+		"results":    results,
+	})
+}
+
+// flattenOPMLOutlines recorre las carpetas del OPML y devuelve las fuentes, heredando categoría e idioma del padre
+func flattenOPMLOutlines(outlines []opmlOutline, category, language string, out *[]opmlOutline) {
+	for _, outline := range outlines {
+		if strings.TrimSpace(outline.Category) == "" {
+			outline.Category = category
+		}
+		if strings.TrimSpace(outline.Language) == "" {
+			outline.Language = language
+		}
+		if strings.TrimSpace(outline.XMLURL) != "" {
+			*out = append(*out, outline)
+		}
+		flattenOPMLOutlines(outline.Outlines, outline.Category, outline.Language, out)
+	}
+}
+
+// importOPMLOutline valida, detecta el patrón y guarda una fuente del OPML
+func (h *Handler) importOPMLOutline(ctx context.Context, outline opmlOutline) opmlImportResult {
+	result := opmlImportResult{
+		Title:    strings.TrimSpace(outline.Title),
+		URL:      strings.TrimSpace(outline.XMLURL),
+		Category: strings.TrimSpace(outline.Category),
+		Language: strings.TrimSpace(outline.Language),
+	}
+	if result.Title == "" {
+		result.Title = strings.TrimSpace(outline.Text)
+	}
+	if result.Title == "" {
+		result.Title = result.URL
+	}
+	fail := func(status, msg string) opmlImportResult {
+		result.Status = status
+		result.Error = msg
+		return result
+	}
+
+	if result.Category == "" || result.Language == "" {
+		return fail("invalid", "la fuente no indica categoría e idioma")
+	}
+	category, err := h.getCategoryByCode(ctx, result.Category)
+	if err != nil {
+		return fail("invalid", "categoría no válida")
+	}
+	lang, err := h.CountryRepo.FindByCode(ctx, result.Language)
+	if err != nil {
+		return fail("invalid", "idioma no válido")
+	}
+
+	exists, err := h.SourceRepo.ExistsByURLCategoryLang(ctx, result.URL, category.ID, lang.ID)
+	if err != nil {
+		return fail("error", fmt.Sprintf("error al validar duplicado: %v", err))
+	}
+	if exists {
+		return fail("duplicate", "la fuente ya existe para la misma categoría e idioma")
+	}
+
+	newSource := &domain.NewsSource{
+		SourceName: result.Title,
+		RSSURL:     result.URL,
+		NewsID:     category.ID,
+		LangID:     lang.ID,
+		IsActive:   true,
+		UserAdded:  true,
+	}
+
+	custom := customSourceFields{
+		Type:         outline.SourceType,
+		ItemSelector: outline.ItemSelector,
+		TitleField:   outline.TitleField,
+		ImageField:   outline.ImageField,
+		LinkField:    outline.LinkField,
+		DateField:    outline.DateField,
+	}
+	if options := strings.TrimSpace(outline.FetchOptions); options != "" {
+		custom.Options = []byte(options)
+	}
+
+	if custom.isCustom() {
+		custom.applyTo(newSource)
+		if _, _, err := h.testCustomSource(ctx, newSource); err != nil {
+			return fail("invalid", "no se pudieron extraer noticias: "+err.Error())
+		}
+		result.Pattern = newSource.SourceType
+	} else {
+		bestPattern, err := h.detectBestPattern(ctx, result.URL)
+		if err != nil {
+			return fail("invalid", "no se detectó un patrón válido: "+err.Error())
+		}
+		newSource.Filter = &bestPattern
+		result.Pattern = bestPattern
+	}
+
+	if err := h.SourceRepo.Create(ctx, newSource); err != nil {
+		return fail("error", "error al guardar la fuente: "+err.Error())
+	}
+
+	result.Status = "imported"
+	result.ID = newSource.ID
+	return result
+}
+
+// derefString devuelve el valor de un *string o "" si es nil
+func derefString(ptr *string) string {
+	if ptr != nil {
+		return *ptr
+	}
+	return ""
+}
@@ -0,0 +1,314 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memorySourceRepo es un NewsSourceRepository en memoria para los tests de handlers
+type memorySourceRepo struct {
+	sources []domain.NewsSource
+}
+
+func (r *memorySourceRepo) FindByID(ctx context.Context, id uint) (*domain.NewsSource, error) {
+	for i := range r.sources {
+		if r.sources[i].ID == id {
+			return &r.sources[i], nil
+		}
+	}
+	return nil, fmt.Errorf("fuente %d no encontrada", id)
+}
+
+func (r *memorySourceRepo) FindActiveByLangAndCategory(ctx context.Context, langID, categoryID uint) ([]domain.NewsSource, error) {
+	var out []domain.NewsSource
+	for _, s := range r.sources {
+		if s.IsActive && s.LangID == langID && s.NewsID == categoryID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *memorySourceRepo) ListActive(ctx context.Context) ([]domain.NewsSource, error) {
+	var out []domain.NewsSource
+	for _, s := range r.sources {
+		if s.IsActive {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *memorySourceRepo) ListAll(ctx context.Context) ([]domain.NewsSource, error) {
+	return append([]domain.NewsSource(nil), r.sources...), nil
+}
+
+func (r *memorySourceRepo) Create(ctx context.Context, source *domain.NewsSource) error {
+	source.ID = uint(len(r.sources) + 1)
+	r.sources = append(r.sources, *source)
+	return nil
+}
+
+func (r *memorySourceRepo) Update(ctx context.Context, source *domain.NewsSource) error {
+	for i := range r.sources {
+		if r.sources[i].ID == source.ID {
+			r.sources[i] = *source
+			return nil
+		}
+	}
+	return fmt.Errorf("fuente %d no encontrada", source.ID)
+}
+
+func (r *memorySourceRepo) Delete(ctx context.Context, id uint) error {
+	for i := range r.sources {
+		if r.sources[i].ID == id {
+			r.sources = append(r.sources[:i], r.sources[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *memorySourceRepo) ExistsByURLCategoryLang(ctx context.Context, rssURL string, categoryID, langID uint) (bool, error) {
+	for _, s := range r.sources {
+		if s.RSSURL == rssURL && s.NewsID == categoryID && s.LangID == langID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryCategoryRepo []domain.Category
+
+func (r memoryCategoryRepo) FindByCode(ctx context.Context, code string) (*domain.Category, error) {
+	for i := range r {
+		if r[i].Code == code {
+			return &r[i], nil
+		}
+	}
+	return nil, fmt.Errorf("categoría '%s' no encontrada", code)
+}
+
+func (r memoryCategoryRepo) ListAll(ctx context.Context) ([]domain.Category, error) {
+	return r, nil
+}
+
+type memoryCountryRepo []domain.Country
+
+func (r memoryCountryRepo) FindByCode(ctx context.Context, code string) (*domain.Country, error) {
+	for i := range r {
+		if r[i].Code == code {
+			return &r[i], nil
+		}
+	}
+	return nil, fmt.Errorf("idioma '%s' no encontrado", code)
+}
+
+func (r memoryCountryRepo) ListAll(ctx context.Context) ([]domain.Country, error) {
+	return r, nil
+}
+
+// patternRSSFetcher solo devuelve noticias con el patrón indicado
+type patternRSSFetcher struct {
+	pattern string
+}
+
+func (f patternRSSFetcher) Fetch(ctx context.Context, url string, filter string, titleField, imageField, linkField, dateField string) ([]domain.NewsItem, error) {
+	if filter != f.pattern {
+		return nil, fmt.Errorf("patrón %s sin noticias", filter)
+	}
+	return testNewsItems(url), nil
+}
+
+// staticSourceFetcher devuelve siempre noticias válidas para las fuentes personalizadas
+type staticSourceFetcher struct{}
+
+func (staticSourceFetcher) FetchSource(ctx context.Context, source *domain.NewsSource) ([]domain.NewsItem, error) {
+	return testNewsItems(source.RSSURL), nil
+}
+
+func testNewsItems(base string) []domain.NewsItem {
+	now := time.Now().Add(-time.Hour)
+	return []domain.NewsItem{
+		{Title: "Primera noticia de prueba", Link: base + "/1", PubDate: now},
+		{Title: "Segunda noticia de prueba", Link: base + "/2", PubDate: now},
+	}
+}
+
+func newOPMLTestHandler(sources *memorySourceRepo) *Handler {
+	return &Handler{
+		SourceRepo:    sources,
+		CategoryRepo:  memoryCategoryRepo{{ID: 1, Code: "technology"}, {ID: 2, Code: "sports"}},
+		CountryRepo:   memoryCountryRepo{{ID: 1, Code: "es"}, {ID: 2, Code: "eng"}},
+		RSSFetcher:    patternRSSFetcher{pattern: "patron2"},
+		SourceFetcher: staticSourceFetcher{},
+		Config:        &config.Config{},
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestOPMLExportImportRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := &memorySourceRepo{sources: []domain.NewsSource{
+		{
+			ID: 1, SourceName: "Tecnología & Co", RSSURL: "https://tech.example/rss?a=1&b=2",
+			NewsID: 1, News: domain.Category{ID: 1, Code: "technology"},
+			LangID: 1, Lang: domain.Country{ID: 1, Code: "es"},
+			Filter: strPtr("patron1"),
+		},
+		{
+			ID: 2, SourceName: "Deportes HTML", RSSURL: "https://sports.example/portada",
+			NewsID: 2, News: domain.Category{ID: 2, Code: "sports"},
+			LangID: 2, Lang: domain.Country{ID: 2, Code: "eng"},
+			SourceType: domain.SourceTypeHTML, ItemSelector: strPtr("article.item"),
+			TitleField: strPtr("h2 a"), ImageField: strPtr("img"), LinkField: strPtr("h2 a"),
+			CampoFecha: strPtr("time@datetime"), FetchOptions: strPtr(`{"headers":{"X-Key":"${KEY}"}}`),
+		},
+	}}
+
+	exported := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(exported)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/sources/export.opml", nil)
+	newOPMLTestHandler(original).ExportSourcesOPMLHandler(c)
+	if exported.Code != http.StatusOK {
+		t.Fatalf("export status = %d: %s", exported.Code, exported.Body.String())
+	}
+	opml := exported.Body.String()
+
+	target := &memorySourceRepo{}
+	handler := newOPMLTestHandler(target)
+	importOPML := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/import", strings.NewReader(opml))
+		handler.ImportSourcesOPMLHandler(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("import status = %d: %s", w.Code, w.Body.String())
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("respuesta de importación inválida: %v", err)
+		}
+		return body
+	}
+
+	if body := importOPML(); body["imported"] != float64(2) {
+		t.Fatalf("imported = %v: %v", body["imported"], body)
+	}
+	if len(target.sources) != 2 {
+		t.Fatalf("got %d sources, want 2", len(target.sources))
+	}
+
+	rss := target.sources[0]
+	if rss.SourceName != "Tecnología & Co" || rss.RSSURL != "https://tech.example/rss?a=1&b=2" || rss.NewsID != 1 || rss.LangID != 1 {
+		t.Errorf("fuente RSS importada = %+v", rss)
+	}
+	// El patrón de las fuentes RSS no viaja en el OPML: se vuelve a detectar
+	if derefString(rss.Filter) != "patron2" || rss.GetType() != domain.SourceTypeRSS {
+		t.Errorf("patrón = %q, tipo = %q", derefString(rss.Filter), rss.GetType())
+	}
+
+	html := target.sources[1]
+	if html.GetType() != domain.SourceTypeHTML || html.NewsID != 2 || html.LangID != 2 {
+		t.Errorf("fuente HTML importada = %+v", html)
+	}
+	gotFields := []string{derefString(html.ItemSelector), derefString(html.TitleField), derefString(html.ImageField),
+		derefString(html.LinkField), derefString(html.CampoFecha), derefString(html.FetchOptions)}
+	wantFields := []string{"article.item", "h2 a", "img", "h2 a", "time@datetime", `{"headers":{"X-Key":"${KEY}"}}`}
+	if !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf("campos de extracción = %q, want %q", gotFields, wantFields)
+	}
+
+	// Una segunda importación del mismo OPML solo encuentra duplicados
+	if body := importOPML(); body["duplicates"] != float64(2) || body["imported"] != float64(0) {
+		t.Errorf("reimportación = %v", body)
+	}
+	if len(target.sources) != 2 {
+		t.Errorf("got %d sources after reimport, want 2", len(target.sources))
+	}
+}
+
+func TestImportSourcesOPMLInvalidDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/sources/import", strings.NewReader("no es xml"))
+	newOPMLTestHandler(&memorySourceRepo{}).ImportSourcesOPMLHandler(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestImportOPMLOutlineValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		outline opmlOutline
+		status  string
+	}{
+		{"sin categoría", opmlOutline{XMLURL: "https://a.example/rss", Language: "es"}, "invalid"},
+		{"categoría desconocida", opmlOutline{XMLURL: "https://a.example/rss", Category: "cocina", Language: "es"}, "invalid"},
+		{"idioma desconocido", opmlOutline{XMLURL: "https://a.example/rss", Category: "technology", Language: "fr"}, "invalid"},
+		{"html personalizado", opmlOutline{XMLURL: "https://a.example", Category: "technology", Language: "es", SourceType: "html"}, "imported"},
+		{"rss válido", opmlOutline{XMLURL: "https://b.example/rss", Category: "technology", Language: "es"}, "imported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newOPMLTestHandler(&memorySourceRepo{})
+			result := handler.importOPMLOutline(context.Background(), tt.outline)
+			if result.Status != tt.status {
+				t.Errorf("status = %q (%s), want %q", result.Status, result.Error, tt.status)
+			}
+			if result.Title != tt.outline.XMLURL {
+				t.Errorf("title = %q, want the URL when the outline has no text", result.Title)
+			}
+		})
+	}
+}
+
+func TestFlattenOPMLOutlines(t *testing.T) {
+	outlines := []opmlOutline{
+		{Text: "Tecnología", Category: "technology", Outlines: []opmlOutline{
+			{XMLURL: "https://a.example/rss"},
+			{XMLURL: "https://b.example/rss", Language: "eng"},
+			{Text: "Subcarpeta", Language: "pt", Outlines: []opmlOutline{
+				{XMLURL: "https://c.example/rss", Category: "science"},
+			}},
+		}},
+		{XMLURL: "https://d.example/rss"},
+		{Text: "Carpeta vacía"},
+	}
+
+	var got []opmlOutline
+	flattenOPMLOutlines(outlines, "general", "es", &got)
+
+	want := []struct{ url, category, language string }{
+		{"https://a.example/rss", "technology", "es"},
+		{"https://b.example/rss", "technology", "eng"},
+		{"https://c.example/rss", "science", "pt"},
+		{"https://d.example/rss", "general", "es"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d outlines, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].XMLURL != w.url || got[i].Category != w.category || got[i].Language != w.language {
+			t.Errorf("outline %d = %s/%s/%s, want %s/%s/%s", i, got[i].XMLURL, got[i].Category, got[i].Language, w.url, w.category, w.language)
+		}
+	}
+}
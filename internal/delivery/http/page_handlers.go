@@ -2,8 +2,10 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"html/template"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -72,15 +74,21 @@ type CategoryData struct {
 }
 
 type NewsData struct {
-	ID           uint   `json:"id"`
-	Title        string `json:"title"`
-	Link         string `json:"link"`
-	Image        string `json:"image"`
-	SourceName   string `json:"source_name"`
-	CategoryName string `json:"category_name"`
-	Language     string `json:"language"`
-	PubDate      string `json:"pub_date"`
-	AuthorName   string `json:"author_name,omitempty"`
+	ID           uint         `json:"id"`
+	Title        string       `json:"title"`
+	Link         string       `json:"link"`
+	Image        string       `json:"image"`
+	ImageSrcset  string       `json:"image_srcset,omitempty"` // Versiones reducidas de la imagen (solo con la caché local)
+	ImageWidth   int          `json:"image_width,omitempty"`  // Dimensiones para reservar el hueco antes de cargarla
+	ImageHeight  int          `json:"image_height,omitempty"`
+	BlurHash     string       `json:"blurhash,omitempty"` // Vista previa difuminada de la imagen (https://blurha.sh)
+	ImagePreview template.URL `json:"-"`                  // BlurHash como data URI para el fondo de la tarjeta
+	SourceName   string       `json:"source_name"`
+	CategoryName string       `json:"category_name"`
+	Language     string       `json:"language"`
+	PubDate      string       `json:"pub_date"`
+	AuthorName   string       `json:"author_name,omitempty"`
+	ReadingTime  int          `json:"reading_time,omitempty"` // Minutos de lectura (solo con texto completo)
 }
 
 type PaginationData struct {
@@ -247,73 +255,96 @@ func (h *Handler) GetUserSourcesHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, userSources)
 }
 
-// detectBestPattern detecta automáticamente el mejor patrón para una URL RSS
-// Implementa detección híbrida: primero intenta patrones con imagen, luego sin imagen
+// detectBestPattern detecta automáticamente el mejor patrón para una URL RSS.
+// Evalúa todos los patrones (con y sin imagen) y elige el de mayor puntuación.
 func (h *Handler) detectBestPattern(ctx context.Context, rssURL string) (string, error) {
 	rssURL = strings.TrimSpace(rssURL)
-	// FASE 1: Probar patrones con imagen (prioridad alta)
-	patternsWithImage := []string{"patron1", "patron2", "patron3"}
-	bestPattern, err := h.testPatternsWithImage(ctx, rssURL, patternsWithImage)
-	if err == nil && bestPattern != "" {
-		return bestPattern, nil
+	reports := h.evaluateRSSPatterns(ctx, rssURL, h.Config.GetMaxDays("", ""))
+	best, err := bestPatternReport(reports)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo detectar un patrón válido para esta URL")
 	}
+	return best.Pattern, nil
+}
 
-	// FASE 2: Probar patrones sin imagen (fallback)
-	patternsWithoutImage := []string{"patron1_no_image", "patron2_no_image", "patron3_no_image"}
-	bestPattern, err = h.testPatternsWithoutImage(ctx, rssURL, patternsWithoutImage)
-	if err == nil && bestPattern != "" {
-		return bestPattern, nil
-	}
+// customSourceFields agrupa la configuración de extracción de las fuentes que no son RSS
+type customSourceFields struct {
+	Type         string          `json:"type"`         // Tipo de fuente ("rss" por defecto, "html", "sitemap", "json")
+	ItemSelector string          `json:"itemSelector"` // Selector de cada noticia (HTML), fragmento de ruta (sitemap) o ruta al array (JSON)
+	TitleField   string          `json:"titleField"`
+	ImageField   string          `json:"imageField"`
+	LinkField    string          `json:"linkField"`
+	DateField    string          `json:"dateField"`
+	Options      json.RawMessage `json:"options"` // Cabeceras y paginación (JSON)
+}
 
-	return "", fmt.Errorf("no se pudo detectar un patrón válido para esta URL")
+// isCustom indica si la petición describe una fuente que no es RSS
+func (f customSourceFields) isCustom() bool {
+	sourceType := strings.TrimSpace(f.Type)
+	return sourceType != "" && sourceType != domain.SourceTypeRSS
 }
 
-// testPatternsWithImage prueba patrones que incluyen extracción de imagen
-func (h *Handler) testPatternsWithImage(ctx context.Context, rssURL string, patterns []string) (string, error) {
-	for _, pattern := range patterns {
-		items, err := h.RSSFetcher.Fetch(ctx, rssURL, pattern, "", "", "", "")
-		if err == nil && len(items) > 0 {
-			validItems := 0
-			for _, item := range items {
-				// Validación completa: título, link, imagen
-				if item.Title != "" && item.Link != "" && item.Image != "" && len(item.Title) > 10 {
-					validItems++
-				}
-			}
+// applyTo copia la configuración de extracción a la fuente
+func (f customSourceFields) applyTo(source *domain.NewsSource) {
+	source.SourceType = strings.TrimSpace(f.Type)
+	source.ItemSelector = optionalString(f.ItemSelector)
+	source.TitleField = optionalString(f.TitleField)
+	source.ImageField = optionalString(f.ImageField)
+	source.LinkField = optionalString(f.LinkField)
+	source.CampoFecha = optionalString(f.DateField)
+	if len(f.Options) > 0 && string(f.Options) != "null" {
+		source.FetchOptions = optionalString(string(f.Options))
+	}
+}
 
-			if validItems >= 2 {
-				return pattern, nil
-			}
-		}
+// patternType describe si la fuente aporta imagen o dependerá de la imagen de fallback
+func (f customSourceFields) patternType() string {
+	if strings.TrimSpace(f.Type) == domain.SourceTypeSitemap {
+		return "image:image del sitemap (fallback si falta)"
+	}
+	if strings.TrimSpace(f.ImageField) == "" {
+		return "sin imagen (requerirá imagen de fallback)"
 	}
-	return "", fmt.Errorf("no se encontró patrón válido con imagen")
+	return "con imagen"
 }
 
-// testPatternsWithoutImage prueba patrones que no incluyen extracción de imagen
-func (h *Handler) testPatternsWithoutImage(ctx context.Context, rssURL string, patterns []string) (string, error) {
-	for _, pattern := range patterns {
-		items, err := h.RSSFetcher.Fetch(ctx, rssURL, pattern, "", "", "", "")
-		if err == nil && len(items) > 0 {
-			validItems := 0
-			for _, item := range items {
-				// Validación sin imagen: solo título y link
-				if item.Title != "" && item.Link != "" && len(item.Title) > 10 {
-					validItems++
-				}
-			}
+// optionalString devuelve nil para cadenas vacías
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
 
-			if validItems >= 2 {
-				return pattern, nil
-			}
+// testCustomSource prueba una fuente que no es RSS con su configuración y cuenta las noticias válidas
+func (h *Handler) testCustomSource(ctx context.Context, source *domain.NewsSource) ([]domain.NewsItem, int, error) {
+	items, err := h.SourceFetcher.FetchSource(ctx, source)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	validItems := 0
+	for _, item := range items {
+		if item.Title != "" && item.Link != "" && len(item.Title) > 10 {
+			validItems++
 		}
 	}
-	return "", fmt.Errorf("no se encontró patrón válido sin imagen")
+	if validItems < 2 {
+		return items, validItems, fmt.Errorf("la configuración solo extrajo %d noticias válidas", validItems)
+	}
+	return items, validItems, nil
 }
 
-// Probar URL RSS con detección automática
+// Probar URL RSS con detección automática.
+// Evalúa todos los patrones candidatos y devuelve un informe por patrón junto al mejor por puntuación.
 func (h *Handler) TestSourceHandler(c *gin.Context) {
 	var req struct {
-		RSSURL string `json:"url" binding:"required"`
+		RSSURL        string `json:"url" binding:"required"`
+		Category      string `json:"category"`      // Opcional: para usar el maxDays de la categoría
+		Language      string `json:"language"`      // Opcional: para usar el maxDays del idioma
+		HTTPProfileID *uint  `json:"httpProfileId"` // Opcional: perfil HTTP con el que probar la fuente
+		customSourceFields
 	}
 
 	// Log de la solicitud recibida
@@ -332,19 +363,37 @@ func (h *Handler) TestSourceHandler(c *gin.Context) {
 
 	// Sanear URL: eliminar espacios en blanco accidentales
 	req.RSSURL = strings.TrimSpace(req.RSSURL)
+	maxDays := h.Config.GetMaxDays(strings.TrimSpace(req.Language), strings.TrimSpace(req.Category))
 
 	utils.AppInfo("TEST_SOURCE", "Datos parseados correctamente", map[string]interface{}{
-		"url": req.RSSURL,
+		"url":      req.RSSURL,
+		"max_days": maxDays,
 	})
 
 	ctx := c.Request.Context()
 
-	// Detectar mejor patrón
-	utils.AppInfo("TEST_SOURCE", "Iniciando detección de patrón", map[string]interface{}{
-		"url": req.RSSURL,
-	})
+	profile, err := h.loadHTTPProfile(ctx, req.HTTPProfileID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx = domain.WithHTTPProfile(ctx, profile, req.RSSURL)
+
+	// Fuentes que no son RSS: se evalúa la configuración de extracción indicada;
+	// las RSS: todos los patrones conocidos
+	var reports []patternReport
+	if req.isCustom() {
+		source := &domain.NewsSource{RSSURL: req.RSSURL}
+		req.applyTo(source)
+		reports = []patternReport{h.evaluateCustomSource(ctx, source, req.patternType(), maxDays)}
+	} else {
+		utils.AppInfo("TEST_SOURCE", "Iniciando detección de patrón", map[string]interface{}{
+			"url": req.RSSURL,
+		})
+		reports = h.evaluateRSSPatterns(ctx, req.RSSURL, maxDays)
+	}
 
-	bestPattern, err := h.detectBestPattern(ctx, req.RSSURL)
+	best, err := bestPatternReport(reports)
 	if err != nil {
 		utils.AppError("TEST_SOURCE", "Error al detectar patrón", err, map[string]interface{}{
 			"url": req.RSSURL,
@@ -352,57 +401,34 @@ func (h *Handler) TestSourceHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "No se pudo detectar un patrón válido para esta URL",
 			"details": err.Error(),
+			"report":  reports,
 		})
 		return
 	}
 
-	utils.AppInfo("TEST_SOURCE", "Patrón detectado exitosamente", map[string]interface{}{
-		"url":     req.RSSURL,
-		"pattern": bestPattern,
-	})
-
-	// Obtener noticias con el patrón detectado
-	items, err := h.RSSFetcher.Fetch(ctx, req.RSSURL, bestPattern, "", "", "", "")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener noticias"})
-		return
-	}
-
-	// Preparar respuesta
 	var sampleTitles []string
-	validCount := 0
-
-	for _, item := range items {
-		if item.Title != "" && item.Link != "" && len(item.Title) > 10 {
-			validCount++
-			if len(sampleTitles) < 3 {
-				sampleTitles = append(sampleTitles, item.Title)
-			}
-		}
-	}
-
-	// Determinar tipo de patrón
-	patternType := "con imagen"
-	if strings.Contains(bestPattern, "no_image") {
-		patternType = "sin imagen (requerirá imagen de fallback)"
+	for _, sample := range best.Samples {
+		sampleTitles = append(sampleTitles, sample.Title)
 	}
 
 	utils.AppInfo("TEST_SOURCE", "Prueba completada exitosamente", map[string]interface{}{
 		"url":          req.RSSURL,
-		"pattern":      bestPattern,
-		"pattern_type": patternType,
-		"valid_items":  validCount,
-		"total_items":  len(items),
-		"sample_count": len(sampleTitles),
+		"pattern":      best.Pattern,
+		"pattern_type": best.PatternType,
+		"score":        best.Score,
+		"valid_items":  best.ValidItems,
+		"total_items":  best.TotalItems,
 	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":          true,
-		"valid_items":      validCount,
-		"total_items":      len(items),
-		"detected_pattern": bestPattern,
-		"pattern_type":     patternType,
+		"valid_items":      best.ValidItems,
+		"total_items":      best.TotalItems,
+		"detected_pattern": best.Pattern,
+		"pattern_type":     best.PatternType,
+		"score":            best.Score,
 		"sample_titles":    sampleTitles,
+		"report":           reports,
 	})
 }
 
@@ -413,6 +439,10 @@ func (h *Handler) AddSourceHandler(c *gin.Context) {
 		Category        string `json:"category" binding:"required"`
 		Language        string `json:"language" binding:"required"`
 		FallbackImageID *uint  `json:"fallbackImageId"` // NUEVO: ID de imagen de fallback
+		HTTPProfileID   *uint  `json:"httpProfileId"`   // Perfil HTTP opcional para la fuente
+		EnrichImages    bool   `json:"enrichImages"`    // Buscar la imagen en la página de la noticia si el feed no trae una válida
+		ExtractContent  bool   `json:"extractContent"`  // Extraer el texto completo de las noticias
+		customSourceFields
 	}
 
 	// Log de la solicitud recibida
@@ -458,6 +488,14 @@ func (h *Handler) AddSourceHandler(c *gin.Context) {
 		return
 	}
 
+	// Validar el perfil HTTP y usarlo también para detectar el patrón
+	profile, err := h.loadHTTPProfile(ctx, req.HTTPProfileID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx = domain.WithHTTPProfile(ctx, profile, req.RSSURL)
+
 	// 1. Verificar duplicado exacto (misma URL + categoría + idioma)
 	exists, err := h.SourceRepo.ExistsByURLCategoryLang(ctx, req.RSSURL, category.ID, lang.ID)
 	if err != nil {
@@ -475,22 +513,35 @@ func (h *Handler) AddSourceHandler(c *gin.Context) {
 		return
 	}
 
-	// 2. Detectar el mejor patrón automáticamente
-	bestPattern, err := h.detectBestPattern(ctx, req.RSSURL)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo procesar esta fuente RSS. Verifica que la URL sea correcta."})
-		return
-	}
-
-	// 3. Crear fuente con el patrón detectado
+	// 2. Crear fuente (los datos de extracción se completan según el tipo)
 	newSource := &domain.NewsSource{
-		SourceName: req.SourceName,
-		RSSURL:     req.RSSURL,
-		NewsID:     category.ID,
-		LangID:     lang.ID,
-		IsActive:   true,
-		UserAdded:  true,         // ← MARCA COMO FUENTE DEL USUARIO
-		Filter:     &bestPattern, // ← PATRÓN DETECTADO AUTOMÁTICAMENTE
+		SourceName:     req.SourceName,
+		RSSURL:         req.RSSURL,
+		NewsID:         category.ID,
+		LangID:         lang.ID,
+		IsActive:       true,
+		UserAdded:      true, // ← MARCA COMO FUENTE DEL USUARIO
+		HTTPProfileID:  req.HTTPProfileID,
+		EnrichImages:   req.EnrichImages,
+		ExtractContent: req.ExtractContent,
+	}
+
+	// 3. Detectar el mejor patrón automáticamente (o validar la configuración en fuentes no RSS)
+	var bestPattern string
+	if req.isCustom() {
+		req.applyTo(newSource)
+		if _, _, err := h.testCustomSource(ctx, newSource); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudieron extraer noticias con esta configuración: " + err.Error()})
+			return
+		}
+		bestPattern = newSource.SourceType
+	} else {
+		bestPattern, err = h.detectBestPattern(ctx, req.RSSURL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No se pudo procesar esta fuente RSS. Verifica que la URL sea correcta."})
+			return
+		}
+		newSource.Filter = &bestPattern // ← PATRÓN DETECTADO AUTOMÁTICAMENTE
 	}
 
 	// 4. Guardar en la base de datos
@@ -667,6 +718,19 @@ func (h *Handler) DeleteSourceHandler(c *gin.Context) {
 		}
 	}
 
+	// Y el resto de imágenes de fallback propias de la fuente
+	if images, err := h.FallbackImageRepo.ListBySource(ctx, source.ID); err == nil {
+		for _, image := range images {
+			os.Remove(filepath.Join(getProjectRoot(), "frontend", "assets", "images", "fallback", image.Filename))
+			if err := h.FallbackImageRepo.DeleteByID(ctx, image.ID); err != nil {
+				utils.AppWarn("DELETE_SOURCE", "Error al eliminar registro de imagen", map[string]interface{}{
+					"fallback_id": image.ID,
+					"error":       err.Error(),
+				})
+			}
+		}
+	}
+
 	utils.AppInfo("DELETE_SOURCE", "Fuente eliminada exitosamente", map[string]interface{}{
 		"id": source.ID,
 	})
@@ -715,6 +779,76 @@ func (h *Handler) UpdateSourceHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// PUT /api/sources/:id/image-enrichment - Activa o desactiva la búsqueda de imagen en la página de la noticia
+func (h *Handler) UpdateSourceImageEnrichmentHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req struct {
+		EnrichImages *bool `json:"enrichImages" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	source, err := h.SourceRepo.FindByID(ctx, uint(id))
+	if err != nil || source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+		return
+	}
+
+	source.EnrichImages = *req.EnrichImages
+	if err := h.SourceRepo.Update(ctx, source); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la fuente"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Búsqueda de imagen en la noticia actualizada",
+		"enrichImages": source.EnrichImages,
+	})
+}
+
+// PUT /api/sources/:id/article-content - Activa o desactiva la extracción del texto completo de las noticias
+func (h *Handler) UpdateSourceArticleContentHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req struct {
+		ExtractContent *bool `json:"extractContent" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	source, err := h.SourceRepo.FindByID(ctx, uint(id))
+	if err != nil || source == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+		return
+	}
+
+	source.ExtractContent = *req.ExtractContent
+	if err := h.SourceRepo.Update(ctx, source); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la fuente"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Extracción de texto completo actualizada",
+		"extractContent": source.ExtractContent,
+	})
+}
+
 // POST /api/sources/:id/fallback-image - Actualiza imagen fallback de la fuente
 func (h *Handler) UpdateSourceFallbackImageHandler(c *gin.Context) {
 	idStr := c.Param("id")
@@ -774,6 +908,7 @@ func (h *Handler) UpdateSourceFallbackImageHandler(c *gin.Context) {
 
 	// Crear nuevo registro
 	newImg := &domain.FallbackImage{
+		SourceID:     &source.ID,
 		CategoryCode: source.News.Code,
 		LanguageCode: source.Lang.Code,
 		Filename:     filename,
@@ -983,10 +1118,16 @@ func (h *Handler) getFilteredNews(ctx context.Context, lang, category, search st
 			Title:        item.Title,
 			Link:         item.Link,
 			Image:        item.Image,
+			ImageSrcset:  item.ImageSrcset,
+			ImageWidth:   item.ImageWidth,
+			ImageHeight:  item.ImageHeight,
+			BlurHash:     item.ImageBlurHash,
+			ImagePreview: blurHashDataURI(item.ImageBlurHash),
 			SourceName:   item.Source.SourceName,
 			CategoryName: h.getCategoryNameByCode(item.CategoryCode),
 			Language:     item.LangCode,
 			PubDate:      utils.FormatDate(item.PubDate),
+			ReadingTime:  item.ReadingTime,
 		}
 	}
 
@@ -1188,24 +1329,43 @@ func (h *Handler) renderErrorPage(c *gin.Context, title, message string) {
 
 // ===== HANDLERS PARA IMÁGENES DE FALLBACK =====
 
-// UploadFallbackImageHandler maneja la subida de imágenes de fallback
+// UploadFallbackImageHandler maneja la subida de imágenes de fallback. El nivel sale del formulario:
+// sourceId (de una fuente), categoryCode+languageCode, solo categoryCode o ninguno (global).
+// Cada subida se añade al conjunto de su nivel, que se reparte entre las noticias.
 func (h *Handler) UploadFallbackImageHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	// Obtener parámetros del formulario
 	categoryCode := c.PostForm("categoryCode")
 	languageCode := c.PostForm("languageCode")
+	var sourceID *uint
+	if sourceIDStr := c.PostForm("sourceId"); sourceIDStr != "" {
+		id, err := strconv.ParseUint(sourceIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de fuente inválido"})
+			return
+		}
+		source, err := h.SourceRepo.FindByID(ctx, uint(id))
+		if err != nil || source == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+			return
+		}
+		sourceID = &source.ID
+		categoryCode, languageCode = source.News.Code, source.Lang.Code
+	}
 
 	utils.AppInfo("UPLOAD_FALLBACK", "Solicitud de subida de imagen recibida", map[string]interface{}{
+		"source_id":     sourceID,
 		"category_code": categoryCode,
 		"language_code": languageCode,
 		"content_type":  c.GetHeader("Content-Type"),
 	})
 
-	if categoryCode == "" || languageCode == "" {
-		utils.AppError("UPLOAD_FALLBACK", "Categoría o idioma faltante", nil, map[string]interface{}{
-			"category_code": categoryCode,
+	if categoryCode == "" && languageCode != "" {
+		utils.AppError("UPLOAD_FALLBACK", "Idioma sin categoría", nil, map[string]interface{}{
 			"language_code": languageCode,
 		})
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Categoría e idioma son requeridos"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "El idioma requiere una categoría"})
 		return
 	}
 
@@ -1225,7 +1385,10 @@ func (h *Handler) UploadFallbackImageHandler(c *gin.Context) {
 	// Generar nombre único
 	timestamp := time.Now().Format("20060102_150405")
 	extension := getFileExtension(file.Filename)
-	filename := fmt.Sprintf("%s_%s_%s%s", categoryCode, languageCode, timestamp, extension)
+	filename := fmt.Sprintf("%s_%s_%s%s", fallbackCodeOrAll(categoryCode), fallbackCodeOrAll(languageCode), timestamp, extension)
+	if sourceID != nil {
+		filename = fmt.Sprintf("source%d_%s", *sourceID, filename)
+	}
 
 	// Crear directorio si no existe (ruta relativa al proyecto)
 	projectRoot := getProjectRoot()
@@ -1257,6 +1420,7 @@ func (h *Handler) UploadFallbackImageHandler(c *gin.Context) {
 
 	// Crear registro en BD
 	fallbackImage := &domain.FallbackImage{
+		SourceID:     sourceID,
 		CategoryCode: categoryCode,
 		LanguageCode: languageCode,
 		Filename:     filename,
@@ -1265,7 +1429,6 @@ func (h *Handler) UploadFallbackImageHandler(c *gin.Context) {
 		FileSize:     file.Size,
 	}
 
-	ctx := c.Request.Context()
 	if err := h.FallbackImageRepo.Create(ctx, fallbackImage); err != nil {
 		// Eliminar archivo si falla la BD
 		os.Remove(uploadPath)
@@ -1356,6 +1519,8 @@ func (h *Handler) ListFallbackImagesHandler(c *gin.Context) {
 	var result []gin.H
 	for _, img := range images {
 		result = append(result, gin.H{
+			"id":            img.ID,
+			"source_id":     img.SourceID,
 			"category_code": img.CategoryCode,
 			"language_code": img.LanguageCode,
 			"filename":      img.Filename,
@@ -1368,6 +1533,40 @@ func (h *Handler) ListFallbackImagesHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// DeleteFallbackImageByIDHandler elimina una imagen concreta del conjunto de su nivel
+func (h *Handler) DeleteFallbackImageByIDHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	image, err := h.FallbackImageRepo.GetByID(ctx, uint(id))
+	if err != nil || image == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No se encontró imagen de fallback"})
+		return
+	}
+	if err := h.FallbackImageRepo.DeleteByID(ctx, image.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar de base de datos"})
+		return
+	}
+	os.Remove(filepath.Join(getProjectRoot(), "frontend", "assets", "images", "fallback", image.Filename))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Imagen de fallback eliminada exitosamente",
+	})
+}
+
+// fallbackCodeOrAll sustituye un código vacío (nivel de categoría o global) en el nombre del archivo
+func fallbackCodeOrAll(code string) string {
+	if code == "" {
+		return "all"
+	}
+	return code
+}
+
 // Funciones auxiliares para validación de archivos
 func validateImageFile(file *multipart.FileHeader) error {
 	// Validar tipo MIME
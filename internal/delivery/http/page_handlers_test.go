@@ -0,0 +1,76 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+// renderNewsCard ejecuta la plantilla de la tarjeta de noticia y devuelve su etiqueta <img> ("" si no tiene)
+func renderNewsCard(t *testing.T, data NewsData) string {
+	t.Helper()
+	tmpl, err := template.ParseFiles("../../../frontend/templates/partials/newsCard.html")
+	if err != nil {
+		t.Fatalf("plantilla inválida: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "newsCard", data); err != nil {
+		t.Fatalf("ExecuteTemplate: %v", err)
+	}
+	html := buf.String()
+	start := strings.Index(html, "<img")
+	if start < 0 {
+		return ""
+	}
+	return html[start : start+strings.Index(html[start:], ">")+1]
+}
+
+func TestNewsCardImage(t *testing.T) {
+	const srcset = "/images/cache/ab/abcd-320w.webp 320w, /images/cache/ab/abcd-480w.webp 480w, /images/cache/ab/abcd.webp 800w"
+	tests := []struct {
+		name     string
+		data     NewsData
+		want     []string
+		unwanted []string
+	}{
+		{
+			"imagen cacheada con versiones reducidas",
+			NewsData{Title: "Noticia", Image: "/images/cache/ab/abcd.webp", ImageSrcset: srcset, ImageWidth: 800, ImageHeight: 450},
+			[]string{`src="/images/cache/ab/abcd.webp"`, `srcset="` + srcset + `"`, `sizes="(min-width: 1280px) 25vw`, `width="800" height="450"`},
+			nil,
+		},
+		{
+			"imagen enlazada del medio",
+			NewsData{Title: "Noticia", Image: "https://example.com/foto.jpg"},
+			[]string{`src="https://example.com/foto.jpg"`},
+			[]string{"srcset=", "sizes=", "width="},
+		},
+		{
+			"sin alto no se reserva el hueco",
+			NewsData{Title: "Noticia", Image: "/images/cache/ab/abcd.webp", ImageWidth: 800},
+			[]string{`src="/images/cache/ab/abcd.webp"`},
+			[]string{"width="},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := renderNewsCard(t, tt.data)
+			for _, want := range tt.want {
+				if !strings.Contains(img, want) {
+					t.Errorf("la imagen no contiene %q:\n%s", want, img)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(img, unwanted) {
+					t.Errorf("la imagen contiene %q:\n%s", unwanted, img)
+				}
+			}
+		})
+	}
+
+	// Sin imagen se muestra el marcador aunque haya versiones reducidas
+	if img := renderNewsCard(t, NewsData{Title: "Noticia", ImageSrcset: srcset}); img != "" {
+		t.Errorf("la tarjeta sin imagen tiene %s", img)
+	}
+}
@@ -0,0 +1,239 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/feedcache"
+)
+
+// rssPatterns son los patrones RSS que se evalúan, en orden de preferencia para desempatar
+var rssPatterns = []string{"patron1", "patron2", "patron3", "patron1_no_image", "patron2_no_image", "patron3_no_image"}
+
+const (
+	reportSampleSize  = 3 // Noticias de ejemplo por patrón
+	reportImageChecks = 5 // Imágenes que se validan con ValidateImage por patrón
+)
+
+// patternReport es el resultado de evaluar un patrón (o configuración personalizada) sobre una fuente
+type patternReport struct {
+	Pattern         string                `json:"pattern"`
+	PatternType     string                `json:"pattern_type"`
+	Eligible        bool                  `json:"eligible"` // Al menos 2 noticias válidas
+	Score           float64               `json:"score"`    // Puntuación 0-100
+	TotalItems      int                   `json:"total_items"`
+	ValidItems      int                   `json:"valid_items"`
+	Coverage        coverageReport        `json:"coverage"`
+	ImageValidation imageValidationReport `json:"image_validation"`
+	Age             ageReport             `json:"age"`
+	Samples         []reportSample        `json:"samples"`
+	Error           string                `json:"error,omitempty"`
+}
+
+// coverageReport indica el porcentaje (0-100) de noticias con cada campo presente
+type coverageReport struct {
+	Title float64 `json:"title"`
+	Link  float64 `json:"link"`
+	Image float64 `json:"image"`
+	Date  float64 `json:"date"`
+}
+
+// imageValidationReport resume la validación de una muestra de imágenes con ValidateImage
+type imageValidationReport struct {
+	Checked  int     `json:"checked"`
+	Passed   int     `json:"passed"`
+	PassRate float64 `json:"pass_rate"`
+}
+
+// ageReport reparte las noticias con fecha según su antigüedad respecto a maxDays
+type ageReport struct {
+	MaxDays       int `json:"max_days"`
+	WithinMaxDays int `json:"within_max_days"`
+	Older         int `json:"older"`
+	Undated       int `json:"undated"`
+	Last24h       int `json:"last_24h"`
+	Days1To3      int `json:"days_1_3"`
+	Days3To7      int `json:"days_3_7"`
+	Over7Days     int `json:"over_7_days"`
+}
+
+// reportSample es una noticia de ejemplo del informe
+type reportSample struct {
+	Title   string    `json:"title"`
+	Link    string    `json:"link"`
+	Image   string    `json:"image"`
+	PubDate time.Time `json:"pub_date"`
+}
+
+// evaluateRSSPatterns evalúa todos los patrones RSS sobre la URL indicada
+func (h *Handler) evaluateRSSPatterns(ctx context.Context, rssURL string, maxDays int) []patternReport {
+	// Los patrones suelen compartir imágenes: no validar dos veces la misma URL
+	validated := make(map[string]bool)
+	// Todos los patrones se aplican al mismo feed: descargarlo una sola vez
+	ctx = feedcache.NewContext(ctx, feedcache.New(0))
+
+	reports := make([]patternReport, 0, len(rssPatterns))
+	for _, pattern := range rssPatterns {
+		patternType := "con imagen"
+		if strings.Contains(pattern, "no_image") {
+			patternType = "sin imagen (requerirá imagen de fallback)"
+		}
+		reports = append(reports, h.evaluatePattern(ctx, pattern, patternType, maxDays, validated, func() ([]domain.NewsItem, error) {
+			return h.RSSFetcher.Fetch(ctx, rssURL, pattern, "", "", "", "")
+		}))
+	}
+	return reports
+}
+
+// evaluateCustomSource evalúa la configuración de una fuente que no es RSS
+func (h *Handler) evaluateCustomSource(ctx context.Context, source *domain.NewsSource, patternType string, maxDays int) patternReport {
+	return h.evaluatePattern(ctx, source.GetType(), patternType, maxDays, make(map[string]bool), func() ([]domain.NewsItem, error) {
+		return h.SourceFetcher.FetchSource(ctx, source)
+	})
+}
+
+// evaluatePattern obtiene las noticias con fetch y calcula cobertura, validación de imágenes, antigüedad y puntuación
+func (h *Handler) evaluatePattern(ctx context.Context, pattern, patternType string, maxDays int, validated map[string]bool, fetch func() ([]domain.NewsItem, error)) patternReport {
+	report := patternReport{
+		Pattern:     pattern,
+		PatternType: patternType,
+		Age:         ageReport{MaxDays: maxDays},
+		Samples:     []reportSample{},
+	}
+
+	// Los fetchers usan la hora actual cuando la noticia no trae fecha: solo cuentan como
+	// fechadas las noticias publicadas antes de empezar la petición
+	start := time.Now()
+	items, err := fetch()
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.TotalItems = len(items)
+	if len(items) == 0 {
+		report.Error = "no se extrajo ninguna noticia"
+		return report
+	}
+
+	var withTitle, withLink, withImage, withDate int
+	for _, item := range items {
+		validTitle := item.Title != "" && len(item.Title) > 10
+		if validTitle {
+			withTitle++
+		}
+		if item.Link != "" {
+			withLink++
+		}
+		if item.Image != "" {
+			withImage++
+		}
+		if validTitle && item.Link != "" {
+			report.ValidItems++
+			if len(report.Samples) < reportSampleSize {
+				report.Samples = append(report.Samples, reportSample{
+					Title:   item.Title,
+					Link:    item.Link,
+					Image:   item.Image,
+					PubDate: item.PubDate,
+				})
+			}
+		}
+
+		if !item.PubDate.Before(start) {
+			report.Age.Undated++
+			continue
+		}
+		withDate++
+		age := start.Sub(item.PubDate)
+		if age <= time.Duration(maxDays)*24*time.Hour {
+			report.Age.WithinMaxDays++
+		} else {
+			report.Age.Older++
+		}
+		switch {
+		case age <= 24*time.Hour:
+			report.Age.Last24h++
+		case age <= 3*24*time.Hour:
+			report.Age.Days1To3++
+		case age <= 7*24*time.Hour:
+			report.Age.Days3To7++
+		default:
+			report.Age.Over7Days++
+		}
+	}
+
+	total := float64(len(items))
+	report.Coverage = coverageReport{
+		Title: percent(float64(withTitle), total),
+		Link:  percent(float64(withLink), total),
+		Image: percent(float64(withImage), total),
+		Date:  percent(float64(withDate), total),
+	}
+
+	for _, item := range items {
+		if report.ImageValidation.Checked >= reportImageChecks {
+			break
+		}
+		if item.Image == "" {
+			continue
+		}
+		valid, seen := validated[item.Image]
+		if !seen {
+			ok, err := h.ImageDownloader.ValidateImage(ctx, item.Image)
+			valid = err == nil && ok
+			validated[item.Image] = valid
+		}
+		report.ImageValidation.Checked++
+		if valid {
+			report.ImageValidation.Passed++
+		}
+	}
+	report.ImageValidation.PassRate = percent(float64(report.ImageValidation.Passed), float64(report.ImageValidation.Checked))
+
+	report.Eligible = report.ValidItems >= 2
+	report.Score = scorePattern(report)
+	return report
+}
+
+// scorePattern puntúa un patrón de 0 a 100: noticias válidas (30), imágenes presentes y válidas (30),
+// noticias dentro de maxDays (20), cobertura de título y link (10) y cobertura de fecha (10)
+func scorePattern(r patternReport) float64 {
+	if r.TotalItems == 0 {
+		return 0
+	}
+	score := math.Min(float64(r.ValidItems), 10) / 10 * 30
+	score += r.Coverage.Image / 100 * r.ImageValidation.PassRate / 100 * 30
+	score += float64(r.Age.WithinMaxDays) / float64(r.TotalItems) * 20
+	score += (r.Coverage.Title + r.Coverage.Link) / 200 * 10
+	score += r.Coverage.Date / 100 * 10
+	return math.Round(score*10) / 10
+}
+
+// bestPatternReport devuelve el informe elegible con mayor puntuación (en empate, el primero)
+func bestPatternReport(reports []patternReport) (*patternReport, error) {
+	var best *patternReport
+	for i := range reports {
+		if !reports[i].Eligible {
+			continue
+		}
+		if best == nil || reports[i].Score > best.Score {
+			best = &reports[i]
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("ningún patrón extrajo al menos 2 noticias válidas")
+	}
+	return best, nil
+}
+
+// percent devuelve part/total en porcentaje con un decimal
+func percent(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(part*1000/total) / 10
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"dailynews/internal/domain"
+)
+
+// countingImageDownloader valida las imágenes cuya URL contiene "ok" y cuenta las validaciones
+type countingImageDownloader struct {
+	calls int
+}
+
+func (d *countingImageDownloader) DownloadAndValidate(ctx context.Context, url, savePath string) (string, error) {
+	return "", errors.New("no implementado")
+}
+
+func (d *countingImageDownloader) ValidateImage(ctx context.Context, url string) (bool, error) {
+	d.calls++
+	return strings.Contains(url, "ok"), nil
+}
+
+func (d *countingImageDownloader) InspectImage(ctx context.Context, url string) (*domain.ImageValidation, error) {
+	return nil, errors.New("no implementado")
+}
+
+func TestEvaluatePattern(t *testing.T) {
+	now := time.Now()
+	items := []domain.NewsItem{
+		{Title: "Noticia de hace dos horas", Link: "https://a.example/1", Image: "https://img.example/ok.jpg", PubDate: now.Add(-2 * time.Hour)},
+		{Title: "Noticia de hace dos días", Link: "https://a.example/2", Image: "https://img.example/ok.jpg", PubDate: now.Add(-48 * time.Hour)},
+		{Title: "Noticia de hace cinco días", Link: "https://a.example/3", Image: "https://img.example/roto.jpg", PubDate: now.Add(-5 * 24 * time.Hour)},
+		{Title: "Noticia de hace un mes", Link: "https://a.example/4", PubDate: now.Add(-30 * 24 * time.Hour)},
+		{Title: "Corto", Link: "https://a.example/5", PubDate: now.Add(time.Hour)},
+	}
+
+	images := &countingImageDownloader{}
+	h := &Handler{ImageDownloader: images}
+	report := h.evaluatePattern(context.Background(), "patron1", "con imagen", 3, make(map[string]bool), func() ([]domain.NewsItem, error) {
+		return items, nil
+	})
+
+	if report.TotalItems != 5 || report.ValidItems != 4 || !report.Eligible {
+		t.Errorf("total/valid/eligible = %d/%d/%v", report.TotalItems, report.ValidItems, report.Eligible)
+	}
+	if len(report.Samples) != reportSampleSize {
+		t.Errorf("samples = %d, want %d", len(report.Samples), reportSampleSize)
+	}
+
+	wantCoverage := coverageReport{Title: 80, Link: 100, Image: 60, Date: 80}
+	if report.Coverage != wantCoverage {
+		t.Errorf("coverage = %+v, want %+v", report.Coverage, wantCoverage)
+	}
+
+	wantAge := ageReport{MaxDays: 3, WithinMaxDays: 2, Older: 2, Undated: 1, Last24h: 1, Days1To3: 1, Days3To7: 1, Over7Days: 1}
+	if report.Age != wantAge {
+		t.Errorf("age = %+v, want %+v", report.Age, wantAge)
+	}
+
+	// La imagen repetida solo se valida una vez, pero cuenta en la muestra
+	wantImages := imageValidationReport{Checked: 3, Passed: 2, PassRate: 66.7}
+	if report.ImageValidation != wantImages {
+		t.Errorf("image validation = %+v, want %+v", report.ImageValidation, wantImages)
+	}
+	if images.calls != 2 {
+		t.Errorf("ValidateImage calls = %d, want 2", images.calls)
+	}
+
+	if want := scorePattern(report); report.Score != want {
+		t.Errorf("score = %v, want %v", report.Score, want)
+	}
+}
+
+func TestEvaluatePatternErrors(t *testing.T) {
+	h := &Handler{ImageDownloader: &countingImageDownloader{}}
+	tests := []struct {
+		name  string
+		fetch func() ([]domain.NewsItem, error)
+		want  string
+	}{
+		{"error del fetcher", func() ([]domain.NewsItem, error) { return nil, errors.New("timeout") }, "timeout"},
+		{"sin noticias", func() ([]domain.NewsItem, error) { return nil, nil }, "no se extrajo ninguna noticia"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := h.evaluatePattern(context.Background(), "patron1", "", 5, make(map[string]bool), tt.fetch)
+			if report.Error != tt.want || report.Eligible || report.Score != 0 {
+				t.Errorf("report = %+v", report)
+			}
+		})
+	}
+}
+
+func TestScorePattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		report patternReport
+		want   float64
+	}{
+		{"sin noticias", patternReport{}, 0},
+		{
+			"perfecto",
+			patternReport{
+				TotalItems: 10, ValidItems: 10,
+				Coverage:        coverageReport{Title: 100, Link: 100, Image: 100, Date: 100},
+				ImageValidation: imageValidationReport{PassRate: 100},
+				Age:             ageReport{WithinMaxDays: 10},
+			},
+			100,
+		},
+		{
+			"sin imágenes ni fechas",
+			patternReport{
+				TotalItems: 4, ValidItems: 4,
+				Coverage: coverageReport{Title: 100, Link: 100},
+			},
+			22,
+		},
+		{
+			"las noticias válidas saturan en 10",
+			patternReport{
+				TotalItems: 40, ValidItems: 40,
+				Coverage:        coverageReport{Title: 50, Link: 100, Image: 50, Date: 25},
+				ImageValidation: imageValidationReport{PassRate: 50},
+				Age:             ageReport{WithinMaxDays: 10},
+			},
+			30 + 7.5 + 5 + 7.5 + 2.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scorePattern(tt.report); got != tt.want {
+				t.Errorf("scorePattern = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestPatternReport(t *testing.T) {
+	reports := []patternReport{
+		{Pattern: "patron1", Eligible: false, Score: 90},
+		{Pattern: "patron2", Eligible: true, Score: 70},
+		{Pattern: "patron3", Eligible: true, Score: 70},
+		{Pattern: "patron1_no_image", Eligible: true, Score: 60},
+	}
+	best, err := bestPatternReport(reports)
+	if err != nil {
+		t.Fatalf("bestPatternReport: %v", err)
+	}
+	if best.Pattern != "patron2" {
+		t.Errorf("best = %s, want patron2 (el primero en caso de empate)", best.Pattern)
+	}
+
+	if _, err := bestPatternReport(reports[:1]); err == nil {
+		t.Error("expected error when no report is eligible")
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		part, total, want float64
+	}{
+		{1, 3, 33.3},
+		{2, 3, 66.7},
+		{5, 5, 100},
+		{3, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := percent(tt.part, tt.total); got != tt.want {
+			t.Errorf("percent(%v, %v) = %v, want %v", tt.part, tt.total, got, tt.want)
+		}
+	}
+}
@@ -10,16 +10,26 @@ func InitRoutes(router *gin.Engine, handler *Handler) {
 	router.GET("/categoria/:category", handler.CategoryPageHandler)
 	router.GET("/buscar", handler.SearchPageHandler)
 
+	// Callback público de WebSub (verificación de intención y contenido enviado por el hub)
+	router.GET("/websub/callback/:id", handler.WebSubVerifyHandler)
+	router.POST("/websub/callback/:id", handler.WebSubReceiveHandler)
+
 	//  Rutas de API
 	api := router.Group("/api")
 	{
 		// Rutas de noticias
 		api.GET("/news/:lang/:category", handler.GetNewsHandler)
 		api.GET("/news/search", handler.SearchNewsHandler)
-		api.GET("/news/filtered", handler.GetFilteredNewsHandler) // Nueva ruta para filtros avanzados
+		api.GET("/news/filtered", handler.GetFilteredNewsHandler)        // Nueva ruta para filtros avanzados
+		api.GET("/news/item/:id/content", handler.GetNewsContentHandler) // texto completo (vista de lectura)
 		// Fuentes RSS del usuario (CRUD)
-		api.PUT("/sources/:id", handler.UpdateSourceHandler)                              // actualizar nombre
-		api.POST("/sources/:id/fallback-image", handler.UpdateSourceFallbackImageHandler) // actualizar imagen fallback
+		api.PUT("/sources/:id", handler.UpdateSourceHandler)                                 // actualizar nombre
+		api.POST("/sources/:id/fallback-image", handler.UpdateSourceFallbackImageHandler)    // actualizar imagen fallback
+		api.PUT("/sources/:id/http-profile", handler.UpdateSourceHTTPProfileHandler)         // asociar perfil HTTP
+		api.PUT("/sources/:id/image-enrichment", handler.UpdateSourceImageEnrichmentHandler) // imagen desde la página de la noticia
+		api.PUT("/sources/:id/article-content", handler.UpdateSourceArticleContentHandler)   // texto completo de las noticias
+		api.GET("/sources/:id/image-placeholders", handler.ListImagePlaceholdersHandler)     // imágenes genéricas de la fuente
+		api.POST("/sources/:id/image-placeholders", handler.AddImagePlaceholderHandler)
 
 		// Rutas de metadatos
 		api.GET("/categories", handler.GetCategoriesHandler)
@@ -30,16 +40,33 @@ func InitRoutes(router *gin.Engine, handler *Handler) {
 		api.POST("/sources/check-duplicate", handler.CheckDuplicateSourceHandler)
 		api.POST("/sources/add", handler.AddSourceHandler)
 		api.POST("/sources/test", handler.TestSourceHandler)
+		api.POST("/sources/discover", handler.DiscoverSourcesHandler)     // buscar feeds a partir de una web
+		api.GET("/sources/export.opml", handler.ExportSourcesOPMLHandler) // exportar fuentes como OPML
+		api.POST("/sources/import", handler.ImportSourcesOPMLHandler)     // importar fuentes desde OPML
+		api.GET("/sources/health", handler.GetSourcesHealthHandler)       // salud de la última extracción
 		api.DELETE("/sources/:id", handler.DeleteSourceHandler)
 
+		// Perfiles HTTP (cabeceras, autenticación, proxy, timeouts) asociables a fuentes
+		api.GET("/http-profiles", handler.ListHTTPProfilesHandler)
+		api.POST("/http-profiles", handler.CreateHTTPProfileHandler)
+		api.PUT("/http-profiles/:id", handler.UpdateHTTPProfileHandler)
+		api.DELETE("/http-profiles/:id", handler.DeleteHTTPProfileHandler)
+
 		// Rutas para gestión de imágenes de fallback
 		api.POST("/fallback-image/upload", handler.UploadFallbackImageHandler)
 		api.GET("/fallback-image/:category/:lang", handler.GetFallbackImageHandler)
 		api.DELETE("/fallback-image/:category/:lang", handler.DeleteFallbackImageHandler)
 		api.GET("/fallback-image/list", handler.ListFallbackImagesHandler)
+		api.DELETE("/fallback-images/:id", handler.DeleteFallbackImageByIDHandler) // quitar una imagen de un conjunto
 
 		// Rutas de administración
 		api.POST("/news/refresh", handler.RefreshNewsHandler)
 		api.GET("/health", handler.HealthHandler)
+		api.GET("/circuit-breakers", handler.ListCircuitBreakersHandler)              // estado por host
+		api.POST("/circuit-breakers/:host/reset", handler.ResetCircuitBreakerHandler) // cerrar manualmente
+		api.GET("/image-validations", handler.GetImageValidationHandler)              // validación guardada de una imagen
+		api.DELETE("/image-validations", handler.InvalidateImageValidationsHandler)   // invalidar la caché de validaciones
+		api.DELETE("/image-placeholders/:id", handler.DeleteImagePlaceholderHandler)  // quitar una imagen genérica
+		api.GET("/websub/subscriptions", handler.ListWebSubSubscriptionsHandler)      // suscripciones push
 	}
 }
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sourceHealthResponse es la salud de una fuente en su última extracción
+type sourceHealthResponse struct {
+	SourceID            uint       `json:"sourceId"`
+	SourceName          string     `json:"sourceName"`
+	URL                 string     `json:"url"`
+	IsActive            bool       `json:"isActive"`
+	LastRunAt           *time.Time `json:"lastRunAt"` // nil si nunca se ha extraído
+	LastSuccessAt       *time.Time `json:"lastSuccessAt"`
+	LastStatus          string     `json:"lastStatus"`
+	LastError           string     `json:"lastError,omitempty"`
+	ItemsFetched        int        `json:"itemsFetched"`
+	ItemsAccepted       int        `json:"itemsAccepted"`
+	Requests            int        `json:"requests"`
+	Retries             int        `json:"retries"`
+	Failures            int        `json:"failures"`
+	RateLimited         int        `json:"rateLimited"`
+	RobotsBlocked       int        `json:"robotsBlocked"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+}
+
+// GET /api/sources/health - Salud de todas las fuentes (última extracción y estadísticas HTTP)
+func (h *Handler) GetSourcesHealthHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sources, err := h.SourceRepo.ListAll(ctx)
+	if err != nil {
+		utils.AppError("SOURCE_HEALTH", "Error al listar fuentes", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener fuentes"})
+		return
+	}
+	healths, err := h.SourceHealthRepo.ListAll(ctx)
+	if err != nil {
+		utils.AppError("SOURCE_HEALTH", "Error al listar salud de fuentes", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener la salud de las fuentes"})
+		return
+	}
+
+	bySource := make(map[uint]*domain.SourceHealth, len(healths))
+	for i := range healths {
+		bySource[healths[i].SourceID] = &healths[i]
+	}
+
+	response := make([]sourceHealthResponse, 0, len(sources))
+	for _, source := range sources {
+		item := sourceHealthResponse{
+			SourceID:   source.ID,
+			SourceName: source.SourceName,
+			URL:        source.RSSURL,
+			IsActive:   source.IsActive,
+			LastStatus: "pending",
+		}
+		if health, ok := bySource[source.ID]; ok {
+			lastRun := health.LastRunAt
+			item.LastRunAt = &lastRun
+			item.LastSuccessAt = health.LastSuccessAt
+			item.LastStatus = health.LastStatus
+			item.LastError = health.LastError
+			item.ItemsFetched = health.ItemsFetched
+			item.ItemsAccepted = health.ItemsAccepted
+			item.Requests = health.Requests
+			item.Retries = health.Retries
+			item.Failures = health.Failures
+			item.RateLimited = health.RateLimited
+			item.RobotsBlocked = health.RobotsBlocked
+			item.ConsecutiveFailures = health.ConsecutiveFailures
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
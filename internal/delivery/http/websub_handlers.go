@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webSubMaxPushBody es el tamaño máximo aceptado del contenido enviado por un hub
+const webSubMaxPushBody = 10 << 20
+
+// webSubSubscriptionResponse es la representación pública de una suscripción WebSub (sin el secreto)
+type webSubSubscriptionResponse struct {
+	ID           uint       `json:"id"`
+	SourceID     uint       `json:"sourceId"`
+	Topic        string     `json:"topic"`
+	Hub          string     `json:"hub"`
+	State        string     `json:"state"`
+	LeaseSeconds int        `json:"leaseSeconds"`
+	ExpiresAt    *time.Time `json:"expiresAt"`
+	LastPushAt   *time.Time `json:"lastPushAt"`
+	LastError    string     `json:"lastError,omitempty"`
+	CheckedAt    time.Time  `json:"checkedAt"`
+}
+
+// GET /websub/callback/:id - Verificación de intención del hub (subscribe, unsubscribe o denied)
+func (h *Handler) WebSubVerifyHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || h.WebSub == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	leaseSeconds, _ := strconv.Atoi(c.Query("hub.lease_seconds"))
+	challenge, err := h.WebSub.Verify(c.Request.Context(), uint(id),
+		c.Query("hub.mode"), c.Query("hub.topic"), c.Query("hub.challenge"), leaseSeconds, c.Query("hub.reason"))
+	if err != nil {
+		utils.AppWarn("WEBSUB", "Verificación del hub rechazada", map[string]interface{}{
+			"subscription_id": id,
+			"mode":            c.Query("hub.mode"),
+			"error":           err.Error(),
+		})
+		if errors.Is(err, domain.ErrWebSubNotFound) {
+			c.Status(http.StatusNotFound)
+		} else {
+			c.Status(http.StatusBadRequest)
+		}
+		return
+	}
+
+	c.String(http.StatusOK, challenge)
+}
+
+// POST /websub/callback/:id - Contenido nuevo enviado por el hub; se ingiere con la extracción de la fuente
+func (h *Handler) WebSubReceiveHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || h.WebSub == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, webSubMaxPushBody))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	// La extracción sigue tras responder al hub: el contexto no se cancela con la petición
+	sourceID, pushCtx, err := h.WebSub.Receive(context.WithoutCancel(c.Request.Context()), uint(id), body, c.GetHeader("X-Hub-Signature"))
+	switch {
+	case errors.Is(err, domain.ErrWebSubNotFound):
+		c.Status(http.StatusNotFound)
+		return
+	case errors.Is(err, domain.ErrWebSubSignature):
+		// El protocolo exige confirmar la recepción aunque el contenido se ignore
+		utils.AppWarn("WEBSUB", "Contenido con firma inválida ignorado", map[string]interface{}{
+			"subscription_id": id,
+		})
+		c.Status(http.StatusAccepted)
+		return
+	case err != nil:
+		utils.AppWarn("WEBSUB", "Contenido del hub rechazado", map[string]interface{}{
+			"subscription_id": id,
+			"error":           err.Error(),
+		})
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(pushCtx, 2*time.Minute)
+		defer cancel()
+		if err := h.FetchUseCaseForSource(ctx, sourceID); err != nil {
+			utils.AppError("WEBSUB", "Error ingiriendo contenido del hub", err, map[string]interface{}{
+				"source_id": sourceID,
+			})
+		}
+	}()
+	c.Status(http.StatusAccepted)
+}
+
+// GET /api/websub/subscriptions - Estado de las suscripciones WebSub
+func (h *Handler) ListWebSubSubscriptionsHandler(c *gin.Context) {
+	subscriptions, err := h.WebSubRepo.ListAll(c.Request.Context())
+	if err != nil {
+		utils.AppError("WEBSUB", "Error al listar suscripciones", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener suscripciones WebSub"})
+		return
+	}
+
+	response := make([]webSubSubscriptionResponse, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		response = append(response, webSubSubscriptionResponse{
+			ID:           sub.ID,
+			SourceID:     sub.SourceID,
+			Topic:        sub.Topic,
+			Hub:          sub.Hub,
+			State:        sub.State,
+			LeaseSeconds: sub.LeaseSeconds,
+			ExpiresAt:    sub.ExpiresAt,
+			LastPushAt:   sub.LastPushAt,
+			LastError:    sub.LastError,
+			CheckedAt:    sub.CheckedAt,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// httpProfileKey es la clave de contexto del perfil HTTP de la fuente en proceso
+type httpProfileKey struct{}
+
+// httpProfileScope es el perfil HTTP de la fuente en proceso junto con el host de la URL de la fuente
+type httpProfileScope struct {
+	profile *HTTPProfile
+	host    string
+}
+
+// WithHTTPProfile devuelve un contexto que lleva el perfil HTTP de la fuente con URL sourceURL.
+// Los fetchers y el validador de imágenes lo aplican a sus peticiones; sus cabeceras y autenticación
+// solo se envían al host de sourceURL y a los CredentialHosts del perfil.
+func WithHTTPProfile(ctx context.Context, profile *HTTPProfile, sourceURL string) context.Context {
+	if profile == nil {
+		return ctx
+	}
+	host := ""
+	if u, err := url.Parse(strings.TrimSpace(sourceURL)); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+	return context.WithValue(ctx, httpProfileKey{}, &httpProfileScope{profile: profile, host: host})
+}
+
+// HTTPProfileFromContext devuelve el perfil HTTP del contexto o nil si no hay
+func HTTPProfileFromContext(ctx context.Context) *HTTPProfile {
+	if scope, _ := ctx.Value(httpProfileKey{}).(*httpProfileScope); scope != nil {
+		return scope.profile
+	}
+	return nil
+}
+
+// HTTPProfileHostFromContext devuelve el host de la fuente del perfil HTTP del contexto ("" si no hay)
+func HTTPProfileHostFromContext(ctx context.Context) string {
+	if scope, _ := ctx.Value(httpProfileKey{}).(*httpProfileScope); scope != nil {
+		return scope.host
+	}
+	return ""
+}
+
+// fetchStatsKey es la clave de contexto de las estadísticas HTTP de la fuente en proceso
+type fetchStatsKey struct{}
+
+// WithFetchStats devuelve un contexto en el que la capa HTTP acumula estadísticas de la fuente
+func WithFetchStats(ctx context.Context, stats *FetchStats) context.Context {
+	if stats == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, fetchStatsKey{}, stats)
+}
+
+// FetchStatsFromContext devuelve las estadísticas del contexto o nil si no hay.
+// Los métodos de FetchStats admiten receptor nil, por lo que se pueden usar sin comprobarlo.
+func FetchStatsFromContext(ctx context.Context) *FetchStats {
+	stats, _ := ctx.Value(fetchStatsKey{}).(*FetchStats)
+	return stats
+}
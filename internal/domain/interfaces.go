@@ -30,6 +30,35 @@ type NewsSourceRepository interface {
 	ExistsByURLCategoryLang(ctx context.Context, rssURL string, categoryID, langID uint) (bool, error)
 }
 
+// HTTPProfileRepository define las operaciones para el repositorio de perfiles HTTP
+type HTTPProfileRepository interface {
+	FindByID(ctx context.Context, id uint) (*HTTPProfile, error)
+	ListAll(ctx context.Context) ([]HTTPProfile, error)
+	Create(ctx context.Context, profile *HTTPProfile) error
+	Update(ctx context.Context, profile *HTTPProfile) error
+	// Delete elimina el perfil y lo desasocia de las fuentes que lo usaban
+	Delete(ctx context.Context, id uint) error
+}
+
+// SourceHealthRepository define las operaciones para el repositorio de salud de las fuentes
+type SourceHealthRepository interface {
+	FindBySourceID(ctx context.Context, sourceID uint) (*SourceHealth, error)
+	ListAll(ctx context.Context) ([]SourceHealth, error)
+	// Save crea o actualiza el registro de la fuente
+	Save(ctx context.Context, health *SourceHealth) error
+}
+
+// WebSubSubscriptionRepository define las operaciones para el repositorio de suscripciones WebSub
+type WebSubSubscriptionRepository interface {
+	FindByID(ctx context.Context, id uint) (*WebSubSubscription, error)
+	FindBySourceID(ctx context.Context, sourceID uint) (*WebSubSubscription, error)
+	ListAll(ctx context.Context) ([]WebSubSubscription, error)
+	// ListExpiringBefore devuelve las suscripciones activas que caducan antes de la fecha indicada
+	ListExpiringBefore(ctx context.Context, date time.Time) ([]WebSubSubscription, error)
+	// Save crea o actualiza la suscripción
+	Save(ctx context.Context, subscription *WebSubSubscription) error
+}
+
 // FallbackImageRepository define las operaciones para el repositorio de imágenes de fallback
 type FallbackImageRepository interface {
 	Create(ctx context.Context, image *FallbackImage) error
@@ -39,6 +68,11 @@ type FallbackImageRepository interface {
 	Delete(ctx context.Context, categoryCode, languageCode string) error
 	DeleteByID(ctx context.Context, id uint) error // NUEVO
 	ListAll(ctx context.Context) ([]FallbackImage, error)
+	// ListForFallback devuelve las imágenes que pueden servir de fallback a una noticia de la fuente:
+	// las de la fuente y las compartidas de su categoría+idioma, de su categoría y globales
+	ListForFallback(ctx context.Context, sourceID uint, categoryCode, languageCode string) ([]FallbackImage, error)
+	// ListBySource devuelve las imágenes propias de la fuente (SourceID)
+	ListBySource(ctx context.Context, sourceID uint) ([]FallbackImage, error)
 }
 
 // NewsItemRepository define las operaciones para el repositorio de noticias
@@ -49,6 +83,11 @@ type NewsItemRepository interface {
 	FindBySourceID(ctx context.Context, sourceID uint) ([]NewsItem, error)
 	FindByLangAndCategory(ctx context.Context, langCode, categoryCode string, limit int) ([]NewsItem, error)
 	DeleteOlderThan(ctx context.Context, date time.Time) error
+	// ExistsByLink indica si ya hay una noticia guardada con ese link
+	ExistsByLink(ctx context.Context, link string) (bool, error)
+	Update(ctx context.Context, item *NewsItem) error
+	// ImagesWithPrefix devuelve las imágenes distintas de las noticias guardadas que empiezan por prefix
+	ImagesWithPrefix(ctx context.Context, prefix string) ([]string, error)
 
 	// Métodos para el frontend
 	GetLatest(ctx context.Context, lang string, limit, offset int) ([]NewsItem, error)
@@ -68,10 +107,115 @@ type RSSFetcher interface {
 	Fetch(ctx context.Context, url string, filter string, titleField, imageField, linkField, dateField string) ([]NewsItem, error)
 }
 
+// SourceFetcher define el contrato para obtener noticias de una fuente según su tipo (RSS, HTML...)
+type SourceFetcher interface {
+	FetchSource(ctx context.Context, source *NewsSource) ([]NewsItem, error)
+}
+
+// FeedDiscoverer define el contrato para encontrar feeds y sitemaps a partir de la URL de una web
+type FeedDiscoverer interface {
+	Discover(ctx context.Context, pageURL string) ([]FeedCandidate, error)
+}
+
+// CircuitBreakerMonitor expone el estado de los circuit breakers por host de la capa HTTP
+type CircuitBreakerMonitor interface {
+	CircuitStates() []HostCircuit
+	// ResetCircuit cierra el circuito del host; devuelve false si el host no tiene circuito
+	ResetCircuit(host string) bool
+}
+
+// HTTPProfileClientCache guarda los clientes HTTP construidos para cada perfil
+type HTTPProfileClientCache interface {
+	// Invalidate descarta los clientes del perfil tras editarlo o borrarlo
+	Invalidate(profileID uint)
+}
+
+// WebSubManager gestiona las suscripciones WebSub de las fuentes RSS
+type WebSubManager interface {
+	// EnsureSubscribed detecta el hub del feed de la fuente y se suscribe si aún no lo está
+	EnsureSubscribed(ctx context.Context, source *NewsSource) error
+	// Verify atiende la verificación de intención del hub y devuelve el challenge que hay que responder
+	Verify(ctx context.Context, subscriptionID uint, mode, topic, challenge string, leaseSeconds int, reason string) (string, error)
+	// Receive valida la firma del contenido enviado por el hub. Devuelve la fuente a extraer y un contexto
+	// que ya lleva el feed recibido, para que la extracción no vuelva a descargarlo.
+	Receive(ctx context.Context, subscriptionID uint, body []byte, signature string) (uint, context.Context, error)
+	// RenewExpiring renueva las suscripciones activas que están a punto de caducar
+	RenewExpiring(ctx context.Context) error
+}
+
 // ImageDownloader define el contrato para descargar y validar imágenes
 type ImageDownloader interface {
 	DownloadAndValidate(ctx context.Context, url, savePath string) (string, error)
-	ValidateImage(path string) (bool, error)
+	ValidateImage(ctx context.Context, url string) (bool, error)
+	// InspectImage valida la imagen y devuelve el detalle (dimensiones, motivo del rechazo, hash perceptual).
+	// Los fallos transitorios (red, 5xx, 429) se devuelven como error.
+	InspectImage(ctx context.Context, url string) (*ImageValidation, error)
+}
+
+// ImagePlaceholderRepository define las operaciones de la lista de imágenes genéricas por fuente
+type ImagePlaceholderRepository interface {
+	ListBySource(ctx context.Context, sourceID uint) ([]ImagePlaceholder, error)
+	Save(ctx context.Context, placeholder *ImagePlaceholder) error
+	Delete(ctx context.Context, id uint) error
+	// IncrementHits suma un descarte a la imagen genérica
+	IncrementHits(ctx context.Context, id uint) error
+	// RecordSighting anota la imagen vista en una noticia de la fuente y conserva solo las keep más recientes
+	RecordSighting(ctx context.Context, sighting *ImageHashSighting, keep int) error
+	// RecentSightings devuelve los hashes de las últimas imágenes vistas en noticias de la fuente
+	RecentSightings(ctx context.Context, sourceID uint, limit int) ([]string, error)
+}
+
+// CachedImageRepository define las operaciones para el repositorio de imágenes cacheadas
+type CachedImageRepository interface {
+	FindByURLHash(ctx context.Context, urlHash string) (*CachedImage, error)
+	Save(ctx context.Context, image *CachedImage) error
+	Delete(ctx context.Context, id uint) error
+	// CountByContentHash indica cuántas URLs comparten el archivo
+	CountByContentHash(ctx context.Context, contentHash string) (int64, error)
+	// ListUnreferenced devuelve las imágenes que no usa ninguna noticia, de la menos a la más usada recientemente
+	ListUnreferenced(ctx context.Context) ([]CachedImage, error)
+	// TotalBytes suma el tamaño de los archivos distintos de la caché
+	TotalBytes(ctx context.Context) (int64, error)
+}
+
+// ImageValidationRepository define las operaciones para la caché de validaciones de imágenes
+type ImageValidationRepository interface {
+	FindByURLHash(ctx context.Context, urlHash string) (*ImageValidation, error)
+	Save(ctx context.Context, validation *ImageValidation) error
+	// Delete invalida una URL; DeleteAll invalida todas (o solo las rechazadas con onlyInvalid)
+	Delete(ctx context.Context, urlHash string) (int64, error)
+	DeleteAll(ctx context.Context, onlyInvalid bool) (int64, error)
+	// DeleteCheckedBefore elimina las entradas comprobadas antes de la fecha indicada
+	DeleteCheckedBefore(ctx context.Context, date time.Time) (int64, error)
+}
+
+// FallbackImageGenerator genera imágenes de marca (color e icono de la categoría, fuente y titular)
+// para las noticias sin imagen cuando no hay ninguna imagen de fallback subida
+type FallbackImageGenerator interface {
+	// Generate devuelve la URL local de la imagen, generándola solo si no estaba ya en disco
+	Generate(ctx context.Context, sourceName, categoryCode, title string) (string, error)
+	// CollectGarbage borra las imágenes generadas que llevan más de la edad máxima sin usarse
+	CollectGarbage(ctx context.Context) (int, error)
+}
+
+// ImageCache define el contrato de la caché local de imágenes de noticias
+type ImageCache interface {
+	// Store descarga la imagen (si no estaba ya), la guarda optimizada con sus versiones reducidas
+	// y devuelve su registro (URL local, dimensiones, versiones)
+	Store(ctx context.Context, imageURL string) (*CachedImage, error)
+	// CollectGarbage borra las imágenes sin usar más antiguas que la edad máxima o que exceden el tamaño máximo
+	CollectGarbage(ctx context.Context) (int, error)
+}
+
+// ArticleImageExtractor define el contrato para obtener la imagen de una noticia desde su página
+// (og:image, twitter:image o JSON-LD) cuando el feed no trae una válida
+type ArticleImageExtractor interface {
+	ExtractImages(ctx context.Context, articleURL string) ([]string, error)
+}
+
+// ArticleContentExtractor define el contrato para extraer el texto completo de una noticia desde su página
+type ArticleContentExtractor interface {
+	Extract(ctx context.Context, articleURL string) (*ArticleContent, error)
 }
 
 // Logger define el contrato para el sistema de logging
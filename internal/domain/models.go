@@ -1,6 +1,15 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/bits"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,23 +37,38 @@ func (Category) TableName() string {
 	return "template_news"
 }
 
+// Tipos de fuente de noticias soportados
+const (
+	SourceTypeRSS     = "rss"     // Feed RSS/Atom (por defecto)
+	SourceTypeHTML    = "html"    // Página HTML sin feed, extraída con selectores CSS
+	SourceTypeSitemap = "sitemap" // Sitemap de Google News o sitemap genérico
+	SourceTypeJSON    = "json"    // API JSON con rutas de campos estilo JSONPath
+)
+
 // NewsSource representa una fuente RSS de noticias
 type NewsSource struct {
-	ID              uint     `gorm:"primaryKey"` // Identificador único de la fuente RSS
-	NewsID          uint     `gorm:"not null"`   // ID de la categoría asociada
-	News            Category // Relación con la categoría
-	SourceName      string   `gorm:"size:100"`           // Nombre de la fuente (ej: "BBC Mundo")
-	RSSURL          string   `gorm:"type:text;not null"` // URL del feed RSS
-	Filter          *string  `gorm:"type:text"`          // Identificador de patrón de extracción ("patron1", "patron2", etc.)
-	TitleField      *string  `gorm:"size:255"`           // Campo personalizado para el titular (si el RSS es único)
-	ImageField      *string  `gorm:"size:255"`           // Campo personalizado para la imagen
-	LinkField       *string  `gorm:"size:255"`           // Campo personalizado para el link
-	CampoFecha      *string  `gorm:"size:255"`           // Campo personalizado para la fecha
-	LangID          uint     `gorm:"not null"`           // ID del país/idioma asociado
-	Lang            Country  // Relación con el país/idioma
-	IsActive        bool     `gorm:"default:true"`  // Lo de is IsActive esta pensado para que en un futuro el usuario pueda desactivar fuentes por defecto.
-	UserAdded       bool     `gorm:"default:false"` // Indica si la fuente fue agregada por el usuario
-	FallbackImageID *uint    `gorm:"index"`         // NUEVO: FK a FallbackImage
+	ID              uint         `gorm:"primaryKey"` // Identificador único de la fuente RSS
+	NewsID          uint         `gorm:"not null"`   // ID de la categoría asociada
+	News            Category     // Relación con la categoría
+	SourceName      string       `gorm:"size:100"`            // Nombre de la fuente (ej: "BBC Mundo")
+	RSSURL          string       `gorm:"type:text;not null"`  // URL del feed RSS (o de la página de listado / sitemap según el tipo)
+	SourceType      string       `gorm:"size:20;default:rss"` // Tipo de fuente ("rss", "html", "sitemap", "json")
+	Filter          *string      `gorm:"type:text"`           // Identificador de patrón de extracción ("patron1", "patron2", etc.)
+	ItemSelector    *string      `gorm:"size:255"`            // Selector CSS de cada noticia (HTML), fragmento de ruta de las URLs (sitemap) o ruta al array de noticias (JSON)
+	FetchOptions    *string      `gorm:"type:text"`           // Opciones adicionales en JSON (cabeceras y paginación de las fuentes JSON)
+	TitleField      *string      `gorm:"size:255"`            // Campo personalizado para el titular (si el RSS es único; selector CSS en HTML)
+	ImageField      *string      `gorm:"size:255"`            // Campo personalizado para la imagen
+	LinkField       *string      `gorm:"size:255"`            // Campo personalizado para el link
+	CampoFecha      *string      `gorm:"size:255"`            // Campo personalizado para la fecha
+	LangID          uint         `gorm:"not null"`            // ID del país/idioma asociado
+	Lang            Country      // Relación con el país/idioma
+	IsActive        bool         `gorm:"default:true"`  // Lo de is IsActive esta pensado para que en un futuro el usuario pueda desactivar fuentes por defecto.
+	UserAdded       bool         `gorm:"default:false"` // Indica si la fuente fue agregada por el usuario
+	FallbackImageID *uint        `gorm:"index"`         // NUEVO: FK a FallbackImage
+	HTTPProfileID   *uint        `gorm:"index"`         // FK opcional a HTTPProfile
+	HTTPProfile     *HTTPProfile // Perfil HTTP aplicado a las peticiones de feed e imágenes de la fuente
+	EnrichImages    bool         `gorm:"default:false"` // Buscar la imagen en la página de la noticia si el feed no trae una válida
+	ExtractContent  bool         `gorm:"default:false"` // Extraer el texto completo de las noticias (solo si el medio lo permite)
 }
 
 // TableName especifica el nombre de la tabla para el modelo NewsSource
@@ -52,6 +76,333 @@ func (NewsSource) TableName() string {
 	return "template_news_sources"
 }
 
+// GetType devuelve el tipo de la fuente, usando RSS cuando no está definido
+func (s *NewsSource) GetType() string {
+	if s.SourceType == "" {
+		return SourceTypeRSS
+	}
+	return s.SourceType
+}
+
+// Tipos de autenticación de un perfil HTTP
+const (
+	HTTPAuthBasic  = "basic"  // Usuario + contraseña (Authorization: Basic)
+	HTTPAuthBearer = "bearer" // Token (Authorization: Bearer)
+)
+
+// Políticas de redirección de un perfil HTTP
+const (
+	RedirectFollow   = "follow"    // Seguir redirecciones (por defecto)
+	RedirectSameHost = "same-host" // Seguir solo redirecciones al mismo host
+	RedirectNone     = "none"      // No seguir redirecciones
+)
+
+// SecretEnvPrefix es el prefijo obligatorio de las variables de entorno que un perfil HTTP puede usar como
+// credencial: el resto del entorno del servidor (contraseña de la base de datos, etc.) nunca sale en una petición
+const SecretEnvPrefix = "DAILYNEWS_SECRET_"
+
+// SecretRefPattern detecta referencias ${VARIABLE} a variables de entorno
+var SecretRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// IsSecretEnv indica si name es una variable de entorno que un perfil HTTP puede usar como credencial
+func IsSecretEnv(name string) bool {
+	return strings.HasPrefix(name, SecretEnvPrefix) && len(name) > len(SecretEnvPrefix)
+}
+
+// HTTPProfile agrupa la configuración HTTP que se aplica a las peticiones de una o varias fuentes.
+// Las credenciales no se guardan en claro: SecretEnv es el nombre de la variable de entorno con la
+// contraseña o el token, y los valores de Headers y ProxyURL admiten referencias ${VARIABLE}; en ambos
+// casos solo variables con el prefijo SecretEnvPrefix. Las cabeceras y la autenticación solo se envían
+// al host de la fuente y a los de CredentialHosts.
+type HTTPProfile struct {
+	ID                 uint      `gorm:"primaryKey"`
+	Name               string    `gorm:"size:100;not null;uniqueIndex"` // Nombre del perfil (ej: "Medios con paywall")
+	UserAgent          string    `gorm:"size:255"`                      // User-Agent (vacío = el del fetcher)
+	Headers            *string   `gorm:"type:text"`                     // Cabeceras adicionales en JSON (ej: {"Cookie": "${DAILYNEWS_SECRET_COOKIE}"})
+	AuthType           string    `gorm:"size:20"`                       // "", "basic" o "bearer"
+	Username           string    `gorm:"size:255"`                      // Usuario para autenticación basic
+	SecretEnv          string    `gorm:"size:100"`                      // Variable de entorno con la contraseña o token
+	CredentialHosts    string    `gorm:"size:500"`                      // Otros hosts que reciben cabeceras y autenticación, separados por comas ("*.medio.com" incluye subdominios)
+	ProxyURL           string    `gorm:"size:255"`                      // Proxy HTTP(S) (ej: "http://${DAILYNEWS_SECRET_PROXY}@proxy:3128")
+	TimeoutSeconds     int       `gorm:"default:0"`                     // Timeout por petición (0 = el del fetcher)
+	InsecureSkipVerify bool      `gorm:"default:false"`                 // No verificar certificados TLS
+	MinTLSVersion      string    `gorm:"size:10"`                       // Versión mínima de TLS ("1.2", "1.3")
+	RedirectPolicy     string    `gorm:"size:20"`                       // "follow" (por defecto), "same-host" o "none"
+	MaxRedirects       int       `gorm:"default:0"`                     // Máximo de redirecciones (0 = 10)
+	CreatedAt          time.Time `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla para el modelo HTTPProfile
+func (HTTPProfile) TableName() string {
+	return "http_profiles"
+}
+
+// FetchStats acumula las estadísticas HTTP de una fuente durante una extracción (no se persiste).
+// Es seguro para uso concurrente y sus métodos admiten receptor nil.
+type FetchStats struct {
+	requests      atomic.Int64
+	retries       atomic.Int64
+	failures      atomic.Int64
+	rateLimited   atomic.Int64
+	robotsBlocked atomic.Int64
+}
+
+func (s *FetchStats) AddRequest() {
+	if s != nil {
+		s.requests.Add(1)
+	}
+}
+
+func (s *FetchStats) AddRetry() {
+	if s != nil {
+		s.retries.Add(1)
+	}
+}
+
+func (s *FetchStats) AddFailure() {
+	if s != nil {
+		s.failures.Add(1)
+	}
+}
+
+func (s *FetchStats) AddRateLimited() {
+	if s != nil {
+		s.rateLimited.Add(1)
+	}
+}
+
+func (s *FetchStats) AddRobotsBlocked() {
+	if s != nil {
+		s.robotsBlocked.Add(1)
+	}
+}
+
+// Snapshot devuelve los contadores actuales: peticiones, reintentos, fallos, respuestas 429 y bloqueos por robots.txt
+func (s *FetchStats) Snapshot() (requests, retries, failures, rateLimited, robotsBlocked int) {
+	if s == nil {
+		return 0, 0, 0, 0, 0
+	}
+	return int(s.requests.Load()), int(s.retries.Load()), int(s.failures.Load()), int(s.rateLimited.Load()), int(s.robotsBlocked.Load())
+}
+
+// SourceHealth guarda el resultado de la última extracción de cada fuente
+type SourceHealth struct {
+	ID                  uint       `gorm:"primaryKey"`
+	SourceID            uint       `gorm:"not null;uniqueIndex"` // Fuente a la que pertenece
+	LastRunAt           time.Time  // Última extracción
+	LastSuccessAt       *time.Time // Última extracción sin error
+	LastStatus          string     `gorm:"size:20"`   // "ok" o "error"
+	LastError           string     `gorm:"type:text"` // Error de la última extracción fallida
+	ItemsFetched        int        // Noticias obtenidas del feed en la última extracción
+	ItemsAccepted       int        // Noticias guardadas en la última extracción
+	Requests            int        // Peticiones HTTP (feed + imágenes)
+	Retries             int        // Reintentos por timeouts, 429 o 5xx
+	Failures            int        // Peticiones fallidas tras los reintentos
+	RateLimited         int        // Respuestas 429 recibidas
+	RobotsBlocked       int        // Peticiones bloqueadas por robots.txt
+	ConsecutiveFailures int        // Extracciones fallidas seguidas
+	UpdatedAt           time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla para el modelo SourceHealth
+func (SourceHealth) TableName() string {
+	return "source_health"
+}
+
+// Estados de una suscripción WebSub
+const (
+	WebSubPending      = "pending"      // Solicitada al hub, pendiente de verificación
+	WebSubActive       = "active"       // Verificada: el hub envía el contenido nuevo
+	WebSubDenied       = "denied"       // El hub rechazó la suscripción
+	WebSubUnsubscribed = "unsubscribed" // Baja confirmada por el hub
+	WebSubUnsupported  = "unsupported"  // El feed no declara hub (se vuelve a comprobar más adelante)
+)
+
+// WebSubSubscription es la suscripción WebSub (PubSubHubbub) del feed de una fuente
+type WebSubSubscription struct {
+	ID           uint       `gorm:"primaryKey"`
+	SourceID     uint       `gorm:"not null;uniqueIndex"` // Fuente suscrita
+	Topic        string     `gorm:"type:text"`            // URL del feed (rel="self")
+	Hub          string     `gorm:"type:text"`            // URL del hub (rel="hub")
+	Secret       string     `gorm:"size:64"`              // Clave HMAC compartida con el hub
+	State        string     `gorm:"size:20;index"`
+	LeaseSeconds int        // Duración de la suscripción concedida por el hub
+	ExpiresAt    *time.Time `gorm:"index"` // Fin de la suscripción (se renueva antes)
+	LastError    string     `gorm:"type:text"`
+	LastPushAt   *time.Time // Último contenido recibido del hub
+	CheckedAt    time.Time  // Última detección del hub
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla para el modelo WebSubSubscription
+func (WebSubSubscription) TableName() string {
+	return "websub_subscriptions"
+}
+
+// CachedImage es una imagen de noticia descargada, redimensionada y guardada en local.
+// El archivo se nombra por el hash de su contenido, así que varias URLs pueden compartirlo.
+type CachedImage struct {
+	ID          uint      `gorm:"primaryKey"`
+	URLHash     string    `gorm:"size:64;not null;uniqueIndex"` // SHA-256 de OriginalURL
+	OriginalURL string    `gorm:"type:text;not null"`           // URL de la imagen en el medio
+	ContentHash string    `gorm:"size:64;not null;index"`       // SHA-256 del archivo guardado
+	LocalURL    string    `gorm:"size:255;not null;index"`      // Ruta pública (/images/cache/...)
+	Format      string    `gorm:"size:10"`                      // "webp" o "jpeg"
+	Width       int       // Dimensiones de la versión principal
+	Height      int       //
+	Bytes       int64     // Tamaño de la versión principal y sus versiones reducidas
+	Renditions  string    `gorm:"size:100"` // Anchos de las versiones reducidas generadas ("320,480")
+	LastUsedAt  time.Time `gorm:"index"`    // Última noticia que la usó (para el GC)
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// RenditionWidths devuelve los anchos de las versiones reducidas de la imagen
+func (c *CachedImage) RenditionWidths() []int {
+	var widths []int
+	for _, field := range strings.Split(c.Renditions, ",") {
+		if width, err := strconv.Atoi(strings.TrimSpace(field)); err == nil && width > 0 {
+			widths = append(widths, width)
+		}
+	}
+	return widths
+}
+
+// RenditionURL devuelve la URL local de la versión de width píxeles de ancho (imagen-320w.webp)
+func (c *CachedImage) RenditionURL(width int) string {
+	ext := path.Ext(c.LocalURL)
+	return strings.TrimSuffix(c.LocalURL, ext) + "-" + strconv.Itoa(width) + "w" + ext
+}
+
+// Srcset devuelve el atributo srcset con las versiones reducidas y la principal ("" si no hay reducidas)
+func (c *CachedImage) Srcset() string {
+	widths := c.RenditionWidths()
+	if len(widths) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(widths)+1)
+	for _, width := range widths {
+		parts = append(parts, c.RenditionURL(width)+" "+strconv.Itoa(width)+"w")
+	}
+	parts = append(parts, c.LocalURL+" "+strconv.Itoa(c.Width)+"w")
+	return strings.Join(parts, ", ")
+}
+
+// TableName especifica el nombre de la tabla para el modelo CachedImage
+func (CachedImage) TableName() string {
+	return "cached_images"
+}
+
+// ImageValidation es el resultado guardado de validar una imagen, para no volver a descargarla
+// mientras no caduque
+type ImageValidation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	URLHash     string    `gorm:"size:64;not null;uniqueIndex" json:"-"` // SHA-256 de URL
+	URL         string    `gorm:"type:text;not null" json:"url"`
+	Valid       bool      `json:"valid"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	ContentType string    `gorm:"size:100" json:"contentType"`
+	Bytes       int64     `json:"bytes"`
+	Reason      string    `gorm:"type:text" json:"reason,omitempty"` // Motivo del rechazo
+	PHash       string    `gorm:"size:16" json:"phash,omitempty"`    // Hash perceptual (dHash) de las imágenes válidas
+	BlurHash    string    `gorm:"size:64" json:"blurhash,omitempty"` // Vista previa difuminada de las imágenes válidas
+	NeedsCrop   bool      `json:"needsCrop"`                         // Aspecto fuera de la tolerancia: solo sirve recortada por la caché local
+	CheckedAt   time.Time `gorm:"index" json:"checkedAt"`
+}
+
+// TableName especifica el nombre de la tabla para el modelo ImageValidation
+func (ImageValidation) TableName() string {
+	return "image_validations"
+}
+
+// ImagePlaceholder es una imagen genérica (logo del medio, marcador de posición) que una fuente adjunta
+// a muchas noticias. Las imágenes con un hash perceptual cercano se tratan como noticias sin imagen.
+type ImagePlaceholder struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	SourceID    uint      `gorm:"not null;uniqueIndex:idx_placeholder_source_hash" json:"sourceId"`
+	Hash        string    `gorm:"size:16;not null;uniqueIndex:idx_placeholder_source_hash" json:"hash"` // dHash en hexadecimal
+	ImageURL    string    `gorm:"type:text" json:"imageUrl"`                                            // Imagen en la que se detectó
+	AutoLearned bool      `json:"autoLearned"`                                                          // Aprendida al repetirse en varias noticias
+	Hits        int       `gorm:"default:0" json:"hits"`                                                // Imágenes descartadas por coincidir
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName especifica el nombre de la tabla para el modelo ImagePlaceholder
+func (ImagePlaceholder) TableName() string {
+	return "image_placeholders"
+}
+
+// ImageHashSighting es una imagen válida vista en una noticia de una fuente. Es el historial del que se
+// aprenden las imágenes genéricas: vive en su propia tabla porque las noticias se borran en cada extracción.
+type ImageHashSighting struct {
+	ID       uint      `gorm:"primaryKey"`
+	SourceID uint      `gorm:"not null;uniqueIndex:idx_sighting_source_link_hash;index:idx_sighting_source_seen"`
+	LinkHash string    `gorm:"size:64;not null;uniqueIndex:idx_sighting_source_link_hash"` // SHA-256 del link de la noticia
+	Hash     string    `gorm:"size:16;not null;uniqueIndex:idx_sighting_source_link_hash"` // dHash en hexadecimal
+	SeenAt   time.Time `gorm:"index:idx_sighting_source_seen"`
+}
+
+// TableName especifica el nombre de la tabla para el modelo ImageHashSighting
+func (ImageHashSighting) TableName() string {
+	return "image_hash_sightings"
+}
+
+// ImageHashDistance devuelve los bits distintos entre dos hashes perceptuales en hexadecimal
+// (64, la máxima, si alguno está vacío o no es válido)
+func ImageHashDistance(a, b string) int {
+	x, errA := strconv.ParseUint(a, 16, 64)
+	y, errB := strconv.ParseUint(b, 16, 64)
+	if a == "" || b == "" || errA != nil || errB != nil {
+		return 64
+	}
+	return bits.OnesCount64(x ^ y)
+}
+
+// HashURL devuelve la clave (SHA-256 en hexadecimal) con la que se indexan las URLs de imágenes
+func HashURL(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	// ErrWebSubNotFound indica que la suscripción o el topic del callback WebSub no existen
+	ErrWebSubNotFound = errors.New("suscripción WebSub no encontrada")
+	// ErrWebSubSignature indica que el contenido recibido del hub no tiene una firma HMAC válida
+	ErrWebSubSignature = errors.New("firma WebSub inválida")
+)
+
+// ErrCircuitOpen indica que la petición no se hizo porque el circuito de su host está abierto
+var ErrCircuitOpen = errors.New("circuito abierto para el host")
+
+// Estados del circuit breaker de un host
+const (
+	CircuitClosed   = "closed"    // Las peticiones pasan con normalidad
+	CircuitOpen     = "open"      // Las peticiones se rechazan sin llegar a la red
+	CircuitHalfOpen = "half-open" // Pasada la espera, se deja pasar una petición de prueba
+)
+
+// HostCircuit es el estado del circuit breaker de un host (no se persiste)
+type HostCircuit struct {
+	Host                string     `json:"host"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`  // Última apertura del circuito
+	RetryAt             *time.Time `json:"retryAt,omitempty"`   // Momento en que pasa a half-open
+	Rejected            int64      `json:"rejected"`            // Peticiones rechazadas desde la última apertura
+	LastError           string     `json:"lastError,omitempty"` // Último fallo registrado
+}
+
+// FeedCandidate es un feed o sitemap encontrado al analizar una web (no se persiste)
+type FeedCandidate struct {
+	URL      string // URL del feed o sitemap
+	Type     string // Tipo de fuente (SourceTypeRSS o SourceTypeSitemap)
+	Title    string // Título declarado por el feed o por el enlace
+	Language string // Idioma declarado por el feed o por la página (ej: "es", "en-US")
+	Origin   string // Cómo se encontró: "link", "path" o "sitemap"
+}
+
 // NewsItem representa una noticia procesada
 type NewsItem struct {
 	ID           uint       `gorm:"primaryKey"`          // Identificador único de la noticia
@@ -64,6 +415,27 @@ type NewsItem struct {
 	LangCode     string     `gorm:"size:10;not null"`    // Código de idioma (ej: "es", "en")
 	CategoryCode string     `gorm:"size:50;not null"`    // Código de categoría (ej: "technology")
 	CreatedAt    time.Time  `gorm:"autoCreateTime"`      // Fecha de creación en el sistema
+
+	// Texto completo de la noticia (solo en fuentes con ExtractContent)
+	Content     string `gorm:"type:mediumtext"` // Cuerpo como HTML saneado para la vista de lectura
+	ContentText string `gorm:"type:mediumtext"` // Cuerpo como texto plano (búsqueda)
+	WordCount   int    `gorm:"default:0"`       // Número de palabras del cuerpo
+	ReadingTime int    `gorm:"default:0"`       // Tiempo de lectura estimado en minutos
+
+	// OriginalImage es la URL de la imagen en el medio cuando Image apunta a la caché local
+	OriginalImage string `gorm:"type:text"`
+	// ImageHash es el hash perceptual (dHash) de la imagen, para detectar imágenes genéricas y duplicadas
+	ImageHash string `gorm:"size:16;index"`
+	// Versiones de la imagen para srcset (solo con la caché local) y dimensiones para width/height
+	ImageSrcset string `gorm:"type:text"`
+	ImageWidth  int    `gorm:"default:0"`
+	ImageHeight int    `gorm:"default:0"`
+	// ImageBlurHash es la vista previa difuminada (BlurHash) que se muestra mientras carga la imagen
+	ImageBlurHash string `gorm:"size:64"`
+
+	// ImageCandidates son las imágenes alternativas del feed ordenadas de mejor a peor ajuste
+	// (la primera es Image). No se persiste: sirve para probar la siguiente si una se rechaza.
+	ImageCandidates []string `gorm:"-" json:"-"`
 }
 
 // TableName especifica el nombre de la tabla para el modelo NewsItem
@@ -73,14 +445,19 @@ func (NewsItem) TableName() string {
 
 // NewsItemDTO es una representación simplificada de NewsItem para la API
 type NewsItemDTO struct {
-	ID       uint      `json:"id"`        // Identificador único de la noticia
-	Title    string    `json:"title"`     // Titular de la noticia
-	Link     string    `json:"link"`      // Link a la noticia original
-	Image    string    `json:"image"`     // URL de la imagen principal
-	Source   string    `json:"source"`    // Nombre de la fuente RSS
-	Date     time.Time `json:"date"`      // Fecha de publicación
-	LangCode string    `json:"lang_code"` // Código de idioma
-	Category string    `json:"category"`  // Código de categoría
+	ID       uint      `json:"id"`                 // Identificador único de la noticia
+	Title    string    `json:"title"`              // Titular de la noticia
+	Link     string    `json:"link"`               // Link a la noticia original
+	Image    string    `json:"image"`              // URL de la imagen principal
+	BlurHash string    `json:"blurhash,omitempty"` // Vista previa difuminada de la imagen (https://blurha.sh)
+	Source   string    `json:"source"`             // Nombre de la fuente RSS
+	Date     time.Time `json:"date"`               // Fecha de publicación
+	LangCode string    `json:"lang_code"`          // Código de idioma
+	Category string    `json:"category"`           // Código de categoría
+
+	WordCount   int  `json:"word_count,omitempty"`   // Palabras del texto completo (0 = no extraído)
+	ReadingTime int  `json:"reading_time,omitempty"` // Minutos de lectura estimados
+	HasContent  bool `json:"has_content"`            // Hay vista de lectura disponible
 }
 
 // ToDTO convierte un NewsItem a NewsItemDTO
@@ -90,16 +467,32 @@ func (n *NewsItem) ToDTO() *NewsItemDTO {
 		Title:    n.Title,
 		Link:     n.Link,
 		Image:    n.Image,
+		BlurHash: n.ImageBlurHash,
 		Source:   n.Source.SourceName,
 		Date:     n.PubDate,
 		LangCode: n.LangCode,
 		Category: n.CategoryCode,
+
+		WordCount:   n.WordCount,
+		ReadingTime: n.ReadingTime,
+		HasContent:  n.Content != "",
 	}
 }
 
-// FallbackImage representa una imagen de respaldo para una categoría+idioma
+// ArticleContent es el cuerpo de una noticia extraído de su página
+type ArticleContent struct {
+	HTML        string // HTML saneado (párrafos, títulos, listas, citas, enlaces e imágenes)
+	Text        string // Texto plano, con los párrafos separados por líneas en blanco
+	WordCount   int
+	ReadingTime int // Minutos
+}
+
+// FallbackImage representa una imagen de respaldo para las noticias sin imagen. Su nivel sale de los campos:
+// de una fuente (SourceID o NewsSource.FallbackImageID), de categoría+idioma, de categoría (LanguageCode vacío)
+// o global (ambos códigos vacíos). Varias imágenes del mismo nivel forman un conjunto que se reparte por noticia.
 type FallbackImage struct {
 	ID           uint      `gorm:"primaryKey"`
+	SourceID     *uint     `gorm:"index"` // Fuente propietaria (nil = compartida por categoría/idioma)
 	CategoryCode string    `gorm:"size:50;not null;index"`
 	LanguageCode string    `gorm:"size:10;not null;index"`
 	Filename     string    `gorm:"size:255;not null"`
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImageHashDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"iguales", "0f0f0f0f0f0f0f0f", "0f0f0f0f0f0f0f0f", 0},
+		{"un bit", "0000000000000000", "0000000000000001", 1},
+		{"cuatro bits", "0000000000000000", "000000000000000f", 4},
+		{"todos", "0000000000000000", "ffffffffffffffff", 64},
+		{"mayúsculas", "ABCDEF0123456789", "abcdef0123456789", 0},
+		{"simétrica", "ffffffffffffffff", "7fffffffffffffff", 1},
+		{"vacío", "", "0000000000000000", 64},
+		{"ambos vacíos", "", "", 64},
+		{"no hexadecimal", "zzzzzzzzzzzzzzzz", "0000000000000000", 64},
+		{"demasiado largo", "10000000000000000", "0000000000000000", 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageHashDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("ImageHashDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedImageRenditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      CachedImage
+		wantWidths []int
+		wantSrcset string
+	}{
+		{
+			"sin versiones reducidas",
+			CachedImage{LocalURL: "/images/cache/ab/abcd.webp", Width: 800},
+			nil,
+			"",
+		},
+		{
+			"dos versiones",
+			CachedImage{LocalURL: "/images/cache/ab/abcd.webp", Width: 800, Renditions: "320,480"},
+			[]int{320, 480},
+			"/images/cache/ab/abcd-320w.webp 320w, /images/cache/ab/abcd-480w.webp 480w, /images/cache/ab/abcd.webp 800w",
+		},
+		{
+			"JPEG con espacios y valores inválidos",
+			CachedImage{LocalURL: "/images/cache/cd/cdef.jpg", Width: 640, Renditions: " 320 , x, 0,-1,"},
+			[]int{320},
+			"/images/cache/cd/cdef-320w.jpg 320w, /images/cache/cd/cdef.jpg 640w",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.image.RenditionWidths(); !reflect.DeepEqual(got, tt.wantWidths) {
+				t.Errorf("RenditionWidths = %v, want %v", got, tt.wantWidths)
+			}
+			if got := tt.image.Srcset(); got != tt.wantSrcset {
+				t.Errorf("Srcset = %q, want %q", got, tt.wantSrcset)
+			}
+		})
+	}
+}
+
+func TestCachedImageRenditionURL(t *testing.T) {
+	tests := []struct {
+		localURL string
+		width    int
+		want     string
+	}{
+		{"/images/cache/ab/abcd.webp", 320, "/images/cache/ab/abcd-320w.webp"},
+		{"/images/cache/ab/abcd.jpg", 480, "/images/cache/ab/abcd-480w.jpg"},
+		{"/images/cache/ab/abcd", 320, "/images/cache/ab/abcd-320w"},
+	}
+	for _, tt := range tests {
+		image := CachedImage{LocalURL: tt.localURL}
+		if got := image.RenditionURL(tt.width); got != tt.want {
+			t.Errorf("RenditionURL(%q, %d) = %q, want %q", tt.localURL, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"dailynews/internal/domain"
+)
+
+// Valores por defecto del extractor de texto de noticias
+const (
+	defaultArticleContentMaxBytes = 2 * 1024 * 1024
+	defaultArticleContentTimeout  = 15 * time.Second
+	defaultWordsPerMinute         = 200
+	minArticleWords               = 80 // Por debajo se asume muro de pago o página sin artículo
+	minParagraphChars             = 25
+)
+
+// articleNoiseSelector son los elementos que nunca forman parte del cuerpo del artículo
+const articleNoiseSelector = "script, style, noscript, iframe, form, nav, header, footer, aside, svg, button, " +
+	"input, select, textarea, [role=navigation], [role=banner], [role=contentinfo], [aria-hidden=true]"
+
+var (
+	// positiveClassPattern y negativeClassPattern ajustan la puntuación de un bloque según su class e id
+	positiveClassPattern = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text|cuerpo|noticia`)
+	negativeClassPattern = regexp.MustCompile(`(?i)comment|footer|sidebar|share|social|related|promo|advert|banner|newsletter|subscri|nav|menu|widget|cookie|popup|breadcrumb`)
+)
+
+// allowedContentTags son las etiquetas que se conservan en el HTML saneado; el resto se sustituye por su contenido
+var allowedContentTags = map[string]bool{
+	"p": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "blockquote": true, "pre": true, "code": true,
+	"strong": true, "em": true, "b": true, "i": true, "a": true, "br": true,
+	"figure": true, "figcaption": true, "img": true,
+}
+
+// articleContentExtractor implementa domain.ArticleContentExtractor con un algoritmo tipo readability:
+// puntúa los bloques por sus párrafos y se queda con el mejor
+type articleContentExtractor struct {
+	httpClient     *http.Client
+	maxBytes       int64
+	wordsPerMinute int
+}
+
+// NewArticleContentExtractor crea un extractor del texto completo de las noticias.
+// maxBytes, timeout y wordsPerMinute admiten 0 para usar los valores por defecto.
+func NewArticleContentExtractor(transport http.RoundTripper, maxBytes int64, timeout time.Duration, wordsPerMinute int) domain.ArticleContentExtractor {
+	if maxBytes <= 0 {
+		maxBytes = defaultArticleContentMaxBytes
+	}
+	if timeout <= 0 {
+		timeout = defaultArticleContentTimeout
+	}
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = defaultWordsPerMinute
+	}
+	return &articleContentExtractor{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+		maxBytes:       maxBytes,
+		wordsPerMinute: wordsPerMinute,
+	}
+}
+
+// Extract descarga la noticia y devuelve su cuerpo como HTML saneado y texto plano
+func (e *articleContentExtractor) Extract(ctx context.Context, articleURL string) (*domain.ArticleContent, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(articleURL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") {
+		return nil, fmt.Errorf("URL de noticia inválida: %s", articleURL)
+	}
+
+	client := httpClientFor(ctx, e.httpClient)
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	req, err := newSourceRequest(ctx, pageURL.String(), browserUserAgent, "text/html,application/xhtml+xml")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener la noticia: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, e.maxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error al parsear HTML: %w", err)
+	}
+	base := resp.Request.URL
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if parsed, err := base.Parse(strings.TrimSpace(href)); err == nil {
+			base = parsed
+		}
+	}
+
+	doc.Find(articleNoiseSelector).Remove()
+	body := findArticleBody(doc)
+	if body == nil {
+		return nil, fmt.Errorf("no se encontró el cuerpo de la noticia")
+	}
+
+	var htmlOut, textOut strings.Builder
+	for _, node := range body.Nodes {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			renderSanitized(&htmlOut, child, base)
+		}
+	}
+	body.Find("p, h2, h3, h4, h5, h6, li, blockquote, pre, figcaption").Each(func(_ int, s *goquery.Selection) {
+		// Solo bloques hoja: los anidados ya aportan su texto por separado
+		if s.Find("p, li, blockquote").Length() > 0 {
+			return
+		}
+		if text := strings.Join(strings.Fields(s.Text()), " "); text != "" {
+			if textOut.Len() > 0 {
+				textOut.WriteString("\n\n")
+			}
+			textOut.WriteString(text)
+		}
+	})
+
+	text := textOut.String()
+	words := len(strings.Fields(text))
+	if words < minArticleWords {
+		return nil, fmt.Errorf("texto insuficiente (%d palabras)", words)
+	}
+	return &domain.ArticleContent{
+		HTML:        strings.TrimSpace(htmlOut.String()),
+		Text:        text,
+		WordCount:   words,
+		ReadingTime: int(math.Ceil(float64(words) / float64(e.wordsPerMinute))),
+	}, nil
+}
+
+// findArticleBody devuelve el contenedor del artículo: el marcado semántico si lo hay y,
+// si no, el bloque con mayor puntuación según sus párrafos
+func findArticleBody(doc *goquery.Document) *goquery.Selection {
+	for _, selector := range []string{`[itemprop="articleBody"]`, "article"} {
+		if sel := doc.Find(selector); sel.Length() > 0 {
+			// Con varios <article> (relacionadas, comentarios) el de más texto es el principal
+			best := sel.First()
+			sel.Each(func(_ int, s *goquery.Selection) {
+				if len(s.Find("p").Text()) > len(best.Find("p").Text()) {
+					best = s
+				}
+			})
+			if best.Find("p").Length() > 0 {
+				return best
+			}
+		}
+	}
+
+	scores := make(map[*html.Node]float64)
+	doc.Find("p").Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		if len(text) < minParagraphChars {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+		parent := p.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		scores[parent.Nodes[0]] += score
+		if grandparent := parent.Parent(); grandparent.Length() > 0 {
+			scores[grandparent.Nodes[0]] += score / 2
+		}
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for node, score := range scores {
+		score *= 1 + classWeight(node)
+		if score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return doc.FindNodes(best)
+}
+
+// classWeight ajusta la puntuación de un bloque según los nombres de su class e id
+func classWeight(node *html.Node) float64 {
+	weight := 0.0
+	for _, attr := range node.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+		if negativeClassPattern.MatchString(attr.Val) {
+			weight -= 0.5
+		}
+		if positiveClassPattern.MatchString(attr.Val) {
+			weight += 0.25
+		}
+	}
+	return math.Max(weight, -0.9)
+}
+
+// renderSanitized escribe el nodo conservando solo las etiquetas y atributos permitidos.
+// Los enlaces e imágenes se resuelven respecto a base y solo se admiten URLs http(s).
+func renderSanitized(out *strings.Builder, node *html.Node, base *url.URL) {
+	switch node.Type {
+	case html.TextNode:
+		out.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	tag := node.Data
+	if !allowedContentTags[tag] {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			renderSanitized(out, child, base)
+		}
+		return
+	}
+
+	var attrs string
+	switch tag {
+	case "a":
+		if href := resolveURL(base, attrValue(node, "href")); href != "" {
+			attrs = ` href="` + html.EscapeString(href) + `" rel="noopener noreferrer" target="_blank"`
+		}
+	case "img":
+		src := attrValue(node, "data-src")
+		if src == "" {
+			src = attrValue(node, "src")
+		}
+		src = resolveURL(base, src)
+		if src == "" {
+			return
+		}
+		out.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(attrValue(node, "alt")) + `" loading="lazy">`)
+		return
+	case "br":
+		out.WriteString("<br>")
+		return
+	}
+
+	out.WriteString("<" + tag + attrs + ">")
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		renderSanitized(out, child, base)
+	}
+	out.WriteString("</" + tag + ">")
+}
+
+// attrValue devuelve el valor de un atributo del nodo ("" si no lo tiene)
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// loremParagraph genera un párrafo de n palabras
+func loremParagraph(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("palabra%d", i)
+	}
+	return strings.Join(words, " ")
+}
+
+func TestArticleContentExtractorExtract(t *testing.T) {
+	page := fmt.Sprintf(`<!doctype html><html><body>
+<nav><p>Menú con muchos enlaces, secciones, portada, deportes</p></nav>
+<div class="contenido-noticia">
+  <h2>Subtítulo</h2>
+  <p>%s, con comas, que suman puntos.</p>
+  <p>%s <a href="/otra" onclick="x()">enlace</a> <a href="javascript:alert(1)">malo</a></p>
+  <figure><img data-src="img/foto.jpg" alt="Foto &amp; pie" width="800"><figcaption>Pie de foto</figcaption></figure>
+  <script>alert("fuera")</script>
+  <div class="share"><span>Compartir</span></div>
+</div>
+<div class="sidebar comments"><p>%s, comentario largo, con comas, muchas comas, más comas.</p></div>
+</body></html>`, loremParagraph(60), loremParagraph(60), loremParagraph(20))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	content, err := NewArticleContentExtractor(nil, 0, 0, 50).Extract(context.Background(), server.URL+"/noticias/1")
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	// Subtítulo (1) + 2 párrafos de 60 palabras más su cola + pie de foto (3)
+	if content.WordCount < 120 || content.WordCount > 135 {
+		t.Errorf("WordCount = %d", content.WordCount)
+	}
+	if want := (content.WordCount + 49) / 50; content.ReadingTime != want {
+		t.Errorf("ReadingTime = %d, want %d", content.ReadingTime, want)
+	}
+	if strings.Contains(content.Text, "comentario largo") || strings.Contains(content.Text, "Menú") {
+		t.Errorf("el texto incluye bloques ajenos al artículo: %q", content.Text)
+	}
+	if !strings.HasPrefix(content.Text, "Subtítulo\n\npalabra0") || !strings.HasSuffix(content.Text, "Pie de foto") {
+		t.Errorf("Text = %q", content.Text)
+	}
+
+	for _, want := range []string{
+		`<h2>Subtítulo</h2>`,
+		`<a href="` + server.URL + `/otra" rel="noopener noreferrer" target="_blank">enlace</a>`,
+		`<a>malo</a>`,
+		`<img src="` + server.URL + `/noticias/img/foto.jpg" alt="Foto &amp; pie" loading="lazy">`,
+		`<figcaption>Pie de foto</figcaption>`,
+	} {
+		if !strings.Contains(content.HTML, want) {
+			t.Errorf("HTML no contiene %q:\n%s", want, content.HTML)
+		}
+	}
+	for _, unwanted := range []string{"onclick", "<script", "alert(", "<div", "<span", "width="} {
+		if strings.Contains(content.HTML, unwanted) {
+			t.Errorf("HTML contiene %q:\n%s", unwanted, content.HTML)
+		}
+	}
+}
+
+func TestArticleContentExtractorErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/muro":
+			w.Write([]byte(`<article><p>Contenido exclusivo para suscriptores, suscríbete para seguir leyendo.</p></article>`))
+		case "/vacia":
+			w.Write([]byte(`<html><body><div>Sin párrafos</div></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	extractor := NewArticleContentExtractor(nil, 0, 0, 0)
+	for _, articleURL := range []string{"mailto:a@b.c", server.URL + "/muro", server.URL + "/vacia", server.URL + "/404"} {
+		if _, err := extractor.Extract(context.Background(), articleURL); err == nil {
+			t.Errorf("Extract(%q): expected error", articleURL)
+		}
+	}
+}
+
+func TestFindArticleBody(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want string // id del contenedor elegido ("" = ninguno)
+	}{
+		{
+			"articleBody tiene prioridad",
+			`<article id="a"><p>texto largo del artículo principal aquí</p></article><div id="b" itemprop="articleBody"><p>cuerpo</p></div>`,
+			"b",
+		},
+		{
+			"el article con más texto",
+			`<article id="rel"><p>corto</p></article><article id="main"><p>` + loremParagraph(30) + `</p></article>`,
+			"main",
+		},
+		{
+			"puntuación por párrafos y clases",
+			`<div id="promo" class="promo"><p>` + loremParagraph(40) + `</p></div><div id="story" class="story-body"><p>` + loremParagraph(30) + `, con comas, varias</p></div>`,
+			"story",
+		},
+		{"sin párrafos suficientes", `<div id="x"><p>corto</p></div>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatalf("HTML inválido: %v", err)
+			}
+			body := findArticleBody(doc)
+			got := ""
+			if body != nil {
+				got = body.AttrOr("id", "")
+			}
+			if got != tt.want {
+				t.Errorf("findArticleBody = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassWeight(t *testing.T) {
+	tests := []struct {
+		attrs []html.Attribute
+		want  float64
+	}{
+		{nil, 0},
+		{[]html.Attribute{{Key: "class", Val: "article-body"}}, 0.25},
+		{[]html.Attribute{{Key: "id", Val: "sidebar"}}, -0.5},
+		{[]html.Attribute{{Key: "class", Val: "content"}, {Key: "id", Val: "main"}}, 0.5},
+		{[]html.Attribute{{Key: "class", Val: "comments"}, {Key: "id", Val: "social-share"}}, -0.9},
+	}
+	for _, tt := range tests {
+		if got := classWeight(&html.Node{Attr: tt.attrs}); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("classWeight(%v) = %v, want %v", tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestRenderSanitized(t *testing.T) {
+	base, _ := url.Parse("https://example.com/n/1")
+	tests := []struct {
+		fragment string
+		want     string
+	}{
+		{`<p class="x" style="color:red">Hola <b>mundo</b></p>`, `<p>Hola <b>mundo</b></p>`},
+		{`<section><span>texto</span></section>`, `texto`},
+		{`<p>a<br/>b</p>`, `<p>a<br>b</p>`},
+		{`<img src="data:image/png;base64,AAA">`, ``},
+		{`<img src="/a.jpg" alt="&quot;x&quot;">`, `<img src="https://example.com/a.jpg" alt="&#34;x&#34;" loading="lazy">`},
+		{`<p>1 &lt; 2</p>`, `<p>1 &lt; 2</p>`},
+	}
+	for _, tt := range tests {
+		nodes, err := html.ParseFragment(strings.NewReader(tt.fragment), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+		if err != nil {
+			t.Fatalf("fragmento inválido: %v", err)
+		}
+		var out strings.Builder
+		for _, node := range nodes {
+			renderSanitized(&out, node, base)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("renderSanitized(%q) = %q, want %q", tt.fragment, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"dailynews/internal/domain"
+)
+
+// Límites por defecto de la descarga de la página de una noticia
+const (
+	defaultArticlePageMaxBytes = 1024 * 1024
+	defaultArticlePageTimeout  = 10 * time.Second
+)
+
+// articleImageMetaTags son las etiquetas <meta> con la imagen de la página, por prioridad
+var articleImageMetaTags = []string{"og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src"}
+
+// articleImageExtractor implementa domain.ArticleImageExtractor leyendo og:image,
+// twitter:image y el image de JSON-LD de la página de la noticia
+type articleImageExtractor struct {
+	httpClient *http.Client
+	maxBytes   int64
+	target     imageTarget
+}
+
+// NewArticleImageExtractor crea un extractor de imágenes de páginas de noticias.
+// maxBytes y timeout limitan cada descarga (0 = valores por defecto).
+func NewArticleImageExtractor(transport http.RoundTripper, maxBytes int64, timeout time.Duration, targetAspect float64, targetWidth, targetHeight int) domain.ArticleImageExtractor {
+	if maxBytes <= 0 {
+		maxBytes = defaultArticlePageMaxBytes
+	}
+	if timeout <= 0 {
+		timeout = defaultArticlePageTimeout
+	}
+	return &articleImageExtractor{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+		maxBytes: maxBytes,
+		target:   imageTarget{aspect: targetAspect, width: targetWidth, height: targetHeight},
+	}
+}
+
+// ExtractImages descarga la página de la noticia y devuelve sus imágenes ordenadas por ajuste al objetivo
+func (e *articleImageExtractor) ExtractImages(ctx context.Context, articleURL string) ([]string, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(articleURL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") {
+		return nil, fmt.Errorf("URL de noticia inválida: %s", articleURL)
+	}
+
+	client := httpClientFor(ctx, e.httpClient)
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	req, err := newSourceRequest(ctx, pageURL.String(), browserUserAgent, "text/html,application/xhtml+xml")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener la página de la noticia: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+
+	// Una página truncada por el límite sigue siendo útil: las etiquetas <meta> van en el <head>
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, e.maxBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error al parsear HTML: %w", err)
+	}
+
+	// Las redirecciones cambian la base de las URLs relativas
+	base := resp.Request.URL
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if parsed, err := base.Parse(strings.TrimSpace(href)); err == nil {
+			base = parsed
+		}
+	}
+
+	var candidates []imageCandidate
+	seen := make(map[string]bool)
+	add := func(c imageCandidate) {
+		c.url = resolveURL(base, c.url)
+		if c.url == "" || seen[c.url] || isTrackingImage(c) {
+			return
+		}
+		seen[c.url] = true
+		candidates = append(candidates, c)
+	}
+
+	for _, c := range metaImageCandidates(doc) {
+		add(c)
+	}
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		var data interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return
+		}
+		for _, c := range jsonLDImages(data) {
+			add(c)
+		}
+	})
+
+	return rankImageCandidates(candidates, e.target), nil
+}
+
+// metaImageCandidates lee las etiquetas og:image y twitter:image. Las dimensiones og:image:width
+// y og:image:height se asocian a la og:image que las precede.
+func metaImageCandidates(doc *goquery.Document) []imageCandidate {
+	priority := make(map[string]int, len(articleImageMetaTags))
+	for i, tag := range articleImageMetaTags {
+		priority[tag] = i
+	}
+
+	type metaImage struct {
+		imageCandidate
+		priority int
+	}
+	var images []metaImage
+	doc.Find("meta").Each(func(_ int, s *goquery.Selection) {
+		name, _ := s.Attr("property")
+		if name == "" {
+			name, _ = s.Attr("name")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		content := strings.TrimSpace(s.AttrOr("content", ""))
+		if content == "" {
+			return
+		}
+
+		switch name {
+		case "og:image:width", "og:image:height":
+			if len(images) == 0 {
+				return
+			}
+			value, _ := strconv.Atoi(content)
+			last := &images[len(images)-1]
+			if name == "og:image:width" {
+				last.width = value
+			} else {
+				last.height = value
+			}
+		default:
+			if p, ok := priority[name]; ok {
+				images = append(images, metaImage{imageCandidate: imageCandidate{url: content}, priority: p})
+			}
+		}
+	})
+
+	// Orden estable por prioridad de etiqueta; og:image es la imagen que el medio elige para compartir
+	var candidates []imageCandidate
+	for p := range articleImageMetaTags {
+		for _, img := range images {
+			if img.priority == p {
+				c := img.imageCandidate
+				c.preferred = true
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	return candidates
+}
+
+// jsonLDImages recorre un documento JSON-LD (incluidos @graph y objetos anidados) y devuelve
+// los valores de "image": cadenas, listas u objetos ImageObject con url, width y height
+func jsonLDImages(data interface{}) []imageCandidate {
+	var candidates []imageCandidate
+	switch v := data.(type) {
+	case []interface{}:
+		for _, el := range v {
+			candidates = append(candidates, jsonLDImages(el)...)
+		}
+	case map[string]interface{}:
+		if image, ok := v["image"]; ok {
+			candidates = append(candidates, jsonLDImageValue(image)...)
+		}
+		for key, value := range v {
+			if key == "image" {
+				continue
+			}
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				candidates = append(candidates, jsonLDImages(value)...)
+			}
+		}
+	}
+	return candidates
+}
+
+// jsonLDImageValue interpreta el valor de una propiedad "image" de JSON-LD
+func jsonLDImageValue(value interface{}) []imageCandidate {
+	switch v := value.(type) {
+	case string:
+		return []imageCandidate{{url: v}}
+	case []interface{}:
+		var candidates []imageCandidate
+		for _, el := range v {
+			candidates = append(candidates, jsonLDImageValue(el)...)
+		}
+		return candidates
+	case map[string]interface{}:
+		src, _ := v["url"].(string)
+		if src == "" {
+			src, _ = v["contentUrl"].(string)
+		}
+		if src == "" {
+			return nil
+		}
+		return []imageCandidate{{url: src, width: jsonLDInt(v["width"]), height: jsonLDInt(v["height"])}}
+	}
+	return nil
+}
+
+// jsonLDInt lee una dimensión de JSON-LD, que puede ser número, cadena o QuantitativeValue
+func jsonLDInt(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(v), "px"))
+		return n
+	case map[string]interface{}:
+		return jsonLDInt(v["value"])
+	}
+	return 0
+}
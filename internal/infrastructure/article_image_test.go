@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const articlePageFixture = `<!doctype html>
+<html><head>
+<base href="/static/">
+<meta name="twitter:image" content="twitter.jpg">
+<meta property="og:image" content="og-pequena.jpg">
+<meta property="og:image:width" content="400">
+<meta property="og:image:height" content="225">
+<meta property="og:image" content="og-grande.jpg">
+<meta property="og:image:width" content="1200">
+<meta property="og:image:height" content="675">
+<meta property="og:image" content="https://tracker.example/pixel.gif">
+<meta property="og:image:width" content="1">
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "NewsArticle", "image": {"@type": "ImageObject", "url": "/ld/portada.jpg", "width": 1600, "height": "900px"}},
+  {"@type": "Organization", "logo": {"@type": "ImageObject", "url": "/logo.png"}}
+]}
+</script>
+<script type="application/ld+json">no es JSON</script>
+</head><body><img src="cuerpo.jpg"></body></html>`
+
+func TestArticleImageExtractorExtractImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/antigua" {
+			http.Redirect(w, r, "/noticias/1", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte(articlePageFixture))
+	}))
+	defer server.Close()
+
+	extractor := NewArticleImageExtractor(nil, 0, 0, 16.0/9.0, 800, 450)
+	got, err := extractor.ExtractImages(context.Background(), server.URL+"/antigua")
+	if err != nil {
+		t.Fatalf("ExtractImages: %v", err)
+	}
+
+	// Las etiquetas <meta> son preferidas; entre ellas, og:image antes que twitter:image y por ajuste
+	want := []string{
+		server.URL + "/static/og-grande.jpg",
+		server.URL + "/static/og-pequena.jpg",
+		server.URL + "/static/twitter.jpg",
+		server.URL + "/ld/portada.jpg",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractImages:\n got  %v\n want %v", got, want)
+	}
+}
+
+func TestArticleImageExtractorErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	extractor := NewArticleImageExtractor(nil, 0, 0, 16.0/9.0, 800, 450)
+	for _, articleURL := range []string{"ftp://example.com/x", "::", server.URL + "/no-existe"} {
+		if _, err := extractor.ExtractImages(context.Background(), articleURL); err == nil {
+			t.Errorf("ExtractImages(%q): expected error", articleURL)
+		}
+	}
+}
+
+func TestJSONLDImages(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []imageCandidate
+	}{
+		{"cadena", `{"image": "a.jpg"}`, []imageCandidate{{url: "a.jpg"}}},
+		{"lista", `{"image": ["a.jpg", "b.jpg"]}`, []imageCandidate{{url: "a.jpg"}, {url: "b.jpg"}}},
+		{
+			"ImageObject con QuantitativeValue",
+			`{"image": {"contentUrl": "a.jpg", "width": {"value": 800}, "height": "450"}}`,
+			[]imageCandidate{{url: "a.jpg", width: 800, height: 450}},
+		},
+		{"ImageObject sin URL", `{"image": {"width": 800}}`, nil},
+		{"anidado", `[{"mainEntity": {"image": "a.jpg"}}, {"@graph": [{"image": "b.jpg"}]}]`, []imageCandidate{{url: "a.jpg"}, {url: "b.jpg"}}},
+		{"sin imagen", `{"logo": "l.png"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data interface{}
+			if err := json.Unmarshal([]byte(tt.doc), &data); err != nil {
+				t.Fatalf("fixture JSON inválido: %v", err)
+			}
+			got := jsonLDImages(data)
+			// Los objetos se recorren en orden de mapa: comparar sin orden
+			sort.Slice(got, func(i, j int) bool { return got[i].url < got[j].url })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jsonLDImages = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
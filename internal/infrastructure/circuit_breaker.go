@@ -0,0 +1,178 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// circuitOutcome es el resultado de una petición a efectos del circuit breaker
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	circuitIgnored // Cancelada por el llamador o bloqueada antes de llegar al host
+)
+
+// circuitBreaker es el circuit breaker de un host: se abre tras threshold fallos seguidos,
+// rechaza peticiones durante cooldown y después deja pasar una sola petición de prueba
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+	rejected            int64
+	lastError           string
+}
+
+// allow indica si la petición puede salir; en half-open solo pasa una petición de prueba a la vez
+func (b *circuitBreaker) allow(cooldown time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case domain.CircuitOpen:
+		if time.Since(b.openedAt) < cooldown {
+			b.rejected++
+			return false
+		}
+		b.state = domain.CircuitHalfOpen
+		b.probing = true
+		return true
+	case domain.CircuitHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record registra el resultado de la petición y devuelve true si el circuito acaba de abrirse
+func (b *circuitBreaker) record(outcome circuitOutcome, threshold int, errMsg string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasProbe := b.state == domain.CircuitHalfOpen
+	if wasProbe {
+		b.probing = false
+	}
+
+	switch outcome {
+	case circuitSuccess:
+		b.state = domain.CircuitClosed
+		b.consecutiveFailures = 0
+		b.rejected = 0
+	case circuitFailure:
+		b.consecutiveFailures++
+		b.lastError = errMsg
+		// Una prueba fallida vuelve a abrir el circuito sin esperar al umbral
+		if wasProbe || (b.state != domain.CircuitOpen && b.consecutiveFailures >= threshold) {
+			b.state = domain.CircuitOpen
+			b.openedAt = time.Now()
+			b.rejected = 0
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot devuelve el estado público del circuito
+func (b *circuitBreaker) snapshot(host string, cooldown time.Duration) domain.HostCircuit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	circuit := domain.HostCircuit{
+		Host:                host,
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Rejected:            b.rejected,
+		LastError:           b.lastError,
+	}
+	if !b.openedAt.IsZero() && b.state != domain.CircuitClosed {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(cooldown)
+		circuit.OpenedAt = &openedAt
+		circuit.RetryAt = &retryAt
+	}
+	return circuit
+}
+
+// breaker devuelve el circuit breaker del host, creándolo si no existe
+func (t *HostTransport) breaker(host string) *circuitBreaker {
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	b, ok := t.state.breakers[host]
+	if !ok {
+		b = &circuitBreaker{state: domain.CircuitClosed}
+		t.state.breakers[host] = b
+	}
+	return b
+}
+
+// recordCircuit clasifica el resultado final de una petición y lo registra en el circuito del host
+func (t *HostTransport) recordCircuit(b *circuitBreaker, host string, resp *http.Response, err error) {
+	outcome, errMsg := circuitSuccess, ""
+	switch {
+	case err != nil && errors.Is(err, context.Canceled):
+		outcome = circuitIgnored
+	case err != nil:
+		outcome, errMsg = circuitFailure, err.Error()
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		outcome, errMsg = circuitFailure, resp.Status
+	}
+
+	if b.record(outcome, t.policy.CircuitFailureThreshold, errMsg) {
+		utils.AppWarn("CIRCUIT_BREAKER", "Circuito abierto para el host", map[string]interface{}{
+			"host":     host,
+			"cooldown": t.policy.CircuitCooldown.String(),
+			"error":    errMsg,
+		})
+	}
+}
+
+// CircuitStates devuelve el estado de los circuitos de todos los hosts contactados, ordenados por host
+func (t *HostTransport) CircuitStates() []domain.HostCircuit {
+	t.state.mu.Lock()
+	hosts := make(map[string]*circuitBreaker, len(t.state.breakers))
+	for host, b := range t.state.breakers {
+		hosts[host] = b
+	}
+	t.state.mu.Unlock()
+
+	circuits := make([]domain.HostCircuit, 0, len(hosts))
+	for host, b := range hosts {
+		circuits = append(circuits, b.snapshot(host, t.policy.CircuitCooldown))
+	}
+	sort.Slice(circuits, func(i, j int) bool { return circuits[i].Host < circuits[j].Host })
+	return circuits
+}
+
+// ResetCircuit cierra manualmente el circuito de un host
+func (t *HostTransport) ResetCircuit(host string) bool {
+	t.state.mu.Lock()
+	b, ok := t.state.breakers[strings.ToLower(host)]
+	t.state.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	b.mu.Lock()
+	b.state = domain.CircuitClosed
+	b.consecutiveFailures = 0
+	b.probing = false
+	b.rejected = 0
+	b.mu.Unlock()
+	return true
+}
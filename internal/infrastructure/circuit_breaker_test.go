@@ -0,0 +1,177 @@
+package infrastructure
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dailynews/internal/domain"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	const threshold = 3
+	const cooldown = 20 * time.Millisecond
+
+	type step struct {
+		action string // "allow", "success", "failure", "ignored" o "wait"
+		want   bool   // resultado de allow, o si el circuito acaba de abrirse
+		state  string // estado tras el paso
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"se abre al alcanzar el umbral", []step{
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"failure", true, domain.CircuitOpen},
+			{"allow", false, domain.CircuitOpen},
+		}},
+		{"un éxito reinicia la cuenta de fallos", []step{
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"success", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+		}},
+		{"los resultados ignorados no cuentan", []step{
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"ignored", false, domain.CircuitClosed},
+			{"allow", true, domain.CircuitClosed},
+		}},
+		{"tras el cooldown una sola prueba; si sale bien se cierra", []step{
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"failure", true, domain.CircuitOpen},
+			{"wait", false, domain.CircuitOpen},
+			{"allow", true, domain.CircuitHalfOpen},
+			{"allow", false, domain.CircuitHalfOpen},
+			{"success", false, domain.CircuitClosed},
+			{"allow", true, domain.CircuitClosed},
+		}},
+		{"una prueba fallida vuelve a abrir el circuito", []step{
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"failure", true, domain.CircuitOpen},
+			{"wait", false, domain.CircuitOpen},
+			{"allow", true, domain.CircuitHalfOpen},
+			{"failure", true, domain.CircuitOpen},
+			{"allow", false, domain.CircuitOpen},
+		}},
+		{"una prueba ignorada libera el turno de prueba", []step{
+			{"failure", false, domain.CircuitClosed},
+			{"failure", false, domain.CircuitClosed},
+			{"failure", true, domain.CircuitOpen},
+			{"wait", false, domain.CircuitOpen},
+			{"allow", true, domain.CircuitHalfOpen},
+			{"ignored", false, domain.CircuitHalfOpen},
+			{"allow", true, domain.CircuitHalfOpen},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &circuitBreaker{state: domain.CircuitClosed}
+			for i, s := range tt.steps {
+				var got bool
+				switch s.action {
+				case "allow":
+					got = b.allow(cooldown)
+				case "success":
+					got = b.record(circuitSuccess, threshold, "")
+				case "failure":
+					got = b.record(circuitFailure, threshold, "503 Service Unavailable")
+				case "ignored":
+					got = b.record(circuitIgnored, threshold, "")
+				case "wait":
+					time.Sleep(cooldown + 5*time.Millisecond)
+				}
+				if got != s.want {
+					t.Errorf("paso %d (%s) = %v, want %v", i, s.action, got, s.want)
+				}
+				if b.state != s.state {
+					t.Errorf("paso %d (%s): estado %s, want %s", i, s.action, b.state, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerSnapshot(t *testing.T) {
+	b := &circuitBreaker{state: domain.CircuitClosed}
+	if c := b.snapshot("a.example", time.Minute); c.OpenedAt != nil || c.RetryAt != nil {
+		t.Errorf("un circuito cerrado no tiene fechas de apertura: %+v", c)
+	}
+
+	b.record(circuitFailure, 1, "timeout")
+	b.allow(time.Minute)
+	b.allow(time.Minute)
+	c := b.snapshot("a.example", time.Minute)
+	if c.State != domain.CircuitOpen || c.ConsecutiveFailures != 1 || c.Rejected != 2 || c.LastError != "timeout" {
+		t.Errorf("snapshot = %+v", c)
+	}
+	if c.OpenedAt == nil || c.RetryAt == nil || c.RetryAt.Sub(*c.OpenedAt) != time.Minute {
+		t.Errorf("fechas = %v, %v", c.OpenedAt, c.RetryAt)
+	}
+}
+
+func TestHostTransportCircuit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	host := mustHost(t, server.URL)
+
+	transport := NewHostTransport(HostPolicy{CircuitFailureThreshold: 2, CircuitCooldown: time.Hour})
+	client := &http.Client{Transport: transport}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	// Con el circuito abierto la petición falla sin llegar al host
+	if _, err := client.Get(server.URL); !errors.Is(err, domain.ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+
+	states := transport.CircuitStates()
+	if len(states) != 1 || states[0].Host != host || states[0].State != domain.CircuitOpen || states[0].Rejected != 1 {
+		t.Errorf("CircuitStates = %+v", states)
+	}
+
+	if !transport.ResetCircuit(host) {
+		t.Fatal("ResetCircuit should find the host")
+	}
+	if transport.ResetCircuit("desconocido.example") {
+		t.Error("ResetCircuit of an unknown host should return false")
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get tras reset: %v", err)
+	}
+	resp.Body.Close()
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("calls = %d, want 3", n)
+	}
+}
+
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q): %v", rawURL, err)
+	}
+	return u.Host
+}
@@ -62,6 +62,25 @@ func (s *CronScheduler) ScheduleFetchNews(jobFunc func()) error {
 	return nil
 }
 
+// ScheduleJob programa una tarea periódica auxiliar (renovaciones, limpiezas...) con su propia expresión
+func (s *CronScheduler) ScheduleJob(name, schedule string, jobFunc func()) error {
+	if !s.enabled {
+		return nil
+	}
+
+	_, err := s.cron.AddFunc(schedule, func() {
+		start := time.Now()
+		jobFunc()
+		s.logger.Info("Tarea programada completada", "tarea", name, "duracion", time.Since(start).String())
+	})
+	if err != nil {
+		return fmt.Errorf("error programando tarea %s: %w", name, err)
+	}
+
+	s.logger.Info("Tarea programada correctamente", "tarea", name, "cron_schedule", schedule)
+	return nil
+}
+
 // Start inicia el planificador de tareas
 func (s *CronScheduler) Start() error {
 	if !s.enabled {
@@ -0,0 +1,183 @@
+package infrastructure
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/vector"
+)
+
+// iconGrid es el lado de la cuadrícula en la que se definen los iconos de categoría
+const iconGrid = 24
+
+// categoryStyle es el color y el icono con que se generan las imágenes de una categoría
+type categoryStyle struct {
+	color color.RGBA
+	icon  func(p iconPen)
+}
+
+// categoryStyles relaciona cada categoría con su estilo; el resto usa defaultCategoryStyle
+var categoryStyles = map[string]categoryStyle{
+	"technology":    {color.RGBA{0x25, 0x63, 0xeb, 0xff}, iconChip},
+	"health":        {color.RGBA{0x05, 0x96, 0x69, 0xff}, iconCross},
+	"sports":        {color.RGBA{0xea, 0x58, 0x0c, 0xff}, iconBall},
+	"culture":       {color.RGBA{0x7c, 0x3a, 0xed, 0xff}, iconNotes},
+	"international": {color.RGBA{0x08, 0x91, 0xb2, 0xff}, iconGlobe},
+	"entertainment": {color.RGBA{0xdb, 0x27, 0x77, 0xff}, iconPlay},
+	"economy":       {color.RGBA{0xca, 0x8a, 0x04, 0xff}, iconChart},
+	"breaking":      {color.RGBA{0xdc, 0x26, 0x26, 0xff}, iconBolt},
+}
+
+var defaultCategoryStyle = categoryStyle{color.RGBA{0x47, 0x55, 0x69, 0xff}, iconNewspaper}
+
+// styleFor devuelve el estilo de la categoría
+func styleFor(categoryCode string) categoryStyle {
+	if style, ok := categoryStyles[categoryCode]; ok {
+		return style
+	}
+	return defaultCategoryStyle
+}
+
+// drawIcon rasteriza el icono a size píxeles en la posición at con el color col
+func drawIcon(dst *image.RGBA, icon func(p iconPen), at image.Point, size int, col color.Color) {
+	z := vector.NewRasterizer(size, size)
+	icon(iconPen{z: z, scale: float32(size) / iconGrid})
+	z.Draw(dst, image.Rectangle{Min: at, Max: at.Add(image.Pt(size, size))}, image.NewUniform(col), image.Point{})
+}
+
+// iconPen dibuja figuras en coordenadas de la cuadrícula del icono. Las figuras con el mismo sentido
+// se suman y las de sentido contrario restan (un círculo dentro de otro en sentido inverso es un anillo).
+type iconPen struct {
+	z     *vector.Rasterizer
+	scale float32
+}
+
+// poly traza un polígono cerrado a partir de pares x, y
+func (p iconPen) poly(points ...float32) {
+	p.z.MoveTo(points[0]*p.scale, points[1]*p.scale)
+	for i := 2; i+1 < len(points); i += 2 {
+		p.z.LineTo(points[i]*p.scale, points[i+1]*p.scale)
+	}
+	p.z.ClosePath()
+}
+
+// rect traza un rectángulo; reverse invierte el sentido para recortarlo de otra figura
+func (p iconPen) rect(x0, y0, x1, y1 float32, reverse bool) {
+	if reverse {
+		p.poly(x0, y0, x0, y1, x1, y1, x1, y0)
+		return
+	}
+	p.poly(x0, y0, x1, y0, x1, y1, x0, y1)
+}
+
+// ellipse traza una elipse con cuatro curvas cúbicas; reverse invierte el sentido
+func (p iconPen) ellipse(cx, cy, rx, ry float32, reverse bool) {
+	const k = 0.5523 // Distancia de los puntos de control para aproximar un cuarto de circunferencia
+	s := p.scale
+	dir := float32(1)
+	if reverse {
+		dir = -1
+	}
+	p.z.MoveTo((cx+rx)*s, cy*s)
+	p.z.CubeTo((cx+rx)*s, (cy+dir*k*ry)*s, (cx+k*rx)*s, (cy+dir*ry)*s, cx*s, (cy+dir*ry)*s)
+	p.z.CubeTo((cx-k*rx)*s, (cy+dir*ry)*s, (cx-rx)*s, (cy+dir*k*ry)*s, (cx-rx)*s, cy*s)
+	p.z.CubeTo((cx-rx)*s, (cy-dir*k*ry)*s, (cx-k*rx)*s, (cy-dir*ry)*s, cx*s, (cy-dir*ry)*s)
+	p.z.CubeTo((cx+k*rx)*s, (cy-dir*ry)*s, (cx+rx)*s, (cy-dir*k*ry)*s, (cx+rx)*s, cy*s)
+	p.z.ClosePath()
+}
+
+// ring traza un anillo de radio exterior r y grosor width
+func (p iconPen) ring(cx, cy, r, width float32) {
+	p.ellipse(cx, cy, r, r, false)
+	p.ellipse(cx, cy, r-width, r-width, true)
+}
+
+// iconChip es un microchip con patillas
+func iconChip(p iconPen) {
+	p.rect(6, 6, 18, 18, false)
+	p.rect(8, 8, 16, 16, true)
+	p.rect(10, 10, 14, 14, false)
+	for _, at := range []float32{8, 11.25, 14.5} {
+		p.rect(at, 2.5, at+1.5, 6, false)
+		p.rect(at, 18, at+1.5, 21.5, false)
+		p.rect(2.5, at, 6, at+1.5, false)
+		p.rect(18, at, 21.5, at+1.5, false)
+	}
+}
+
+// iconCross es la cruz sanitaria
+func iconCross(p iconPen) {
+	p.rect(9, 3.5, 15, 20.5, false)
+	p.rect(3.5, 9, 20.5, 15, false)
+}
+
+// iconBall es un balón: anillo con un pentágono central
+func iconBall(p iconPen) {
+	p.ring(12, 12, 9.5, 1.75)
+	p.poly(12, 7.5, 16.3, 10.6, 14.6, 15.6, 9.4, 15.6, 7.7, 10.6)
+}
+
+// iconNotes son dos corcheas unidas
+func iconNotes(p iconPen) {
+	p.ellipse(7, 17.5, 3, 2.5, false)
+	p.ellipse(16.5, 15.5, 3, 2.5, false)
+	p.rect(8.5, 5.5, 10, 17.5, false)
+	p.rect(18, 3.5, 19.5, 15.5, false)
+	p.poly(8.5, 5.5, 19.5, 3, 19.5, 6, 8.5, 8.5)
+}
+
+// iconGlobe es un globo terráqueo con ecuador y meridianos
+func iconGlobe(p iconPen) {
+	p.ring(12, 12, 9.5, 1.5)
+	p.ellipse(12, 12, 4.5, 9.5, false)
+	p.ellipse(12, 12, 3, 8, true)
+	p.rect(3, 11.25, 21, 12.75, false)
+}
+
+// iconPlay es el botón de reproducción
+func iconPlay(p iconPen) {
+	p.ring(12, 12, 9.5, 1.5)
+	p.poly(10, 8, 16.5, 12, 10, 16)
+}
+
+// iconChart es un gráfico de barras ascendente
+func iconChart(p iconPen) {
+	p.rect(4, 14, 7.5, 19.5, false)
+	p.rect(10.25, 10, 13.75, 19.5, false)
+	p.rect(16.5, 5, 20, 19.5, false)
+	p.rect(3, 19.5, 21, 21, false)
+}
+
+// iconBolt es un rayo (última hora)
+func iconBolt(p iconPen) {
+	p.poly(13, 2, 16, 2, 13.5, 10, 19, 10, 9.5, 22, 11, 14, 5, 14)
+}
+
+// iconNewspaper es un periódico con líneas de texto
+func iconNewspaper(p iconPen) {
+	p.rect(3, 4, 21, 20, false)
+	p.rect(4.5, 5.5, 19.5, 18.5, true)
+	p.rect(6.5, 8, 17.5, 9.5, false)
+	p.rect(6.5, 11.25, 17.5, 12.75, false)
+	p.rect(6.5, 14.5, 13.5, 16, false)
+}
+
+// fillGradient rellena la imagen con un degradado vertical de top a bottom
+func fillGradient(dst *image.RGBA, top, bottom color.RGBA) {
+	height := dst.Rect.Dy()
+	for y := 0; y < height; y++ {
+		t := float64(y) / float64(max(height-1, 1))
+		mix := func(a, b uint8) uint8 {
+			return uint8(float64(a)*(1-t) + float64(b)*t + 0.5)
+		}
+		row := image.Rect(dst.Rect.Min.X, dst.Rect.Min.Y+y, dst.Rect.Max.X, dst.Rect.Min.Y+y+1)
+		draw.Draw(dst, row, image.NewUniform(color.RGBA{mix(top.R, bottom.R), mix(top.G, bottom.G), mix(top.B, bottom.B), 0xff}), image.Point{}, draw.Src)
+	}
+}
+
+// darken oscurece el color por factor (0-1)
+func darken(c color.RGBA, factor float64) color.RGBA {
+	scale := func(v uint8) uint8 { return uint8(float64(v)*(1-factor) + 0.5) }
+	return color.RGBA{scale(c.R), scale(c.G), scale(c.B), c.A}
+}
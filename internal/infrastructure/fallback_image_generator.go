@@ -0,0 +1,291 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// GeneratedImagePublicPath es la ruta pública desde la que se sirven las imágenes generadas
+const GeneratedImagePublicPath = "/images/generated/"
+
+// Proporciones del diseño respecto al ancho de la imagen (pensadas para 800x450)
+const (
+	generatedLayoutVersion = "v1" // Cambiarla invalida las imágenes ya generadas
+	generatedMaxTitleLines = 4
+	generatedPadding       = 0.06
+	generatedBadgeIcon     = 0.06
+	generatedBigIcon       = 0.4
+	generatedTitleSize     = 0.05
+	generatedSourceSize    = 0.032
+)
+
+// fallbackImageGenerator implementa domain.FallbackImageGenerator: dibuja en Go puro una imagen de marca
+// con el color y el icono de la categoría, el nombre de la fuente y, opcionalmente, el titular
+type fallbackImageGenerator struct {
+	dir          string
+	format       string
+	width        int
+	height       int
+	includeTitle bool
+	output       imageOutput
+	maxAge       time.Duration
+	newsRepo     domain.NewsItemRepository
+	titleFont    *opentype.Font
+	sourceFont   *opentype.Font
+	fontErr      error
+}
+
+// NewFallbackImageGenerator crea el generador de imágenes de width x height en dir. format es "webp"
+// (por defecto) o "jpeg"; includeTitle añade el titular de la noticia (una imagen por noticia en lugar de
+// una por fuente y categoría) y maxAge es la antigüedad a partir de la que el GC borra las no usadas;
+// las que aún usa alguna noticia de newsRepo no se borran nunca.
+func NewFallbackImageGenerator(dir, format string, width, height int, includeTitle bool, output ImageOutputPolicy, maxAge time.Duration, newsRepo domain.NewsItemRepository) domain.FallbackImageGenerator {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "jpeg" {
+		format = "webp"
+	}
+	g := &fallbackImageGenerator{
+		dir:          dir,
+		format:       format,
+		width:        width,
+		height:       height,
+		includeTitle: includeTitle,
+		output:       output.normalize(),
+		maxAge:       maxAge,
+		newsRepo:     newsRepo,
+	}
+	// Fuentes Go embebidas en el binario: no dependen de las instaladas en el sistema
+	g.titleFont, g.fontErr = opentype.Parse(gobold.TTF)
+	if g.fontErr == nil {
+		g.sourceFont, g.fontErr = opentype.Parse(gomedium.TTF)
+	}
+	return g
+}
+
+// Generate devuelve la URL local de la imagen, dibujándola solo si no está ya en disco
+func (g *fallbackImageGenerator) Generate(ctx context.Context, sourceName, categoryCode, title string) (string, error) {
+	if g.fontErr != nil {
+		return "", fmt.Errorf("error cargando las fuentes tipográficas: %w", g.fontErr)
+	}
+	if !g.includeTitle {
+		title = ""
+	}
+	ext := ".webp"
+	if g.format == "jpeg" {
+		ext = ".jpg"
+	}
+	key := sha256.Sum256([]byte(strings.Join([]string{generatedLayoutVersion, fmt.Sprintf("%dx%d", g.width, g.height), categoryCode, sourceName, title}, "\x00")))
+	filename := hex.EncodeToString(key[:12]) + ext
+	localURL := GeneratedImagePublicPath + filename
+	filePath := filepath.Join(g.dir, filename)
+
+	if _, err := os.Stat(filePath); err == nil {
+		// La fecha de modificación es el último uso: la que mira el GC
+		now := time.Now()
+		if err := os.Chtimes(filePath, now, now); err != nil {
+			utils.AppWarn("GENERATED_IMAGE", "No se pudo marcar el uso de la imagen generada", map[string]interface{}{
+				"file":  localURL,
+				"error": err.Error(),
+			})
+		}
+		return localURL, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	img, err := g.render(sourceName, categoryCode, title)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(g.dir, 0755); err != nil {
+		return "", fmt.Errorf("error creando directorio de imágenes generadas: %w", err)
+	}
+	// Temporal y renombrado: dos extracciones simultáneas no sirven nunca un archivo a medias
+	tmpPath := filepath.Join(g.dir, randomName()+".tmp")
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("error creando imagen generada: %w", err)
+	}
+	if err := g.output.encode(f, img, g.format == "jpeg"); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error codificando imagen generada: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("error guardando imagen generada: %w", err)
+	}
+
+	utils.AppInfo("GENERATED_IMAGE", "Imagen de fallback generada", map[string]interface{}{
+		"source":   sourceName,
+		"category": categoryCode,
+		"file":     localURL,
+	})
+	return localURL, nil
+}
+
+// render dibuja la imagen: degradado del color de la categoría, su icono grande en marca de agua
+// y pequeño arriba, el titular ajustado al ancho y el nombre de la fuente abajo
+func (g *fallbackImageGenerator) render(sourceName, categoryCode, title string) (*image.RGBA, error) {
+	w, h := g.width, g.height
+	unit := float64(w)
+	pad := int(unit * generatedPadding)
+	style := styleFor(categoryCode)
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fillGradient(img, style.color, darken(style.color, 0.35))
+
+	bigIcon := int(unit * generatedBigIcon)
+	drawIcon(img, style.icon, image.Pt(w-bigIcon-pad/2, (h-bigIcon)/2+pad/2), bigIcon, color.NRGBA{0xff, 0xff, 0xff, 0x30})
+	badge := int(unit * generatedBadgeIcon)
+	drawIcon(img, style.icon, image.Pt(pad, pad), badge, color.White)
+
+	sourceFace, err := opentype.NewFace(g.sourceFont, &opentype.FaceOptions{Size: unit * generatedSourceSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer sourceFace.Close()
+	titleFace, err := opentype.NewFace(g.titleFont, &opentype.FaceOptions{Size: unit * generatedTitleSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+
+	// Sin titular, el nombre de la fuente ocupa su lugar
+	headline := title
+	if headline == "" {
+		headline = sourceName
+	}
+	maxWidth := fixed.I(w - 2*pad)
+	lines := wrapText(titleFace, headline, maxWidth, generatedMaxTitleLines)
+	lineHeight := titleFace.Metrics().Height.Mul(fixed.Int26_6(80)) // 1.25 veces la altura de la fuente
+	top := fixed.I(pad + badge + pad/2)
+	text := &font.Drawer{Dst: img, Src: image.NewUniform(color.White), Face: titleFace}
+	for i, line := range lines {
+		text.Dot = fixed.Point26_6{X: fixed.I(pad), Y: top + titleFace.Metrics().Ascent + lineHeight.Mul(fixed.I(i))}
+		text.DrawString(line)
+	}
+
+	if title != "" {
+		// Barra de acento y nombre de la fuente al pie
+		bar := image.Rect(pad, h-pad-int(unit*0.05), pad+int(unit*0.06), h-pad-int(unit*0.045))
+		draw.Draw(img, bar, image.NewUniform(color.White), image.Point{}, draw.Src)
+		source := &font.Drawer{Dst: img, Src: image.NewUniform(color.NRGBA{0xff, 0xff, 0xff, 0xe0}), Face: sourceFace}
+		source.Dot = fixed.P(pad, h-pad)
+		source.DrawString(truncateText(sourceFace, sourceName, maxWidth))
+	}
+	return img, nil
+}
+
+// wrapText reparte el texto en como mucho maxLines líneas de maxWidth; la última se corta con puntos suspensivos
+func wrapText(face font.Face, text string, maxWidth fixed.Int26_6, maxLines int) []string {
+	var lines []string
+	line := ""
+	words := strings.Fields(text)
+	for i, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line == "" || font.MeasureString(face, candidate) <= maxWidth {
+			line = candidate
+			continue
+		}
+		if len(lines) == maxLines-1 {
+			// Última línea disponible: el resto del texto se trunca
+			return append(lines, truncateText(face, strings.Join(append([]string{line}, words[i:]...), " "), maxWidth))
+		}
+		lines = append(lines, truncateText(face, line, maxWidth))
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, truncateText(face, line, maxWidth))
+	}
+	return lines
+}
+
+// truncateText acorta el texto con puntos suspensivos hasta que quepa en maxWidth
+func truncateText(face font.Face, text string, maxWidth fixed.Int26_6) string {
+	if font.MeasureString(face, text) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimRight(string(runes), " ,.;:") + "…"
+		if font.MeasureString(face, candidate) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// CollectGarbage borra las imágenes generadas que no se han usado en maxAge y que ninguna noticia guardada
+// sigue mostrando (la fecha de uso solo se renueva al volver a generarla)
+func (g *fallbackImageGenerator) CollectGarbage(ctx context.Context) (int, error) {
+	if g.maxAge <= 0 {
+		return 0, nil
+	}
+	referenced := make(map[string]bool)
+	if g.newsRepo != nil {
+		images, err := g.newsRepo.ImagesWithPrefix(ctx, GeneratedImagePublicPath)
+		if err != nil {
+			return 0, fmt.Errorf("error consultando las imágenes generadas en uso: %w", err)
+		}
+		for _, image := range images {
+			referenced[filepath.Base(image)] = true
+		}
+	}
+	entries, err := os.ReadDir(g.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error listando imágenes generadas: %w", err)
+	}
+	removed := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return removed, ctx.Err()
+		}
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || referenced[entry.Name()] || time.Since(info.ModTime()) <= g.maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(g.dir, entry.Name())); err != nil {
+			utils.AppWarn("GENERATED_IMAGE", "No se pudo borrar la imagen generada", map[string]interface{}{
+				"file":  entry.Name(),
+				"error": err.Error(),
+			})
+			continue
+		}
+		removed++
+	}
+	utils.AppInfo("GENERATED_IMAGE", "Limpieza de imágenes generadas completada", map[string]interface{}{
+		"removed": removed,
+	})
+	return removed, nil
+}
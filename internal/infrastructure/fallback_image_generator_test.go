@@ -0,0 +1,238 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"dailynews/internal/domain"
+)
+
+// referencedNewsRepo devuelve siempre las mismas imágenes en uso; el resto de operaciones no se usan
+type referencedNewsRepo struct {
+	domain.NewsItemRepository
+	images []string
+}
+
+func (r *referencedNewsRepo) ImagesWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	var result []string
+	for _, image := range r.images {
+		if strings.HasPrefix(image, prefix) {
+			result = append(result, image)
+		}
+	}
+	return result, nil
+}
+
+// testFace crea la cara tipográfica del titular con la fuente embebida
+func testFace(t *testing.T) font.Face {
+	t.Helper()
+	parsed, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		t.Fatalf("opentype.Parse: %v", err)
+	}
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: 20, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		t.Fatalf("opentype.NewFace: %v", err)
+	}
+	t.Cleanup(func() { face.Close() })
+	return face
+}
+
+func TestTruncateText(t *testing.T) {
+	face := testFace(t)
+	width := font.MeasureString(face, "Presupuestos")
+
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth fixed.Int26_6
+		want     string
+	}{
+		{"cabe entero", "Presupuestos", width, "Presupuestos"},
+		{"vacío", "", width, ""},
+		{"se corta con puntos suspensivos", "Presupuestos generales", width, ""},
+		{"sin puntuación antes de los puntos", "Gobierno, presupuestos", font.MeasureString(face, "Gobierno…"), "Gobierno…"},
+		{"no cabe nada", "Presupuestos", fixed.I(1), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateText(face, tt.text, tt.maxWidth)
+			if font.MeasureString(face, got) > tt.maxWidth {
+				t.Errorf("truncateText = %q no cabe en el ancho", got)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("truncateText = %q, want %q", got, tt.want)
+			}
+			if tt.want == "" && tt.text != "" && tt.maxWidth > fixed.I(1) {
+				if !strings.HasSuffix(got, "…") || !strings.HasPrefix(tt.text, strings.TrimSuffix(got, "…")) {
+					t.Errorf("truncateText = %q no es un prefijo de %q con puntos suspensivos", got, tt.text)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	face := testFace(t)
+	width := font.MeasureString(face, "El Gobierno aprueba")
+
+	tests := []struct {
+		name     string
+		text     string
+		maxLines int
+		want     []string
+	}{
+		{"una línea", "El Gobierno", 4, []string{"El Gobierno"}},
+		{"vacío", "   ", 4, nil},
+		{"reparte por palabras", "El Gobierno aprueba los presupuestos", 4, []string{"El Gobierno aprueba", "los presupuestos"}},
+		{"espacios repetidos", "El  Gobierno\taprueba", 4, []string{"El Gobierno aprueba"}},
+		{"la última línea se trunca", "El Gobierno aprueba los presupuestos generales del Estado para el año que viene", 2, nil},
+		{"palabra más larga que el ancho", "Anticonstitucionalmente-inconstitucionalísimo", 4, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(face, tt.text, width, tt.maxLines)
+			if len(got) > tt.maxLines {
+				t.Fatalf("wrapText = %q: %d líneas, máximo %d", got, len(got), tt.maxLines)
+			}
+			for _, line := range got {
+				if font.MeasureString(face, line) > width {
+					t.Errorf("la línea %q no cabe en el ancho", line)
+				}
+			}
+			if tt.want != nil && strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapText = %q, want %q", got, tt.want)
+			}
+			if tt.want == nil && strings.TrimSpace(tt.text) != "" {
+				if len(got) == 0 || !strings.HasSuffix(got[len(got)-1], "…") {
+					t.Errorf("wrapText = %q, want la última línea con puntos suspensivos", got)
+				}
+			}
+		})
+	}
+}
+
+func TestFallbackImageGeneratorCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	generate := func(g domain.FallbackImageGenerator, source, category, title string) string {
+		t.Helper()
+		url, err := g.Generate(ctx, source, category, title)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !strings.HasPrefix(url, GeneratedImagePublicPath) {
+			t.Fatalf("Generate = %q, want prefijo %q", url, GeneratedImagePublicPath)
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.Base(url))); err != nil {
+			t.Fatalf("la imagen %q no está en disco: %v", url, err)
+		}
+		return url
+	}
+
+	withTitle := NewFallbackImageGenerator(dir, "jpeg", 160, 90, true, ImageOutputPolicy{}, time.Hour, nil)
+	withoutTitle := NewFallbackImageGenerator(dir, "", 160, 90, false, ImageOutputPolicy{}, time.Hour, nil)
+	bigger := NewFallbackImageGenerator(dir, "jpeg", 320, 180, true, ImageOutputPolicy{}, time.Hour, nil)
+	base := generate(withTitle, "Diario", "sports", "Titular")
+
+	tests := []struct {
+		name      string
+		generator domain.FallbackImageGenerator
+		source    string
+		category  string
+		title     string
+		same      bool
+	}{
+		{"mismos datos", withTitle, "Diario", "sports", "Titular", true},
+		{"otro titular", withTitle, "Diario", "sports", "Otro titular", false},
+		{"otra fuente", withTitle, "Gaceta", "sports", "Titular", false},
+		{"otra categoría", withTitle, "Diario", "tech", "Titular", false},
+		{"otro tamaño", bigger, "Diario", "sports", "Titular", false},
+		{"sin titular y en WebP", withoutTitle, "Diario", "sports", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generate(tt.generator, tt.source, tt.category, tt.title); (got == base) != tt.same {
+				t.Errorf("Generate = %q, base %q, want misma imagen %v", got, base, tt.same)
+			}
+		})
+	}
+
+	// Sin titular, noticias distintas de la misma fuente y categoría comparten la imagen
+	first := generate(withoutTitle, "Diario", "sports", "Titular")
+	if again := generate(withoutTitle, "Diario", "sports", "Otro titular"); again != first {
+		t.Errorf("sin titular: Generate = %q y después %q", first, again)
+	}
+	if !strings.HasSuffix(first, ".webp") || !strings.HasSuffix(base, ".jpg") {
+		t.Errorf("extensiones: %q y %q", first, base)
+	}
+}
+
+func TestFallbackImageGeneratorCollectGarbage(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	old := time.Now().Add(-2 * time.Hour)
+	files := map[string]time.Time{
+		"antigua.webp":  old,
+		"en-uso.webp":   old,
+		"reciente.webp": time.Now(),
+	}
+	for name, modTime := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	newsRepo := &referencedNewsRepo{images: []string{GeneratedImagePublicPath + "en-uso.webp", "/images/fallback/antigua.webp"}}
+
+	tests := []struct {
+		name    string
+		maxAge  time.Duration
+		removed int
+		kept    []string
+	}{
+		{"desactivado", 0, 0, []string{"antigua.webp", "en-uso.webp", "reciente.webp"}},
+		{"borra solo las antiguas sin usar", time.Hour, 1, []string{"en-uso.webp", "reciente.webp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewFallbackImageGenerator(dir, "webp", 160, 90, true, ImageOutputPolicy{}, tt.maxAge, newsRepo)
+			removed, err := g.CollectGarbage(ctx)
+			if err != nil {
+				t.Fatalf("CollectGarbage: %v", err)
+			}
+			if removed != tt.removed {
+				t.Errorf("CollectGarbage = %d, want %d", removed, tt.removed)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var kept []string
+			for _, entry := range entries {
+				kept = append(kept, entry.Name())
+			}
+			if strings.Join(kept, ",") != strings.Join(tt.kept, ",") {
+				t.Errorf("archivos = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+
+	// Un directorio que aún no existe no es un error
+	g := NewFallbackImageGenerator(filepath.Join(dir, "no-existe"), "webp", 160, 90, true, ImageOutputPolicy{}, time.Hour, nil)
+	if removed, err := g.CollectGarbage(ctx); err != nil || removed != 0 {
+		t.Errorf("CollectGarbage sin directorio = %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,279 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// maxDiscoveryCandidates limita los feeds y sitemaps que se devuelven por web analizada
+const maxDiscoveryCandidates = 10
+
+// commonFeedPaths son las rutas habituales de feeds que se prueban cuando la página no los anuncia
+var commonFeedPaths = []string{"/feed", "/feed/", "/rss", "/rss.xml", "/feed.xml", "/atom.xml", "/index.xml"}
+
+// commonSitemapPaths son las rutas habituales de sitemaps (primero los de noticias)
+var commonSitemapPaths = []string{"/sitemap_news.xml", "/news-sitemap.xml", "/sitemap.xml"}
+
+// feedDiscoverer implementa la interfaz FeedDiscoverer del dominio
+type feedDiscoverer struct {
+	httpClient *http.Client
+	parser     *gofeed.Parser
+}
+
+// NewFeedDiscoverer crea un buscador de feeds a partir de la URL de una web
+func NewFeedDiscoverer(transport http.RoundTripper) domain.FeedDiscoverer {
+	return &feedDiscoverer{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   15 * time.Second,
+		},
+		parser: gofeed.NewParser(),
+	}
+}
+
+// Discover busca feeds en los <link rel="alternate"> de la página, en rutas habituales
+// (/feed, /rss, /rss.xml...) y en los sitemaps declarados en robots.txt o en rutas habituales
+func (d *feedDiscoverer) Discover(ctx context.Context, pageURL string) ([]domain.FeedCandidate, error) {
+	pageURL = strings.TrimSpace(pageURL)
+	if !strings.Contains(pageURL, "://") {
+		pageURL = "https://" + pageURL
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("URL inválida: %s", pageURL)
+	}
+
+	utils.AppInfo("FEED_DISCOVERY", "Iniciando búsqueda de feeds", map[string]interface{}{
+		"url": pageURL,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+
+	body, err := d.get(ctx, pageURL, "text/html,application/xhtml+xml,application/xml")
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo obtener la página: %w", err)
+	}
+
+	// La URL indicada ya es un feed
+	if feed, err := d.parser.Parse(bytes.NewReader(body)); err == nil {
+		return []domain.FeedCandidate{{
+			URL:      pageURL,
+			Type:     domain.SourceTypeRSS,
+			Title:    strings.TrimSpace(feed.Title),
+			Language: strings.TrimSpace(feed.Language),
+			Origin:   "link",
+		}}, nil
+	}
+
+	var pageLang string
+	var linked []domain.FeedCandidate
+	if doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body)); err == nil {
+		pageLang = strings.TrimSpace(doc.Find("html").AttrOr("lang", ""))
+		if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+			if b, err := base.Parse(strings.TrimSpace(href)); err == nil {
+				base = b
+			}
+		}
+		doc.Find(`link[rel~="alternate"]`).Each(func(_ int, s *goquery.Selection) {
+			linkType := strings.ToLower(s.AttrOr("type", ""))
+			if !strings.Contains(linkType, "rss") && !strings.Contains(linkType, "atom") {
+				return
+			}
+			if href := resolveURL(base, s.AttrOr("href", "")); href != "" {
+				linked = append(linked, domain.FeedCandidate{
+					URL:    href,
+					Type:   domain.SourceTypeRSS,
+					Title:  strings.TrimSpace(s.AttrOr("title", "")),
+					Origin: "link",
+				})
+			}
+		})
+	}
+
+	root := &url.URL{Scheme: base.Scheme, Host: base.Host}
+	var guessed []domain.FeedCandidate
+	for _, p := range commonFeedPaths {
+		guessed = append(guessed, domain.FeedCandidate{
+			URL:    root.ResolveReference(&url.URL{Path: p}).String(),
+			Type:   domain.SourceTypeRSS,
+			Origin: "path",
+		})
+	}
+
+	var sitemaps []domain.FeedCandidate
+	for _, loc := range d.robotsSitemaps(ctx, root) {
+		sitemaps = append(sitemaps, domain.FeedCandidate{URL: loc, Type: domain.SourceTypeSitemap, Origin: "sitemap"})
+	}
+	for _, p := range commonSitemapPaths {
+		sitemaps = append(sitemaps, domain.FeedCandidate{
+			URL:    root.ResolveReference(&url.URL{Path: p}).String(),
+			Type:   domain.SourceTypeSitemap,
+			Origin: "sitemap",
+		})
+	}
+
+	candidates := dedupeCandidates(append(append(linked, guessed...), sitemaps...))
+	verified := d.verify(ctx, candidates, pageLang)
+
+	// Varias rutas pueden servir el mismo feed: quedarse con la primera
+	seenTitles := make(map[string]struct{})
+	var result []domain.FeedCandidate
+	for _, c := range verified {
+		key := c.Type + "|" + c.Title
+		if c.Type == domain.SourceTypeRSS && c.Origin == "path" && c.Title != "" {
+			if _, exists := seenTitles[key]; exists {
+				continue
+			}
+		}
+		seenTitles[key] = struct{}{}
+		result = append(result, c)
+		if len(result) >= maxDiscoveryCandidates {
+			break
+		}
+	}
+
+	utils.AppInfo("FEED_DISCOVERY", "Búsqueda de feeds completada", map[string]interface{}{
+		"url":        pageURL,
+		"candidates": len(result),
+	})
+	return result, nil
+}
+
+// verify comprueba en paralelo que cada candidato responde con un feed o sitemap, conservando el orden
+func (d *feedDiscoverer) verify(ctx context.Context, candidates []domain.FeedCandidate, pageLang string) []domain.FeedCandidate {
+	ok := make([]bool, len(candidates))
+	var wg sync.WaitGroup
+	for i := range candidates {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := &candidates[i]
+			if c.Type == domain.SourceTypeSitemap {
+				ok[i] = d.isSitemap(ctx, c.URL)
+				c.Language = pageLang
+				return
+			}
+
+			// gofeed.Parser guarda estado durante el parseo: un parser por goroutine
+			feedCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+			defer cancel()
+			feed, err := gofeed.NewParser().ParseURLWithContext(c.URL, feedCtx)
+			if err != nil {
+				return
+			}
+			ok[i] = true
+			if c.Title == "" {
+				c.Title = strings.TrimSpace(feed.Title)
+			}
+			c.Language = strings.TrimSpace(feed.Language)
+			if c.Language == "" {
+				c.Language = pageLang
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var verified []domain.FeedCandidate
+	for i, c := range candidates {
+		if ok[i] {
+			verified = append(verified, c)
+		}
+	}
+	return verified
+}
+
+// robotsSitemaps devuelve los sitemaps declarados en robots.txt
+func (d *feedDiscoverer) robotsSitemaps(ctx context.Context, root *url.URL) []string {
+	body, err := d.get(ctx, root.ResolveReference(&url.URL{Path: "/robots.txt"}).String(), "text/plain")
+	if err != nil {
+		return nil
+	}
+
+	var locs []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) > 8 && strings.EqualFold(line[:8], "sitemap:") {
+			if loc := resolveURL(root, line[8:]); loc != "" {
+				locs = append(locs, loc)
+			}
+		}
+	}
+	return locs
+}
+
+// isSitemap comprueba si la URL sirve un sitemap (XML con <urlset>/<sitemapindex> o gzip)
+func (d *feedDiscoverer) isSitemap(ctx context.Context, sitemapURL string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", browserUserAgent)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	head := make([]byte, 1024)
+	n, _ := io.ReadFull(resp.Body, head)
+	head = head[:n]
+	if n >= 2 && head[0] == 0x1f && head[1] == 0x8b {
+		return true
+	}
+	return bytes.Contains(head, []byte("<urlset")) || bytes.Contains(head, []byte("<sitemapindex"))
+}
+
+// get descarga una URL y devuelve su contenido (máximo maxHTMLPageSize)
+func (d *feedDiscoverer) get(ctx context.Context, target, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando petición: %w", err)
+	}
+	req.Header.Set("User-Agent", browserUserAgent)
+	req.Header.Set("Accept", accept)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxHTMLPageSize))
+}
+
+// dedupeCandidates elimina candidatos repetidos por URL conservando el primero
+func dedupeCandidates(candidates []domain.FeedCandidate) []domain.FeedCandidate {
+	seen := make(map[string]struct{}, len(candidates))
+	var unique []domain.FeedCandidate
+	for _, c := range candidates {
+		key := strings.TrimSuffix(c.URL, "/")
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, c)
+	}
+	return unique
+}
@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"dailynews/internal/domain"
+)
+
+const discoveryPageFixture = `<!doctype html>
+<html lang="es"><head>
+<base href="/noticias/">
+<link rel="alternate" type="application/rss+xml" title="Últimas noticias" href="rss.xml">
+<link rel="alternate" type="application/atom+xml" href="/atom-roto.xml">
+<link rel="alternate" hreflang="en" href="/en/">
+<link rel="stylesheet" type="text/css" href="/estilos.css">
+</head><body><h1>Portada</h1></body></html>`
+
+func discoveryRSS(title, language string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<rss version="2.0"><channel><title>%s</title><language>%s</language>
+<item><title>Una noticia</title><link>https://example.com/1</link></item>
+</channel></rss>`, title, language)
+}
+
+func TestFeedDiscovererDiscover(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(discoveryPageFixture))
+	})
+	mux.HandleFunc("/noticias/rss.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(discoveryRSS("Canal principal", "es-ES")))
+	})
+	// /feed y /rss.xml sirven el mismo feed: solo se conserva el primero
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(discoveryRSS("Portada", "")))
+	})
+	mux.HandleFunc("/rss.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(discoveryRSS("Portada", "")))
+	})
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nDisallow: /privado\nSitemap: /sitemaps/news.xml\nsitemap:\n"))
+	})
+	mux.HandleFunc("/sitemaps/news.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"></urlset>`))
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body>No es un sitemap</body></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	got, err := NewFeedDiscoverer(nil).Discover(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	want := []domain.FeedCandidate{
+		{URL: server.URL + "/noticias/rss.xml", Type: domain.SourceTypeRSS, Title: "Últimas noticias", Language: "es-ES", Origin: "link"},
+		{URL: server.URL + "/feed", Type: domain.SourceTypeRSS, Title: "Portada", Language: "es", Origin: "path"},
+		{URL: server.URL + "/sitemaps/news.xml", Type: domain.SourceTypeSitemap, Language: "es", Origin: "sitemap"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func TestFeedDiscovererURLIsFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(discoveryRSS("Feed directo", "pt")))
+	}))
+	defer server.Close()
+
+	got, err := NewFeedDiscoverer(nil).Discover(context.Background(), server.URL+"/rss")
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	want := []domain.FeedCandidate{{URL: server.URL + "/rss", Type: domain.SourceTypeRSS, Title: "Feed directo", Language: "pt", Origin: "link"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestFeedDiscovererPageError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := NewFeedDiscoverer(nil).Discover(context.Background(), server.URL); err == nil {
+		t.Fatal("expected error when the page cannot be fetched")
+	}
+}
+
+func TestDedupeCandidates(t *testing.T) {
+	in := []domain.FeedCandidate{
+		{URL: "https://a.example/feed", Origin: "link"},
+		{URL: "https://a.example/feed/", Origin: "path"},
+		{URL: "https://a.example/rss", Origin: "path"},
+		{URL: "https://a.example/feed", Origin: "sitemap"},
+	}
+	got := dedupeCandidates(in)
+	want := []domain.FeedCandidate{in[0], in[2]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeCandidates = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,412 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// robotsTTL es el tiempo que se reutiliza el robots.txt descargado de un host
+const robotsTTL = time.Hour
+
+// ErrRobotsDisallowed indica que robots.txt no permite la URL solicitada
+var ErrRobotsDisallowed = errors.New("URL bloqueada por robots.txt")
+
+// HostPolicy define la cortesía por host de las peticiones salientes
+type HostPolicy struct {
+	RequestsPerSecond float64       // Peticiones por segundo por host (0 = sin límite)
+	MaxConcurrent     int           // Peticiones simultáneas por host (0 = sin límite)
+	RespectRobots     bool          // Consultar robots.txt antes de cada petición
+	MaxRetries        int           // Reintentos ante timeouts, 429 y 5xx
+	RetryBaseDelay    time.Duration // Espera del primer reintento (se duplica en cada uno)
+	RetryMaxDelay     time.Duration // Espera máxima entre reintentos (también limita Retry-After)
+
+	CircuitFailureThreshold int           // Fallos seguidos (timeouts, errores de red, 429, 5xx) que abren el circuito del host
+	CircuitCooldown         time.Duration // Tiempo que el circuito permanece abierto antes de la petición de prueba
+}
+
+// HostTransport es un http.RoundTripper que aplica límite de ritmo y concurrencia por host,
+// robots.txt opcional, reintentos con backoff exponencial y un circuit breaker por host. Lo comparten todos los fetchers
+// y el validador de imágenes para que la cortesía sea global por host.
+type HostTransport struct {
+	base   http.RoundTripper
+	policy HostPolicy
+	state  *hostState
+}
+
+// hostState es el estado compartido por host (también entre los transportes de los perfiles HTTP)
+type hostState struct {
+	mu       sync.Mutex
+	hosts    map[string]*hostLimiter
+	robots   map[string]*robotsRules
+	breakers map[string]*circuitBreaker
+}
+
+// hostLimiter controla el ritmo y la concurrencia de un host
+type hostLimiter struct {
+	mu       sync.Mutex
+	nextSlot time.Time
+	slots    chan struct{}
+}
+
+// robotsRules son las reglas de robots.txt aplicables a cualquier agente ("*")
+type robotsRules struct {
+	fetchedAt time.Time
+	allow     []string
+	disallow  []string
+}
+
+// NewHostTransport crea el transporte compartido con la política indicada
+func NewHostTransport(policy HostPolicy) *HostTransport {
+	if policy.RetryBaseDelay <= 0 {
+		policy.RetryBaseDelay = 500 * time.Millisecond
+	}
+	if policy.RetryMaxDelay <= 0 {
+		policy.RetryMaxDelay = 30 * time.Second
+	}
+	if policy.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+	if policy.CircuitFailureThreshold <= 0 {
+		policy.CircuitFailureThreshold = 5
+	}
+	if policy.CircuitCooldown <= 0 {
+		policy.CircuitCooldown = time.Minute
+	}
+	return &HostTransport{
+		base:   http.DefaultTransport.(*http.Transport).Clone(),
+		policy: policy,
+		state: &hostState{
+			hosts:    make(map[string]*hostLimiter),
+			robots:   make(map[string]*robotsRules),
+			breakers: make(map[string]*circuitBreaker),
+		},
+	}
+}
+
+// WithBase devuelve un transporte que comparte límites y robots.txt pero usa otro transporte
+// de red (el de un perfil HTTP con proxy o TLS propios)
+func (t *HostTransport) WithBase(base http.RoundTripper) *HostTransport {
+	return &HostTransport{base: base, policy: t.policy, state: t.state}
+}
+
+// RoundTrip aplica robots.txt y el circuit breaker, espera turno en el host y reintenta los fallos transitorios
+func (t *HostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	stats := domain.FetchStatsFromContext(ctx)
+	host := strings.ToLower(req.URL.Host)
+
+	if t.policy.RespectRobots && !t.robotsAllowed(ctx, req) {
+		stats.AddRobotsBlocked()
+		return nil, fmt.Errorf("%w: %s", ErrRobotsDisallowed, req.URL.String())
+	}
+
+	// Con el circuito abierto se falla al instante en lugar de esperar al timeout del host caído
+	breaker := t.breaker(host)
+	if !breaker.allow(t.policy.CircuitCooldown) {
+		stats.AddFailure()
+		return nil, fmt.Errorf("%w: %s", domain.ErrCircuitOpen, host)
+	}
+
+	limiter := t.limiter(host)
+	// Solo se reintentan peticiones sin cuerpo (GET/HEAD de feeds e imágenes)
+	retryable := req.Body == nil || req.Body == http.NoBody
+
+	for attempt := 0; ; attempt++ {
+		if err := limiter.acquire(ctx, t.policy.RequestsPerSecond); err != nil {
+			breaker.record(circuitIgnored, t.policy.CircuitFailureThreshold, "")
+			return nil, err
+		}
+		stats.AddRequest()
+
+		resp, err := t.base.RoundTrip(req)
+		transient, retryAfter := isTransient(resp, err)
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			stats.AddRateLimited()
+		}
+
+		// Si el contexto ya expiró (timeout del cliente) no tiene sentido reintentar
+		if !transient || !retryable || attempt >= t.policy.MaxRetries || ctx.Err() != nil {
+			t.recordCircuit(breaker, host, resp, err)
+			if err != nil || (resp != nil && resp.StatusCode >= 400) {
+				stats.AddFailure()
+			}
+			if err != nil {
+				limiter.release()
+				return nil, err
+			}
+			// El hueco de concurrencia se libera al cerrar el cuerpo de la respuesta
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: limiter.release}
+			return resp, nil
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+		limiter.release()
+
+		delay := t.backoff(attempt, retryAfter)
+		stats.AddRetry()
+		utils.AppWarn("HOST_TRANSPORT", "Reintentando petición", map[string]interface{}{
+			"host":    host,
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			breaker.record(circuitIgnored, t.policy.CircuitFailureThreshold, "")
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff calcula la espera antes del siguiente intento: Retry-After si existe, si no exponencial con jitter
+func (t *HostTransport) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = t.policy.RetryBaseDelay << uint(attempt)
+		delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	}
+	if delay > t.policy.RetryMaxDelay {
+		delay = t.policy.RetryMaxDelay
+	}
+	return delay
+}
+
+// isTransient indica si el resultado merece un reintento (timeout, 429 o 5xx) y el Retry-After recibido
+func isTransient(resp *http.Response, err error) (bool, time.Duration) {
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true, 0
+		}
+		return false, 0
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return true, parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	return false, 0
+}
+
+// parseRetryAfter interpreta Retry-After en segundos o como fecha HTTP
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// limiter devuelve el limitador del host, creándolo si no existe
+func (t *HostTransport) limiter(host string) *hostLimiter {
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	l, ok := t.state.hosts[host]
+	if !ok {
+		l = &hostLimiter{}
+		if t.policy.MaxConcurrent > 0 {
+			l.slots = make(chan struct{}, t.policy.MaxConcurrent)
+		}
+		t.state.hosts[host] = l
+	}
+	return l
+}
+
+// acquire espera un hueco de concurrencia y el siguiente turno según el ritmo por host
+func (l *hostLimiter) acquire(ctx context.Context, requestsPerSecond float64) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	interval := time.Duration(float64(time.Second) / requestsPerSecond)
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	l.nextSlot = slot.Add(interval)
+	l.mu.Unlock()
+
+	if wait := time.Until(slot); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.release()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// release libera el hueco de concurrencia ocupado por una petición
+func (l *hostLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// releaseOnClose libera el hueco de concurrencia del host al cerrar el cuerpo (una sola vez)
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// robotsAllowed consulta (y cachea) el robots.txt del host; si no se puede obtener, se permite todo
+func (t *HostTransport) robotsAllowed(ctx context.Context, req *http.Request) bool {
+	host := strings.ToLower(req.URL.Host)
+
+	t.state.mu.Lock()
+	rules, ok := t.state.robots[host]
+	t.state.mu.Unlock()
+
+	if !ok || time.Since(rules.fetchedAt) > robotsTTL {
+		rules = t.fetchRobots(ctx, req.URL.Scheme, req.URL.Host)
+		t.state.mu.Lock()
+		t.state.robots[host] = rules
+		t.state.mu.Unlock()
+	}
+	return rules.allows(req.URL.RequestURI())
+}
+
+// fetchRobots descarga robots.txt directamente con el transporte de red (sin límites ni reintentos)
+func (t *HostTransport) fetchRobots(ctx context.Context, scheme, host string) *robotsRules {
+	rules := &robotsRules{fetchedAt: time.Now()}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/robots.txt", nil)
+	if err != nil {
+		return rules
+	}
+	req.Header.Set("User-Agent", browserUserAgent)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return rules
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return rules
+	}
+
+	// Solo se aplican los grupos "User-agent: *"
+	applies := false
+	inAgents := false
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 512*1024))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx != -1 {
+			line = line[:idx]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				applies = false
+			}
+			inAgents = true
+			if value == "*" {
+				applies = true
+			}
+		case "allow", "disallow":
+			inAgents = false
+			if !applies || value == "" {
+				continue
+			}
+			if key == "allow" {
+				rules.allow = append(rules.allow, value)
+			} else {
+				rules.disallow = append(rules.disallow, value)
+			}
+		default:
+			inAgents = false
+		}
+	}
+	return rules
+}
+
+// allows aplica la regla más específica (el prefijo más largo); en empate gana Allow
+func (r *robotsRules) allows(path string) bool {
+	longestAllow, longestDisallow := -1, -1
+	for _, p := range r.allow {
+		if robotsMatch(p, path) && len(p) > longestAllow {
+			longestAllow = len(p)
+		}
+	}
+	for _, p := range r.disallow {
+		if robotsMatch(p, path) && len(p) > longestDisallow {
+			longestDisallow = len(p)
+		}
+	}
+	return longestDisallow == -1 || longestAllow >= longestDisallow
+}
+
+// robotsMatch compara una regla de robots.txt (con comodín '*' y ancla '$') con una ruta
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for _, part := range parts[1:] {
+		idx := strings.Index(rest, part)
+		if idx == -1 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	if anchored {
+		return rest == "" || (len(parts) > 1 && strings.HasSuffix(path, parts[len(parts)-1]))
+	}
+	return true
+}
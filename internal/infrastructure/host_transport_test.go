@@ -0,0 +1,288 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRobotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/cualquier/cosa", true},
+		{"/privado", "/privado/pagina.html", true},
+		{"/privado", "/privadox", true},
+		{"/privado/", "/privado", false},
+		{"/publico", "/privado", false},
+		{"/*.pdf", "/docs/informe.pdf", true},
+		{"/*.pdf", "/docs/informe.pdf?v=1", true},
+		{"/*.pdf$", "/docs/informe.pdf", true},
+		{"/*.pdf$", "/docs/informe.pdf?v=1", false},
+		{"/fin$", "/fin", true},
+		{"/fin$", "/final", false},
+		{"/*/amp/", "/noticias/amp/123", true},
+		{"/*/amp/", "/noticias/123", false},
+		{"/a*b*c", "/axxbyyc", true},
+		{"/a*b*c", "/axxcyyb", false},
+		{"*", "/x", true},
+	}
+	for _, tt := range tests {
+		if got := robotsMatch(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("robotsMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRobotsRulesAllows(t *testing.T) {
+	rules := &robotsRules{
+		allow:    []string{"/privado/publico", "/buscar"},
+		disallow: []string{"/privado", "/buscar", "/*.json$"},
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/noticias/1", true},
+		{"/privado/datos", false},
+		{"/privado/publico/1", true}, // La regla más larga gana
+		{"/buscar?q=x", true},        // En empate gana Allow
+		{"/api/items.json", false},
+		{"/api/items.json?x=1", true},
+	}
+	for _, tt := range tests {
+		if got := rules.allows(tt.path); got != tt.want {
+			t.Errorf("allows(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if !(&robotsRules{}).allows("/lo-que-sea") {
+		t.Error("sin reglas debería permitirse todo")
+	}
+}
+
+func TestHostTransportRobots(t *testing.T) {
+	var robotsFetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&robotsFetches, 1)
+			w.Write([]byte(strings.Join([]string{
+				"User-agent: Googlebot",
+				"Disallow: /",
+				"",
+				"User-agent: otro",
+				"User-agent: *",
+				"Disallow: /privado # comentario",
+				"Allow: /privado/rss",
+			}, "\n")))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewHostTransport(HostPolicy{RespectRobots: true})}
+	tests := []struct {
+		path    string
+		blocked bool
+	}{
+		{"/noticias", false},
+		{"/privado/pagina", true},
+		{"/privado/rss", false},
+	}
+	for _, tt := range tests {
+		resp, err := client.Get(server.URL + tt.path)
+		if tt.blocked {
+			if !errors.Is(err, ErrRobotsDisallowed) {
+				t.Errorf("%s: err = %v, want ErrRobotsDisallowed", tt.path, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+	if n := atomic.LoadInt32(&robotsFetches); n != 1 {
+		t.Errorf("robots.txt descargado %d veces, want 1 (cacheado)", n)
+	}
+}
+
+func TestHostTransportRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int
+		maxRetries int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{"503 y luego 200", http.MethodGet, []int{503, 503, 200}, 3, 200, 3},
+		{"429 y luego 200", http.MethodGet, []int{429, 200}, 3, 200, 2},
+		{"agota los reintentos", http.MethodGet, []int{500, 500, 500, 500}, 2, 500, 3},
+		{"404 no se reintenta", http.MethodGet, []int{404, 200}, 3, 404, 1},
+		{"sin reintentos configurados", http.MethodGet, []int{503, 200}, 0, 503, 1},
+		{"peticiones con cuerpo no se reintentan", http.MethodPost, []int{503, 200}, 3, 503, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				status := tt.statuses[len(tt.statuses)-1]
+				if int(n) <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			transport := NewHostTransport(HostPolicy{
+				MaxRetries:              tt.maxRetries,
+				RetryBaseDelay:          time.Millisecond,
+				RetryMaxDelay:           5 * time.Millisecond,
+				CircuitFailureThreshold: 100,
+			})
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader("x")
+			}
+			req, err := http.NewRequest(tt.method, server.URL, body)
+			if err != nil {
+				t.Fatalf("NewRequest: %v", err)
+			}
+
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHostTransportBackoff(t *testing.T) {
+	transport := NewHostTransport(HostPolicy{RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second})
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		min, max   time.Duration
+	}{
+		{0, 0, 100 * time.Millisecond, 150 * time.Millisecond},
+		{1, 0, 200 * time.Millisecond, 300 * time.Millisecond},
+		{2, 0, 400 * time.Millisecond, 600 * time.Millisecond},
+		{5, 0, time.Second, time.Second},
+		{0, 700 * time.Millisecond, 700 * time.Millisecond, 700 * time.Millisecond},
+		{0, time.Minute, time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if got := transport.backoff(tt.attempt, tt.retryAfter); got < tt.min || got > tt.max {
+				t.Errorf("backoff(%d, %v) = %v, want in [%v, %v]", tt.attempt, tt.retryAfter, got, tt.min, tt.max)
+				break
+			}
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsTransient(t *testing.T) {
+	withStatus := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+	tests := []struct {
+		name       string
+		resp       *http.Response
+		err        error
+		transient  bool
+		retryAfter time.Duration
+	}{
+		{"timeout de red", nil, timeoutError{}, true, 0},
+		{"error no transitorio", nil, errors.New("connection refused"), false, 0},
+		{"200", withStatus(200, ""), nil, false, 0},
+		{"404", withStatus(404, ""), nil, false, 0},
+		{"429 con Retry-After", withStatus(429, "3"), nil, true, 3 * time.Second},
+		{"502", withStatus(502, ""), nil, true, 0},
+		{"503 con Retry-After inválido", withStatus(503, "pronto"), nil, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transient, retryAfter := isTransient(tt.resp, tt.err)
+			if transient != tt.transient || retryAfter != tt.retryAfter {
+				t.Errorf("isTransient = %v, %v; want %v, %v", transient, retryAfter, tt.transient, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("120"); got != 2*time.Minute {
+		t.Errorf("parseRetryAfter(120) = %v", got)
+	}
+	for _, v := range []string{"", "0", "-5", "mañana"} {
+		if got := parseRetryAfter(v); got != 0 {
+			t.Errorf("parseRetryAfter(%q) = %v, want 0", v, got)
+		}
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 55*time.Second || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %v, want about 1m", date, got)
+	}
+}
+
+func TestHostLimiterConcurrency(t *testing.T) {
+	transport := NewHostTransport(HostPolicy{MaxConcurrent: 1})
+	limiter := transport.limiter("example.com")
+
+	if err := limiter.acquire(context.Background(), 0); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.acquire(ctx, 0); err == nil {
+		t.Fatal("el segundo acquire debería esperar al hueco ocupado")
+	}
+	limiter.release()
+	if err := limiter.acquire(context.Background(), 0); err != nil {
+		t.Fatalf("acquire tras release: %v", err)
+	}
+	limiter.release()
+}
+
+func TestHostLimiterRate(t *testing.T) {
+	limiter := &hostLimiter{}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.acquire(context.Background(), 50); err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+	}
+	// 3 peticiones a 50/s: la tercera sale al menos 40ms después de la primera
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("elapsed = %v, want >= 40ms", elapsed)
+	}
+}
@@ -0,0 +1,232 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// maxHTMLPageSize limita el tamaño de la página de listado que se descarga (5MB)
+const maxHTMLPageSize = 5 * 1024 * 1024
+
+// flexibleDateLayouts son los formatos de fecha que se intentan al leer fechas de páginas HTML y sitemaps
+var flexibleDateLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"02/01/2006 15:04",
+	"02/01/2006",
+	"02-01-2006",
+}
+
+// htmlFetcher implementa la interfaz RSSFetcher para páginas HTML sin feed.
+// Los campos de extracción son selectores CSS relativos a cada noticia y admiten
+// el sufijo "@atributo" para leer un atributo en lugar del texto (ej: "time@datetime").
+type htmlFetcher struct {
+	httpClient *http.Client
+}
+
+// NewHTMLFetcher crea una nueva instancia de fetcher para fuentes de tipo "html"
+func NewHTMLFetcher(transport http.RoundTripper) domain.RSSFetcher {
+	return &htmlFetcher{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+	}
+}
+
+// Fetch descarga la página de listado y extrae las noticias con selectores CSS.
+// En este fetcher el parámetro filter contiene el selector del contenedor de cada noticia.
+func (f *htmlFetcher) Fetch(ctx context.Context, pageURL string, filter string, titleField, imageField, linkField, dateField string) ([]domain.NewsItem, error) {
+	pageURL = strings.TrimSpace(pageURL)
+	itemSelector := strings.TrimSpace(filter)
+	if itemSelector == "" {
+		return nil, fmt.Errorf("la fuente HTML necesita un selector de noticias")
+	}
+	if titleField == "" || linkField == "" {
+		return nil, fmt.Errorf("la fuente HTML necesita selectores de título y link")
+	}
+
+	utils.AppInfo("HTML_FETCHER", "Iniciando extracción HTML", map[string]interface{}{
+		"item_selector": itemSelector,
+		"url":           pageURL,
+	})
+
+	baseURL, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("URL de página inválida: %w", err)
+	}
+
+	client := httpClientFor(ctx, f.httpClient)
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	req, err := newSourceRequest(ctx, pageURL, browserUserAgent, "text/html,application/xhtml+xml")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		utils.SourceError(pageURL, err.Error())
+		return nil, fmt.Errorf("error al obtener página HTML: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxHTMLPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("error al parsear HTML: %w", err)
+	}
+
+	// Respetar <base href> si la página lo declara
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if base, err := baseURL.Parse(strings.TrimSpace(href)); err == nil {
+			baseURL = base
+		}
+	}
+
+	selection := doc.Find(itemSelector)
+	utils.AppInfo("HTML_FETCHER", "Página obtenida exitosamente", map[string]interface{}{
+		"items_count": selection.Length(),
+		"url":         pageURL,
+	})
+
+	var items []domain.NewsItem
+	selection.Each(func(i int, s *goquery.Selection) {
+		newsNum := i + 1
+
+		title := strings.Join(strings.Fields(extractFromSelection(s, titleField, "")), " ")
+		if title == "" {
+			utils.NewsWarn("", "", fmt.Sprintf("Noticia %d", newsNum), fmt.Sprintf("título fallido (%s) → noticia descartada", titleField))
+			return
+		}
+
+		linkURL := resolveURL(baseURL, extractFromSelection(s, linkField, "href"))
+		if linkURL == "" {
+			utils.NewsWarn("", "", fmt.Sprintf("Noticia %d", newsNum), fmt.Sprintf("link fallido (%s) → noticia descartada", linkField))
+			return
+		}
+
+		var imageURL string
+		if imageField != "" {
+			imageURL = resolveURL(baseURL, extractFromSelection(s, imageField, "src"))
+		}
+
+		pubDate := time.Now()
+		if dateField != "" {
+			if t, ok := parseFlexibleDate(extractFromSelection(s, dateField, "datetime")); ok {
+				pubDate = t
+			}
+		}
+
+		items = append(items, domain.NewsItem{
+			Title:   title,
+			Link:    linkURL,
+			Image:   imageURL,
+			PubDate: pubDate,
+		})
+	})
+
+	utils.SourceProcessingComplete(pageURL, len(items), selection.Length())
+	return items, nil
+}
+
+// extractFromSelection aplica un campo "selector@atributo" sobre una noticia, soportando alternativas con '|'.
+// Sin "@atributo" se usa defaultAttr (si existe en el nodo) y en último caso el texto del nodo.
+func extractFromSelection(s *goquery.Selection, field, defaultAttr string) string {
+	for _, f := range strings.Split(field, "|") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		selector, attr := f, ""
+		if idx := strings.LastIndex(f, "@"); idx != -1 {
+			selector, attr = strings.TrimSpace(f[:idx]), strings.TrimSpace(f[idx+1:])
+		}
+
+		node := s
+		if selector != "" {
+			node = s.Find(selector).First()
+			if node.Length() == 0 && s.Is(selector) {
+				node = s
+			}
+		}
+		if node.Length() == 0 {
+			continue
+		}
+
+		if attr != "" {
+			if v, ok := node.Attr(attr); ok && strings.TrimSpace(v) != "" {
+				return strings.TrimSpace(v)
+			}
+			continue
+		}
+
+		if defaultAttr != "" {
+			if v, ok := node.Attr(defaultAttr); ok && strings.TrimSpace(v) != "" {
+				return strings.TrimSpace(v)
+			}
+			// Imágenes con carga diferida
+			if defaultAttr == "src" {
+				if v, ok := node.Attr("data-src"); ok && strings.TrimSpace(v) != "" {
+					return strings.TrimSpace(v)
+				}
+			}
+		}
+
+		if text := strings.TrimSpace(node.Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// resolveURL resuelve una URL relativa respecto a la URL de la página
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "javascript:") {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// parseFlexibleDate intenta interpretar una fecha con los formatos más habituales en páginas de noticias
+func parseFlexibleDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range flexibleDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
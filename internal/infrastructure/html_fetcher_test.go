@@ -0,0 +1,135 @@
+package infrastructure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const htmlListingFixture = `<!doctype html>
+<html><head><base href="/noticias/"></head><body>
+<article class="item">
+  <h2><a href="uno.html">  Primera
+     noticia </a></h2>
+  <img data-src="img/uno.jpg">
+  <time datetime="2024-05-01T10:00:00Z">1 de mayo</time>
+</article>
+<article class="item">
+  <h2><a href="https://otro.example/dos">Segunda noticia</a></h2>
+  <img src="javascript:void(0)">
+  <span class="fecha">02/05/2024</span>
+</article>
+<article class="item">
+  <p>Sin título ni enlace</p>
+</article>
+</body></html>`
+
+func TestHTMLFetcherFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(htmlListingFixture))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTMLFetcher(nil)
+	items, err := fetcher.Fetch(context.Background(), server.URL+"/portada", "article.item", "h2 a", "img", "h2 a", "time@datetime|.fecha")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	first := items[0]
+	if first.Title != "Primera noticia" {
+		t.Errorf("title = %q", first.Title)
+	}
+	if want := server.URL + "/noticias/uno.html"; first.Link != want {
+		t.Errorf("link = %q, want %q (resuelto con <base href>)", first.Link, want)
+	}
+	if want := server.URL + "/noticias/img/uno.jpg"; first.Image != want {
+		t.Errorf("image = %q, want %q (data-src)", first.Image, want)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !first.PubDate.Equal(want) {
+		t.Errorf("pubDate = %v, want %v", first.PubDate, want)
+	}
+
+	second := items[1]
+	if second.Link != "https://otro.example/dos" {
+		t.Errorf("link = %q", second.Link)
+	}
+	if second.Image != "" {
+		t.Errorf("image = %q, want empty for javascript: URL", second.Image)
+	}
+	if want := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC); !second.PubDate.Equal(want) {
+		t.Errorf("pubDate = %v, want %v (alternativa .fecha)", second.PubDate, want)
+	}
+}
+
+func TestHTMLFetcherFetchRequiresSelectors(t *testing.T) {
+	fetcher := NewHTMLFetcher(nil)
+	tests := []struct {
+		name                string
+		filter, title, link string
+	}{
+		{"sin selector de noticias", "", "h2", "a"},
+		{"sin título", "article", "", "a"},
+		{"sin link", "article", "h2", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fetcher.Fetch(context.Background(), "http://example.invalid", tt.filter, tt.title, "", tt.link, ""); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	base, _ := url.Parse("https://example.com/seccion/portada.html")
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"noticia.html", "https://example.com/seccion/noticia.html"},
+		{"/raiz", "https://example.com/raiz"},
+		{"//cdn.example.com/a.jpg", "https://cdn.example.com/a.jpg"},
+		{"  https://otro.com/x  ", "https://otro.com/x"},
+		{"", ""},
+		{"data:image/png;base64,AAAA", ""},
+		{"javascript:alert(1)", ""},
+		{"mailto:redaccion@example.com", ""},
+	}
+	for _, tt := range tests {
+		if got := resolveURL(base, tt.ref); got != tt.want {
+			t.Errorf("resolveURL(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestParseFlexibleDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{"2024-05-01T10:00:00Z", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"2024-05-01T10:00Z", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"Wed, 01 May 2024 10:00:00 +0000", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"2024-05-01 10:00:00", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{"01/05/2024 10:00", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"01-05-2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{" ", time.Time{}, false},
+		{"ayer", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseFlexibleDate(tt.value)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseFlexibleDate(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
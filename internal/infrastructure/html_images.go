@@ -0,0 +1,219 @@
+package infrastructure
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// minContentImageSide es el lado mínimo declarado para no tratar una imagen como icono o píxel de seguimiento
+const minContentImageSide = 50
+
+// lazySrcAttrs son los atributos de carga diferida que llevan la URL real de la imagen, por prioridad
+var lazySrcAttrs = []string{"data-src", "data-lazy-src", "data-original", "data-lazy", "data-url", "src"}
+
+// lazySrcsetAttrs son los atributos con el srcset real, por prioridad
+var lazySrcsetAttrs = []string{"data-srcset", "data-lazy-srcset", "srcset"}
+
+// trackingImageHints son fragmentos de URL propios de píxeles de seguimiento, contadores, avatares y emojis
+var trackingImageHints = []string{
+	"/pixel.", "/pixel?", "/pixel/", "tracking-pixel", "/beacon", "1x1.", "spacer.", "blank.gif",
+	"feeds.feedburner.com/~", "doubleclick.net", "stats.wordpress.com",
+	"gravatar.com/avatar", "/emoji/",
+}
+
+// extractHTMLImages recorre con el tokenizador de HTML un fragmento (descripción o content:encoded)
+// y devuelve sus imágenes en orden de aparición. Tiene en cuenta atributos de carga diferida, elige el
+// mejor candidato de srcset para targetWidth, resuelve URLs relativas con base y descarta píxeles e iconos.
+func extractHTMLImages(fragment string, base *url.URL, targetWidth int) []imageCandidate {
+	if !strings.Contains(fragment, "<") {
+		return nil
+	}
+
+	var candidates []imageCandidate
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return candidates
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		token := tokenizer.Token()
+		switch token.Data {
+		case "img", "source":
+		default:
+			continue
+		}
+
+		attrs := make(map[string]string, len(token.Attr))
+		for _, attr := range token.Attr {
+			attrs[strings.ToLower(attr.Key)] = strings.TrimSpace(attr.Val)
+		}
+		// <source> de <picture> o <video>: solo interesan las de imagen
+		if token.Data == "source" {
+			if mimeType := attrs["type"]; mimeType != "" && !strings.HasPrefix(mimeType, "image/") {
+				continue
+			}
+		}
+
+		candidate := imageCandidate{}
+		candidate.width, _ = strconv.Atoi(strings.TrimSuffix(attrs["width"], "px"))
+		candidate.height, _ = strconv.Atoi(strings.TrimSuffix(attrs["height"], "px"))
+
+		for _, name := range lazySrcsetAttrs {
+			if srcset := attrs[name]; srcset != "" {
+				if src, width := bestSrcsetCandidate(srcset, targetWidth); src != "" {
+					candidate.url = src
+					if width > 0 {
+						// El ancho de srcset es el real; el alto se escala si se conoce la proporción
+						if candidate.width > 0 && candidate.height > 0 {
+							candidate.height = candidate.height * width / candidate.width
+						}
+						candidate.width = width
+					}
+					break
+				}
+			}
+		}
+		if candidate.url == "" {
+			for _, name := range lazySrcAttrs {
+				if src := attrs[name]; src != "" && !strings.HasPrefix(src, "data:") {
+					candidate.url = src
+					break
+				}
+			}
+		}
+
+		candidate.url = resolveImageURL(base, candidate.url)
+		if candidate.url == "" || isTrackingImage(candidate) {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+}
+
+// bestSrcsetCandidate elige de un srcset la imagen más pequeña que cubre targetWidth o, si ninguna
+// llega, la más grande. Con descriptores de densidad (2x) se elige la de mayor densidad.
+func bestSrcsetCandidate(srcset string, targetWidth int) (string, int) {
+	var best string
+	var bestWidth int
+	var bestDensity float64
+	var covers bool
+
+	for _, entry := range splitSrcset(srcset) {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "data:") {
+			continue
+		}
+		src := fields[0]
+		descriptor := ""
+		if len(fields) > 1 {
+			descriptor = strings.ToLower(fields[1])
+		}
+
+		switch {
+		case strings.HasSuffix(descriptor, "w"):
+			width, err := strconv.Atoi(strings.TrimSuffix(descriptor, "w"))
+			if err != nil {
+				continue
+			}
+			entryCovers := width >= targetWidth
+			switch {
+			case best == "" || bestWidth == 0:
+				best, bestWidth, covers = src, width, entryCovers
+			case entryCovers && (!covers || width < bestWidth):
+				best, bestWidth, covers = src, width, true
+			case !entryCovers && !covers && width > bestWidth:
+				best, bestWidth = src, width
+			}
+		default:
+			density := 1.0
+			if strings.HasSuffix(descriptor, "x") {
+				if d, err := strconv.ParseFloat(strings.TrimSuffix(descriptor, "x"), 64); err == nil {
+					density = d
+				}
+			}
+			if bestWidth == 0 && (best == "" || density > bestDensity) {
+				best, bestDensity = src, density
+			}
+		}
+	}
+	return best, bestWidth
+}
+
+// splitSrcset separa las entradas de un srcset con el algoritmo de HTML: la URL llega hasta el primer
+// espacio (las comas de parámetros de CDN no separan entradas), una coma pegada al final de la URL o
+// detrás de los descriptores cierra la entrada aunque no la siga un espacio, y las comas entre paréntesis
+// de los descriptores no cuentan. Cada entrada es la URL seguida de sus descriptores separados por espacios.
+func splitSrcset(srcset string) []string {
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+	var entries []string
+	i := 0
+	for {
+		for i < len(srcset) && (isSpace(srcset[i]) || srcset[i] == ',') {
+			i++
+		}
+		if i == len(srcset) {
+			return entries
+		}
+
+		start := i
+		for i < len(srcset) && !isSpace(srcset[i]) {
+			i++
+		}
+		src := srcset[start:i]
+		if trimmed := strings.TrimRight(src, ","); trimmed != src {
+			// La coma pegada a la URL cierra la entrada, sin descriptores
+			entries = append(entries, trimmed)
+			continue
+		}
+
+		start = i
+		depth := 0
+		for ; i < len(srcset) && (srcset[i] != ',' || depth > 0); i++ {
+			switch srcset[i] {
+			case '(':
+				depth++
+			case ')':
+				if depth > 0 {
+					depth--
+				}
+			}
+		}
+		if descriptors := strings.Fields(srcset[start:i]); len(descriptors) > 0 {
+			src += " " + strings.Join(descriptors, " ")
+		}
+		entries = append(entries, src)
+	}
+}
+
+// resolveImageURL resuelve ref respecto a base; sin base solo se aceptan URLs absolutas http(s)
+func resolveImageURL(base *url.URL, ref string) string {
+	if base != nil {
+		return resolveURL(base, ref)
+	}
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+// isTrackingImage indica si la imagen es un píxel de seguimiento, un icono o un avatar
+func isTrackingImage(c imageCandidate) bool {
+	if (c.width > 0 && c.width < minContentImageSide) || (c.height > 0 && c.height < minContentImageSide) {
+		return true
+	}
+	lower := strings.ToLower(c.url)
+	for _, hint := range trackingImageHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package infrastructure
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExtractHTMLImages(t *testing.T) {
+	base, _ := url.Parse("https://example.com/noticias/1")
+	tests := []struct {
+		name     string
+		fragment string
+		base     *url.URL
+		want     []imageCandidate
+	}{
+		{"texto sin HTML", "Solo texto", base, nil},
+		{
+			"src relativo con dimensiones",
+			`<p><img src="/img/a.jpg" width="800px" height="450"></p>`,
+			base,
+			[]imageCandidate{{url: "https://example.com/img/a.jpg", width: 800, height: 450}},
+		},
+		{
+			"carga diferida con placeholder data:",
+			`<img src="data:image/gif;base64,R0lGOD" data-lazy-src="lazy.jpg">`,
+			base,
+			[]imageCandidate{{url: "https://example.com/noticias/lazy.jpg"}},
+		},
+		{
+			"srcset con ancho real y alto escalado",
+			`<img src="p.jpg" width="400" height="225" srcset="s.jpg 400w, m.jpg 800w, l.jpg 1600w">`,
+			base,
+			[]imageCandidate{{url: "https://example.com/noticias/m.jpg", width: 800, height: 450}},
+		},
+		{
+			"picture con source de imagen y de vídeo",
+			`<picture><source type="video/mp4" srcset="v.mp4"><source type="image/webp" srcset="a.webp 1x, a2.webp 2x"><img src="a.jpg"></picture>`,
+			base,
+			[]imageCandidate{{url: "https://example.com/noticias/a2.webp"}, {url: "https://example.com/noticias/a.jpg"}},
+		},
+		{
+			"píxeles, iconos y avatares descartados",
+			`<img src="https://feeds.feedburner.com/~r/x/~4/abc"><img src="https://a.com/ico.png" width="16" height="16"><img src="https://secure.gravatar.com/avatar/1"><img src="https://a.com/foto.jpg">`,
+			base,
+			[]imageCandidate{{url: "https://a.com/foto.jpg"}},
+		},
+		{
+			"sin base solo URLs absolutas http(s)",
+			`<img src="/relativa.jpg"><img src="ftp://a.com/x.jpg"><img src="https://a.com/ok.jpg">`,
+			nil,
+			[]imageCandidate{{url: "https://a.com/ok.jpg"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractHTMLImages(tt.fragment, tt.base, 800)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractHTMLImages:\n got  %+v\n want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestSrcsetCandidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		srcset    string
+		target    int
+		wantURL   string
+		wantWidth int
+	}{
+		{"la más pequeña que cubre", "a.jpg 400w, b.jpg 800w, c.jpg 1200w", 700, "b.jpg", 800},
+		{"orden arbitrario", "c.jpg 1200w, a.jpg 400w, b.jpg 800w", 700, "b.jpg", 800},
+		{"ninguna cubre: la más grande", "a.jpg 300w, b.jpg 500w", 800, "b.jpg", 500},
+		{"coincidencia exacta", "a.jpg 800w, b.jpg 1600w", 800, "a.jpg", 800},
+		{"densidades: la mayor", "a.jpg, b.jpg 2x, c.jpg 1.5x", 800, "b.jpg", 0},
+		{"w tiene preferencia sobre x", "a.jpg 3x, b.jpg 600w", 800, "b.jpg", 600},
+		{"sin espacio tras la coma", "a.jpg 320w,b.jpg 800w", 800, "b.jpg", 800},
+		{"sin espacio tras la coma con comas en la URL", "i.jpg?w=400,h=225 400w,i.jpg?w=800,h=450 800w", 800, "i.jpg?w=800,h=450", 800},
+		{"comas dentro de la URL", "https://cdn.example.com/i.jpg?w=400,h=225 400w, https://cdn.example.com/i.jpg?w=800,h=450 800w", 800, "https://cdn.example.com/i.jpg?w=800,h=450", 800},
+		{"descriptor inválido", "a.jpg abcw, b.jpg 500w", 800, "b.jpg", 500},
+		{"ignora data:", "data:image/png;base64,AAA 100w, b.jpg 500w", 800, "b.jpg", 500},
+		{"vacío", "", 800, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, gotWidth := bestSrcsetCandidate(tt.srcset, tt.target)
+			if gotURL != tt.wantURL || gotWidth != tt.wantWidth {
+				t.Errorf("bestSrcsetCandidate = %q, %d; want %q, %d", gotURL, gotWidth, tt.wantURL, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestSplitSrcset(t *testing.T) {
+	tests := []struct {
+		srcset string
+		want   []string
+	}{
+		{"a.jpg 1x", []string{"a.jpg 1x"}},
+		{"a.jpg 1x, b.jpg 2x", []string{"a.jpg 1x", "b.jpg 2x"}},
+		{"a.jpg 320w,b.jpg 800w", []string{"a.jpg 320w", "b.jpg 800w"}},
+		{"a.jpg, b.jpg 2x", []string{"a.jpg", "b.jpg 2x"}},
+		{"a.jpg,b.jpg 2x", []string{"a.jpg,b.jpg 2x"}},
+		{"a.jpg?x=1,2 1x,\tb.jpg 2x", []string{"a.jpg?x=1,2 1x", "b.jpg 2x"}},
+		{"i.jpg?w=400,h=225 400w,i.jpg?w=800,h=450 800w", []string{"i.jpg?w=400,h=225 400w", "i.jpg?w=800,h=450 800w"}},
+		{"  a.jpg   1x  ,, b.jpg", []string{"a.jpg 1x", "b.jpg"}},
+		{"a.jpg (max-width: 1px, 2px) 1x, b.jpg 2x", []string{"a.jpg (max-width: 1px, 2px) 1x", "b.jpg 2x"}},
+		{"a.jpg 1x,", []string{"a.jpg 1x"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitSrcset(tt.srcset); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitSrcset(%q) = %q, want %q", tt.srcset, got, tt.want)
+		}
+	}
+}
+
+func TestIsTrackingImage(t *testing.T) {
+	tests := []struct {
+		candidate imageCandidate
+		want      bool
+	}{
+		{imageCandidate{url: "https://a.com/foto.jpg"}, false},
+		{imageCandidate{url: "https://a.com/foto.jpg", width: 800, height: 450}, false},
+		{imageCandidate{url: "https://a.com/foto.jpg", width: 1, height: 1}, true},
+		{imageCandidate{url: "https://a.com/foto.jpg", height: 40}, true},
+		{imageCandidate{url: "https://a.com/Pixel.gif"}, true},
+		{imageCandidate{url: "https://stats.wordpress.com/b.gif?x=1"}, true},
+		{imageCandidate{url: "https://s.w.org/images/core/emoji/14.0/72x72/1f600.png"}, true},
+	}
+	for _, tt := range tests {
+		if got := isTrackingImage(tt.candidate); got != tt.want {
+			t.Errorf("isTrackingImage(%+v) = %v, want %v", tt.candidate, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// browserUserAgent es el User-Agent por defecto de las peticiones que imitan a un navegador
+const browserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
+// profileClientCache guarda los *http.Client construidos para cada perfil, por timeout del cliente base,
+// para reutilizar conexiones entre peticiones. Solo se conserva la versión vigente (UpdatedAt) de cada perfil.
+type profileClientCache struct {
+	mu      sync.Mutex
+	entries map[uint]*profileClients
+}
+
+// profileClients son los clientes de una versión de un perfil
+type profileClients struct {
+	updatedAt time.Time
+	clients   map[time.Duration]*http.Client
+}
+
+// profileClientsCache es la caché compartida por todos los fetchers
+var profileClientsCache = &profileClientCache{entries: make(map[uint]*profileClients)}
+
+// HTTPProfileClients devuelve la caché de clientes por perfil, para invalidarla al editar o borrar perfiles
+func HTTPProfileClients() domain.HTTPProfileClientCache {
+	return profileClientsCache
+}
+
+// httpClientFor devuelve el cliente HTTP del perfil del contexto, o fallback si no hay perfil
+func httpClientFor(ctx context.Context, fallback *http.Client) *http.Client {
+	profile := domain.HTTPProfileFromContext(ctx)
+	if profile == nil {
+		return fallback
+	}
+	return profileClientsCache.get(profile, fallback)
+}
+
+// get devuelve el cliente del perfil para el timeout de fallback; si el perfil ha cambiado desde que se
+// construyeron sus clientes, los descarta y construye uno nuevo
+func (c *profileClientCache) get(profile *domain.HTTPProfile, fallback *http.Client) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[profile.ID]
+	if entry == nil || !entry.updatedAt.Equal(profile.UpdatedAt) {
+		if entry != nil {
+			entry.close()
+		}
+		entry = &profileClients{updatedAt: profile.UpdatedAt, clients: make(map[time.Duration]*http.Client)}
+		c.entries[profile.ID] = entry
+	}
+	if client, ok := entry.clients[fallback.Timeout]; ok {
+		return client
+	}
+	client := buildProfileClient(profile, fallback)
+	entry.clients[fallback.Timeout] = client
+	return client
+}
+
+// Invalidate descarta los clientes del perfil y cierra sus conexiones inactivas
+func (c *profileClientCache) Invalidate(profileID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[profileID]; ok {
+		entry.close()
+		delete(c.entries, profileID)
+	}
+}
+
+// close cierra las conexiones inactivas de los clientes; las peticiones en curso terminan con normalidad
+func (p *profileClients) close() {
+	for _, client := range p.clients {
+		client.CloseIdleConnections()
+	}
+}
+
+// buildProfileClient construye un cliente con el proxy, TLS, timeout y política de redirección del perfil.
+// Si el cliente base usa un HostTransport, el del perfil comparte sus límites por host.
+func buildProfileClient(profile *domain.HTTPProfile, fallback *http.Client) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if profile.ProxyURL != "" {
+		if proxyURL, err := url.Parse(expandSecrets(profile.ProxyURL)); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		} else {
+			utils.AppWarn("HTTP_PROFILE", "Proxy inválido, se ignora", map[string]interface{}{
+				"profile": profile.Name,
+			})
+		}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: profile.InsecureSkipVerify}
+	switch profile.MinTLSVersion {
+	case "1.0":
+		tlsConfig.MinVersion = tls.VersionTLS10
+	case "1.1":
+		tlsConfig.MinVersion = tls.VersionTLS11
+	case "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	var roundTripper http.RoundTripper = transport
+	if hostTransport, ok := fallback.Transport.(*HostTransport); ok {
+		roundTripper = hostTransport.WithBase(transport)
+	}
+
+	timeout := fallback.Timeout
+	if profile.TimeoutSeconds > 0 {
+		timeout = time.Duration(profile.TimeoutSeconds) * time.Second
+	}
+
+	maxRedirects := profile.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = 10
+	}
+	policy := profile.RedirectPolicy
+
+	return &http.Client{
+		Transport: roundTripper,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			switch {
+			case policy == domain.RedirectNone:
+				return http.ErrUseLastResponse
+			case policy == domain.RedirectSameHost && req.URL.Host != via[0].URL.Host:
+				return fmt.Errorf("redirección a otro host bloqueada por el perfil: %s", req.URL.Host)
+			case len(via) >= maxRedirects:
+				return fmt.Errorf("demasiadas redirecciones (%d)", len(via))
+			}
+			// net/http copia al destino las cabeceras propias (y quita Authorization y Cookie si cambia de
+			// dominio): las del perfil solo siguen a los hosts que pueden recibirlas
+			sourceHost := domain.HTTPProfileHostFromContext(req.Context())
+			if credentialsAllowed(profile, sourceHost, req.URL.Hostname()) {
+				applyHTTPProfile(req, profile, sourceHost)
+			} else {
+				stripProfileCredentials(req, profile)
+			}
+			return nil
+		},
+	}
+}
+
+// newSourceRequest crea una petición GET con el User-Agent y Accept indicados y aplica el perfil del contexto
+func newSourceRequest(ctx context.Context, target, userAgent, accept string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando petición: %w", err)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	applyHTTPProfile(req, domain.HTTPProfileFromContext(ctx), domain.HTTPProfileHostFromContext(ctx))
+	return req, nil
+}
+
+// applyHTTPProfile añade a la petición el User-Agent del perfil y, si el destino es el host de la fuente
+// (sourceHost) o uno de sus CredentialHosts, también sus cabeceras y su autenticación. Así las imágenes de
+// CDNs, los artículos o los hubs enlazados desde el feed no reciben las credenciales de la fuente.
+func applyHTTPProfile(req *http.Request, profile *domain.HTTPProfile, sourceHost string) {
+	if profile == nil {
+		return
+	}
+
+	if profile.UserAgent != "" {
+		req.Header.Set("User-Agent", profile.UserAgent)
+	}
+	if !credentialsAllowed(profile, sourceHost, req.URL.Hostname()) {
+		return
+	}
+
+	for name, value := range profileHeaders(profile) {
+		req.Header.Set(name, expandSecrets(value))
+	}
+
+	secret := ""
+	if domain.IsSecretEnv(profile.SecretEnv) {
+		secret = os.Getenv(profile.SecretEnv)
+	}
+	switch profile.AuthType {
+	case domain.HTTPAuthBasic:
+		req.SetBasicAuth(profile.Username, secret)
+	case domain.HTTPAuthBearer:
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+}
+
+// profileHeaders devuelve las cabeceras adicionales del perfil (sin expandir)
+func profileHeaders(profile *domain.HTTPProfile) map[string]string {
+	if profile.Headers == nil || *profile.Headers == "" {
+		return nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(*profile.Headers), &headers); err != nil {
+		utils.AppWarn("HTTP_PROFILE", "Cabeceras del perfil inválidas, se ignoran", map[string]interface{}{
+			"profile": profile.Name,
+			"error":   err.Error(),
+		})
+	}
+	return headers
+}
+
+// stripProfileCredentials quita de la petición las cabeceras y la autenticación del perfil
+func stripProfileCredentials(req *http.Request, profile *domain.HTTPProfile) {
+	for name := range profileHeaders(profile) {
+		req.Header.Del(name)
+	}
+	if profile.AuthType != "" {
+		req.Header.Del("Authorization")
+	}
+}
+
+// credentialsAllowed indica si las cabeceras y la autenticación del perfil pueden enviarse a host: el de la
+// fuente o uno de los CredentialHosts del perfil ("*.medio.com" admite medio.com y sus subdominios)
+func credentialsAllowed(profile *domain.HTTPProfile, sourceHost, host string) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+	if host == sourceHost {
+		return true
+	}
+	for _, allowed := range strings.Split(profile.CredentialHosts, ",") {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if domainPart, ok := strings.CutPrefix(allowed, "*."); ok {
+			if host == domainPart || strings.HasSuffix(host, "."+domainPart) {
+				return true
+			}
+		} else if allowed != "" && host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSecrets sustituye las referencias ${VARIABLE} por el valor de la variable de entorno. Solo se
+// expanden las variables de domain.IsSecretEnv; el resto de referencias se dejan tal cual.
+func expandSecrets(value string) string {
+	return domain.SecretRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := domain.SecretRefPattern.FindStringSubmatch(ref)[1]
+		if !domain.IsSecretEnv(name) {
+			return ref
+		}
+		return os.Getenv(name)
+	})
+}
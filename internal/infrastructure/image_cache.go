@@ -0,0 +1,340 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/imaging"
+	"dailynews/pkg/utils"
+)
+
+// ImageCachePublicPath es la ruta pública desde la que se sirven las imágenes cacheadas
+const ImageCachePublicPath = "/images/cache/"
+
+// defaultImageRenditions son los anchos de las versiones reducidas si no se configuran otros
+var defaultImageRenditions = []int{320, 480}
+
+// imageCache implementa domain.ImageCache: descarga cada imagen una vez, la guarda redimensionada
+// en WebP o JPEG con el hash de su contenido como nombre, genera sus versiones reducidas para srcset
+// (<hash>-320w.webp junto a la principal) y registra la URL original
+type imageCache struct {
+	downloader domain.ImageDownloader
+	repo       domain.CachedImageRepository
+	dir        string
+	format     string
+	renditions []int // Anchos de las versiones reducidas, de menor a mayor
+	output     imageOutput
+	maxAge     time.Duration
+	maxBytes   int64
+}
+
+// NewImageCache crea la caché local de imágenes en dir. format es "webp" (por defecto) o "jpeg";
+// renditions son los anchos de las versiones reducidas (vacío = defaultImageRenditions; solo se generan
+// los menores que la imagen principal) y output el filtro y la calidad con que se codifican;
+// maxAge y maxBytes son la política del GC (0 = sin límite).
+func NewImageCache(downloader domain.ImageDownloader, repo domain.CachedImageRepository, dir, format string, renditions []int, output ImageOutputPolicy, maxAge time.Duration, maxBytes int64) domain.ImageCache {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "jpeg" {
+		format = "webp"
+	}
+	if len(renditions) == 0 {
+		renditions = defaultImageRenditions
+	}
+	widths := make([]int, 0, len(renditions))
+	for _, width := range renditions {
+		if width > 0 && !containsInt(widths, width) {
+			widths = append(widths, width)
+		}
+	}
+	sort.Ints(widths)
+	return &imageCache{
+		downloader: downloader,
+		repo:       repo,
+		dir:        dir,
+		format:     format,
+		renditions: widths,
+		output:     output.normalize(),
+		maxAge:     maxAge,
+		maxBytes:   maxBytes,
+	}
+}
+
+// Store devuelve el registro de la imagen en caché, descargándola y optimizándola solo si no estaba.
+// Las imágenes cacheadas antes de configurar otros anchos reciben aquí las versiones que les falten.
+func (c *imageCache) Store(ctx context.Context, imageURL string) (*domain.CachedImage, error) {
+	urlHash := domain.HashURL(imageURL)
+	cached, err := c.repo.FindByURLHash(ctx, urlHash)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando la caché de imágenes: %w", err)
+	}
+	if cached != nil {
+		if info, err := os.Stat(c.filePath(cached.LocalURL)); err == nil {
+			if cached.Renditions != c.renditionList(cached.Width) {
+				c.storeRenditions(cached, info.Size())
+			}
+			cached.LastUsedAt = time.Now()
+			if err := c.repo.Save(ctx, cached); err != nil {
+				return nil, err
+			}
+			return cached, nil
+		}
+		// El archivo se borró a mano: se vuelve a descargar sobre el mismo registro
+	} else {
+		cached = &domain.CachedImage{URLHash: urlHash, OriginalURL: imageURL}
+	}
+
+	ext := ".webp"
+	if c.format == "jpeg" {
+		ext = ".jpg"
+	}
+	tmpDir := filepath.Join(c.dir, "tmp")
+	tmpPath, err := c.downloader.DownloadAndValidate(ctx, imageURL, filepath.Join(tmpDir, randomName()+ext))
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+
+	contentHash, size, err := hashFile(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	// Subdirectorio por los dos primeros caracteres del hash para no llenar un único directorio
+	localURL := ImageCachePublicPath + contentHash[:2] + "/" + contentHash + ext
+	finalPath := c.filePath(localURL)
+	if _, err := os.Stat(finalPath); err != nil {
+		if err := os.MkdirAll(filepath.Dir(finalPath), 0755); err != nil {
+			return nil, fmt.Errorf("error creando directorio de caché: %w", err)
+		}
+		if err := os.Rename(tmpPath, finalPath); err != nil {
+			return nil, fmt.Errorf("error guardando imagen en caché: %w", err)
+		}
+	}
+
+	width, height := imageFileSize(finalPath)
+	cached.ContentHash = contentHash
+	cached.LocalURL = localURL
+	cached.Format = c.format
+	cached.Width = width
+	cached.Height = height
+	cached.LastUsedAt = time.Now()
+	c.storeRenditions(cached, size)
+	if err := c.repo.Save(ctx, cached); err != nil {
+		return nil, fmt.Errorf("error registrando imagen en caché: %w", err)
+	}
+	return cached, nil
+}
+
+// renditionList devuelve los anchos configurados menores que width, como se guardan en CachedImage.Renditions
+func (c *imageCache) renditionList(width int) string {
+	var fields []string
+	for _, w := range c.renditions {
+		if w < width {
+			fields = append(fields, strconv.Itoa(w))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// storeRenditions genera las versiones reducidas que falten en disco a partir de la principal y actualiza
+// Renditions y Bytes del registro. Un fallo solo deja sin esa versión: la principal sigue sirviendo.
+func (c *imageCache) storeRenditions(cached *domain.CachedImage, mainSize int64) {
+	cached.Bytes = mainSize
+	var done []string
+	var src image.Image
+	for _, width := range c.renditions {
+		if width >= cached.Width || cached.Height <= 0 {
+			break
+		}
+		renditionPath := c.filePath(cached.RenditionURL(width))
+		// Otras URLs con el mismo contenido comparten archivos: la versión puede existir ya
+		if info, err := os.Stat(renditionPath); err == nil {
+			cached.Bytes += info.Size()
+			done = append(done, strconv.Itoa(width))
+			continue
+		}
+		if src == nil {
+			img, err := decodeImageFile(c.filePath(cached.LocalURL))
+			if err != nil {
+				utils.AppWarn("IMAGE_CACHE", "No se pudo leer la imagen para generar sus versiones", map[string]interface{}{
+					"file":  cached.LocalURL,
+					"error": err.Error(),
+				})
+				break
+			}
+			src = img
+		}
+		height := max(1, int(math.Round(float64(width)*float64(cached.Height)/float64(cached.Width))))
+		size, err := c.writeRendition(renditionPath, imaging.Resize(src, width, height, c.output.filter))
+		if err != nil {
+			utils.AppWarn("IMAGE_CACHE", "No se pudo generar la versión reducida de la imagen", map[string]interface{}{
+				"file":  cached.LocalURL,
+				"width": width,
+				"error": err.Error(),
+			})
+			continue
+		}
+		cached.Bytes += size
+		done = append(done, strconv.Itoa(width))
+	}
+	cached.Renditions = strings.Join(done, ",")
+}
+
+// writeRendition codifica la versión en el formato de la caché pasando por un temporal,
+// para que nunca se sirva un archivo a medio escribir
+func (c *imageCache) writeRendition(filePath string, img image.Image) (int64, error) {
+	tmpPath := filePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.output.encode(f, img, c.format == "jpeg"); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// CollectGarbage borra las imágenes que no usa ninguna noticia: primero las no usadas en maxAge
+// y después, de la menos a la más reciente, hasta que la caché quede por debajo de maxBytes
+func (c *imageCache) CollectGarbage(ctx context.Context) (int, error) {
+	images, err := c.repo.ListUnreferenced(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error listando imágenes cacheadas: %w", err)
+	}
+	total, err := c.repo.TotalBytes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error calculando el tamaño de la caché: %w", err)
+	}
+
+	removed := 0
+	for _, img := range images {
+		expired := c.maxAge > 0 && time.Since(img.LastUsedAt) > c.maxAge
+		overSize := c.maxBytes > 0 && total > c.maxBytes
+		if !expired && !overSize {
+			// La lista va de menos a más reciente: las siguientes tampoco han caducado
+			break
+		}
+
+		if err := c.repo.Delete(ctx, img.ID); err != nil {
+			return removed, err
+		}
+		removed++
+		// El archivo solo se borra cuando ya no lo comparte ninguna otra URL
+		shared, err := c.repo.CountByContentHash(ctx, img.ContentHash)
+		if err != nil {
+			return removed, err
+		}
+		if shared == 0 {
+			files := []string{img.LocalURL}
+			for _, width := range img.RenditionWidths() {
+				files = append(files, img.RenditionURL(width))
+			}
+			for _, file := range files {
+				if err := os.Remove(c.filePath(file)); err != nil && !os.IsNotExist(err) {
+					utils.AppWarn("IMAGE_CACHE", "No se pudo borrar la imagen cacheada", map[string]interface{}{
+						"file":  file,
+						"error": err.Error(),
+					})
+				}
+			}
+			total -= img.Bytes
+		}
+	}
+
+	utils.AppInfo("IMAGE_CACHE", "Limpieza de la caché de imágenes completada", map[string]interface{}{
+		"removed":     removed,
+		"total_bytes": total,
+	})
+	return removed, nil
+}
+
+// filePath convierte una URL local (/images/cache/ab/abcd.webp) en su ruta en disco
+func (c *imageCache) filePath(localURL string) string {
+	rel := strings.TrimPrefix(path.Clean(localURL), ImageCachePublicPath)
+	return filepath.Join(c.dir, filepath.FromSlash(rel))
+}
+
+// hashFile devuelve el SHA-256 y el tamaño de un archivo
+func hashFile(filePath string) (string, int64, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// imageFileSize lee las dimensiones de un archivo de imagen (0 si no se pueden leer)
+func imageFileSize(filePath string) (int, int) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
+// decodeImageFile decodifica un archivo de imagen de la caché (WebP o JPEG)
+func decodeImageFile(filePath string) (image.Image, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// containsInt indica si values contiene value
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// randomName genera un nombre aleatorio para archivos temporales
+func randomName() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
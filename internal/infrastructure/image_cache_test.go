@@ -0,0 +1,315 @@
+package infrastructure
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"dailynews/internal/domain"
+)
+
+// memoryCachedImageRepo implementa domain.CachedImageRepository en memoria;
+// used son las URLs locales que usa alguna noticia
+type memoryCachedImageRepo struct {
+	images []*domain.CachedImage
+	used   map[string]bool
+	nextID uint
+}
+
+func (r *memoryCachedImageRepo) FindByURLHash(ctx context.Context, urlHash string) (*domain.CachedImage, error) {
+	for _, img := range r.images {
+		if img.URLHash == urlHash {
+			copied := *img
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryCachedImageRepo) Save(ctx context.Context, image *domain.CachedImage) error {
+	if image.ID == 0 {
+		r.nextID++
+		image.ID = r.nextID
+	}
+	copied := *image
+	for i, img := range r.images {
+		if img.ID == image.ID {
+			r.images[i] = &copied
+			return nil
+		}
+	}
+	r.images = append(r.images, &copied)
+	return nil
+}
+
+func (r *memoryCachedImageRepo) Delete(ctx context.Context, id uint) error {
+	for i, img := range r.images {
+		if img.ID == id {
+			r.images = append(r.images[:i], r.images[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *memoryCachedImageRepo) CountByContentHash(ctx context.Context, contentHash string) (int64, error) {
+	var count int64
+	for _, img := range r.images {
+		if img.ContentHash == contentHash {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryCachedImageRepo) ListUnreferenced(ctx context.Context) ([]domain.CachedImage, error) {
+	var images []domain.CachedImage
+	for _, img := range r.images {
+		if !r.used[img.LocalURL] {
+			images = append(images, *img)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].LastUsedAt.Before(images[j].LastUsedAt) })
+	return images, nil
+}
+
+func (r *memoryCachedImageRepo) TotalBytes(ctx context.Context) (int64, error) {
+	files := map[string]int64{}
+	for _, img := range r.images {
+		files[img.ContentHash] = max(files[img.ContentHash], img.Bytes)
+	}
+	var total int64
+	for _, bytes := range files {
+		total += bytes
+	}
+	return total, nil
+}
+
+// jpegImageDownloader "descarga" una imagen JPEG de color liso: URLs con el mismo color dan el mismo contenido
+type jpegImageDownloader struct {
+	width, height int
+	colors        map[string]color.Color
+	downloads     int
+}
+
+func (d *jpegImageDownloader) DownloadAndValidate(ctx context.Context, url, savePath string) (string, error) {
+	d.downloads++
+	if err := os.MkdirAll(filepath.Dir(savePath), 0755); err != nil {
+		return "", err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+	for y := 0; y < d.height; y++ {
+		for x := 0; x < d.width; x++ {
+			img.Set(x, y, d.colors[url])
+		}
+	}
+	f, err := os.Create(savePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return savePath, jpeg.Encode(f, img, nil)
+}
+
+func (d *jpegImageDownloader) ValidateImage(ctx context.Context, url string) (bool, error) {
+	return true, nil
+}
+
+func (d *jpegImageDownloader) InspectImage(ctx context.Context, url string) (*domain.ImageValidation, error) {
+	return &domain.ImageValidation{}, nil
+}
+
+func TestImageCacheStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	downloader := &jpegImageDownloader{width: 400, height: 225, colors: map[string]color.Color{
+		"https://a.com/1.jpg": color.RGBA{200, 0, 0, 255},
+		"https://b.com/1.jpg": color.RGBA{200, 0, 0, 255},
+	}}
+	repo := &memoryCachedImageRepo{}
+	cache := NewImageCache(downloader, repo, dir, "jpeg", []int{480, 320, 320, 0}, ImageOutputPolicy{}, 0, 0)
+
+	first, err := cache.Store(ctx, "https://a.com/1.jpg")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if first.Width != 400 || first.Height != 225 || first.Format != "jpeg" {
+		t.Errorf("imagen = %dx%d %s", first.Width, first.Height, first.Format)
+	}
+	// Solo las versiones más estrechas que la principal
+	if first.Renditions != "320" {
+		t.Errorf("Renditions = %q, want %q", first.Renditions, "320")
+	}
+	want := ImageCachePublicPath + first.ContentHash[:2] + "/" + first.ContentHash + ".jpg"
+	if first.LocalURL != want {
+		t.Errorf("LocalURL = %q, want %q", first.LocalURL, want)
+	}
+	var files int64
+	for _, localURL := range []string{first.LocalURL, first.RenditionURL(320)} {
+		info, err := os.Stat(filepath.Join(dir, first.ContentHash[:2], filepath.Base(localURL)))
+		if err != nil {
+			t.Fatalf("falta %s: %v", localURL, err)
+		}
+		files += info.Size()
+	}
+	if first.Bytes != files {
+		t.Errorf("Bytes = %d, want %d", first.Bytes, files)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, "tmp")); len(entries) != 0 {
+		t.Errorf("quedan %d temporales", len(entries))
+	}
+
+	// La misma URL se sirve desde la caché sin descargarla otra vez
+	again, err := cache.Store(ctx, "https://a.com/1.jpg")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if downloader.downloads != 1 || again.ID != first.ID {
+		t.Errorf("descargas = %d, ID = %d; want 1, %d", downloader.downloads, again.ID, first.ID)
+	}
+
+	// Otra URL con el mismo contenido comparte archivo
+	shared, err := cache.Store(ctx, "https://b.com/1.jpg")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if shared.ID == first.ID || shared.LocalURL != first.LocalURL || shared.Bytes != first.Bytes {
+		t.Errorf("imagen compartida = %+v", shared)
+	}
+
+	// Si el archivo se borra a mano se vuelve a descargar sobre el mismo registro
+	os.Remove(filepath.Join(dir, first.ContentHash[:2], filepath.Base(first.LocalURL)))
+	restored, err := cache.Store(ctx, "https://a.com/1.jpg")
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if downloader.downloads != 3 || restored.ID != first.ID || len(repo.images) != 2 {
+		t.Errorf("descargas = %d, ID = %d, registros = %d", downloader.downloads, restored.ID, len(repo.images))
+	}
+}
+
+func TestImageCacheRenditionList(t *testing.T) {
+	cache := NewImageCache(nil, nil, "", "", []int{640, 320, 480}, ImageOutputPolicy{}, 0, 0).(*imageCache)
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, ""},
+		{320, ""},
+		{321, "320"},
+		{600, "320,480"},
+		{1200, "320,480,640"},
+	}
+	for _, tt := range tests {
+		if got := cache.renditionList(tt.width); got != tt.want {
+			t.Errorf("renditionList(%d) = %q, want %q", tt.width, got, tt.want)
+		}
+	}
+	if cache.format != "webp" {
+		t.Errorf("format = %q, want webp", cache.format)
+	}
+}
+
+func TestImageCacheFilePath(t *testing.T) {
+	cache := NewImageCache(nil, nil, "/var/cache", "webp", nil, ImageOutputPolicy{}, 0, 0).(*imageCache)
+	tests := []struct {
+		localURL string
+		want     string
+	}{
+		{"/images/cache/ab/abcd.webp", filepath.Join("/var/cache", "ab", "abcd.webp")},
+		{"/images/cache/ab/abcd-320w.webp", filepath.Join("/var/cache", "ab", "abcd-320w.webp")},
+		{"/images/cache/ab/../../../etc/passwd", filepath.Join("/var/cache", "/etc/passwd")},
+	}
+	for _, tt := range tests {
+		if got := cache.filePath(tt.localURL); got != tt.want {
+			t.Errorf("filePath(%q) = %q, want %q", tt.localURL, got, tt.want)
+		}
+	}
+}
+
+func TestImageCacheCollectGarbage(t *testing.T) {
+	now := time.Now()
+	// Cada registro ocupa 100 bytes; "compartida" comparte contenido con "reciente"
+	seed := []domain.CachedImage{
+		{URLHash: "vieja", ContentHash: "c1", LocalURL: "/images/cache/c1/c1.webp", Renditions: "320", Bytes: 100, LastUsedAt: now.Add(-72 * time.Hour)},
+		{URLHash: "compartida", ContentHash: "c2", LocalURL: "/images/cache/c2/c2.webp", Bytes: 100, LastUsedAt: now.Add(-48 * time.Hour)},
+		{URLHash: "usada", ContentHash: "c3", LocalURL: "/images/cache/c3/c3.webp", Bytes: 100, LastUsedAt: now.Add(-96 * time.Hour)},
+		{URLHash: "media", ContentHash: "c4", LocalURL: "/images/cache/c4/c4.webp", Bytes: 100, LastUsedAt: now.Add(-2 * time.Hour)},
+		{URLHash: "reciente", ContentHash: "c2", LocalURL: "/images/cache/c2/c2.webp", Bytes: 100, LastUsedAt: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name       string
+		maxAge     time.Duration
+		maxBytes   int64
+		wantKept   []string
+		wantFiles  []string // archivos que siguen en disco
+		wantRemove int
+	}{
+		{"sin límites", 0, 0, []string{"vieja", "compartida", "usada", "media", "reciente"}, []string{"c1", "c2", "c3", "c4"}, 0},
+		{"caducadas", 24 * time.Hour, 0, []string{"usada", "media", "reciente"}, []string{"c2", "c3", "c4"}, 2},
+		// 400 bytes de contenido distinto: borrar "vieja" basta; "compartida" no libera su archivo
+		{"por tamaño", 0, 300, []string{"compartida", "usada", "media", "reciente"}, []string{"c2", "c3", "c4"}, 1},
+		{"por tamaño sin liberar espacio", 0, 250, []string{"usada", "reciente"}, []string{"c2", "c3"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			repo := &memoryCachedImageRepo{used: map[string]bool{"/images/cache/c3/c3.webp": true}}
+			for _, img := range seed {
+				img := img
+				repo.Save(context.Background(), &img)
+				os.MkdirAll(filepath.Join(dir, img.ContentHash), 0755)
+				os.WriteFile(filepath.Join(dir, img.ContentHash, img.ContentHash+".webp"), []byte("x"), 0644)
+				if img.Renditions != "" {
+					os.WriteFile(filepath.Join(dir, img.ContentHash, img.ContentHash+"-320w.webp"), []byte("x"), 0644)
+				}
+			}
+			cache := NewImageCache(nil, repo, dir, "webp", nil, ImageOutputPolicy{}, tt.maxAge, tt.maxBytes)
+
+			removed, err := cache.CollectGarbage(context.Background())
+			if err != nil {
+				t.Fatalf("CollectGarbage: %v", err)
+			}
+			if removed != tt.wantRemove {
+				t.Errorf("removed = %d, want %d", removed, tt.wantRemove)
+			}
+			var kept []string
+			for _, img := range repo.images {
+				kept = append(kept, img.URLHash)
+			}
+			if !reflect.DeepEqual(kept, tt.wantKept) {
+				t.Errorf("registros = %v, want %v", kept, tt.wantKept)
+			}
+			var files []string
+			for _, hash := range []string{"c1", "c2", "c3", "c4"} {
+				if _, err := os.Stat(filepath.Join(dir, hash, hash+".webp")); err == nil {
+					files = append(files, hash)
+				}
+			}
+			if !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("archivos = %v, want %v", files, tt.wantFiles)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "c1", "c1-320w.webp")); (err == nil) != containsString(tt.wantFiles, "c1") {
+				t.Errorf("la versión reducida de c1 no sigue a la principal")
+			}
+		})
+	}
+}
+
+// containsString indica si values contiene value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// imageTarget es el tamaño y la relación de aspecto ideales de la imagen de una noticia
+type imageTarget struct {
+	aspect float64
+	width  int
+	height int
+}
+
+// imageCandidate es una imagen posible de un item con las dimensiones que declara el feed (0 = desconocida)
+type imageCandidate struct {
+	url       string
+	width     int
+	height    int
+	preferred bool // Viene de los campos de imagen del patrón o de la fuente
+}
+
+// imageFieldNames son todos los orígenes de imagen de un item, en orden de preferencia para desempatar
+var imageFieldNames = []string{"media:content", "media:thumbnail", "enclosure", "itunes:image", "description_img"}
+
+// collectImageCandidates reúne las imágenes de todos los orígenes del item (media:content y
+// media:thumbnail, también dentro de media:group, enclosures, itunes:image y descripción).
+// Las de fields (separados por '|') se marcan como preferidas.
+func collectImageCandidates(item *gofeed.Item, fields string, targetWidth int) []imageCandidate {
+	preferred := make(map[string]bool)
+	var order []string
+	for _, f := range strings.Split(fields, "|") {
+		if f = strings.TrimSpace(f); f != "" {
+			preferred[f] = true
+			order = append(order, f)
+		}
+	}
+	for _, f := range imageFieldNames {
+		if !preferred[f] {
+			order = append(order, f)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var candidates []imageCandidate
+	for _, field := range order {
+		for _, c := range imageCandidatesFromField(item, field, targetWidth) {
+			c.url = strings.TrimSpace(c.url)
+			if c.url == "" || seen[c.url] {
+				continue
+			}
+			seen[c.url] = true
+			c.preferred = preferred[field]
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// imageCandidatesFromField devuelve las imágenes de un origen concreto del item
+func imageCandidatesFromField(item *gofeed.Item, field string, targetWidth int) []imageCandidate {
+	switch field {
+	case "media:content":
+		return mediaCandidates(item, "content")
+	case "media:thumbnail":
+		return mediaCandidates(item, "thumbnail")
+	case "enclosure":
+		var candidates []imageCandidate
+		for _, enc := range item.Enclosures {
+			if enc != nil && strings.HasPrefix(enc.Type, "image/") {
+				candidates = append(candidates, imageCandidate{url: enc.URL})
+			}
+		}
+		return candidates
+	case "itunes:image":
+		var candidates []imageCandidate
+		if item.ITunesExt != nil && item.ITunesExt.Image != "" {
+			candidates = append(candidates, imageCandidate{url: item.ITunesExt.Image})
+		}
+		if item.Image != nil && item.Image.URL != "" {
+			candidates = append(candidates, imageCandidate{url: item.Image.URL})
+		}
+		return candidates
+	case "description_img":
+		// Descripción y content:encoded; las URLs relativas se resuelven respecto al link del item
+		base, err := url.Parse(strings.TrimSpace(item.Link))
+		if err != nil || !base.IsAbs() {
+			base = nil
+		}
+		candidates := extractHTMLImages(item.Description, base, targetWidth)
+		return append(candidates, extractHTMLImages(item.Content, base, targetWidth)...)
+	}
+	return nil
+}
+
+// mediaCandidates devuelve todos los media:<kind> del item, incluidos los de media:group,
+// descartando los que declaran un medium o type que no es imagen (vídeo, audio...)
+func mediaCandidates(item *gofeed.Item, kind string) []imageCandidate {
+	media, ok := item.Extensions["media"]
+	if !ok {
+		return nil
+	}
+
+	elements := append([]ext.Extension{}, media[kind]...)
+	for _, group := range media["group"] {
+		elements = append(elements, group.Children[kind]...)
+	}
+
+	var candidates []imageCandidate
+	for _, el := range elements {
+		src := el.Attrs["url"]
+		if src == "" || !isImageMedia(el.Attrs) {
+			continue
+		}
+		width, _ := strconv.Atoi(el.Attrs["width"])
+		height, _ := strconv.Atoi(el.Attrs["height"])
+		candidates = append(candidates, imageCandidate{url: src, width: width, height: height})
+	}
+	return candidates
+}
+
+// isImageMedia indica si los atributos de un elemento media:* corresponden a una imagen.
+// Sin medium ni type se asume imagen (muchos feeds los omiten).
+func isImageMedia(attrs map[string]string) bool {
+	if medium := strings.ToLower(attrs["medium"]); medium != "" {
+		return medium == "image"
+	}
+	if mimeType := strings.ToLower(attrs["type"]); mimeType != "" {
+		return strings.HasPrefix(mimeType, "image/")
+	}
+	return true
+}
+
+// fitPenalty mide lo lejos que está una imagen del objetivo (0 = ideal). Penaliza la diferencia
+// de aspecto y, sobre todo, ser más pequeña que el objetivo; las dimensiones desconocidas son neutras.
+func (t imageTarget) fitPenalty(c imageCandidate) float64 {
+	if c.width <= 0 || c.height <= 0 {
+		return 0.5
+	}
+	aspect := float64(c.width) / float64(c.height)
+	penalty := math.Abs(aspect-t.aspect) / t.aspect
+	if c.width < t.width {
+		penalty += float64(t.width-c.width) / float64(t.width)
+	} else {
+		// Las imágenes mucho mayores también sirven: solo un ligero coste de descarga
+		penalty += float64(c.width-t.width) / float64(t.width) / 10
+	}
+	return penalty
+}
+
+// rankImageCandidates ordena las imágenes de mejor a peor ajuste: primero las preferidas y,
+// dentro de cada grupo, por fitPenalty (en empate se conserva el orden del feed)
+func rankImageCandidates(candidates []imageCandidate, target imageTarget) []string {
+	ranked := append([]imageCandidate{}, candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].preferred != ranked[j].preferred {
+			return ranked[i].preferred
+		}
+		return target.fitPenalty(ranked[i]) < target.fitPenalty(ranked[j])
+	})
+
+	urls := make([]string, len(ranked))
+	for i, c := range ranked {
+		urls[i] = c.url
+	}
+	return urls
+}
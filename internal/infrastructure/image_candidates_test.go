@@ -0,0 +1,168 @@
+package infrastructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+const imageCandidatesFeed = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel><title>Feed</title>
+<item>
+  <title>Noticia con muchas imágenes</title>
+  <link>https://example.com/noticias/1</link>
+  <description><![CDATA[<p><img src="/img/cuerpo.jpg" width="800" height="450"></p>]]></description>
+  <media:content url="https://cdn.example.com/video.mp4" medium="video"/>
+  <media:content url="https://cdn.example.com/pequena.jpg" width="320" height="180"/>
+  <media:group>
+    <media:content url="https://cdn.example.com/grande.jpg" type="image/jpeg" width="1280" height="720"/>
+    <media:content url="https://cdn.example.com/audio.mp3" type="audio/mpeg"/>
+    <media:thumbnail url="https://cdn.example.com/mini.jpg" width="120" height="68"/>
+  </media:group>
+  <enclosure url="https://cdn.example.com/adjunto.jpg" type="image/jpeg" length="1"/>
+  <enclosure url="https://cdn.example.com/podcast.mp3" type="audio/mpeg" length="1"/>
+  <enclosure url="https://cdn.example.com/pequena.jpg" type="image/jpeg" length="1"/>
+  <itunes:image href="https://cdn.example.com/itunes.jpg"/>
+</item>
+</channel></rss>`
+
+func parseTestItem(t *testing.T) *gofeed.Item {
+	t.Helper()
+	feed, err := gofeed.NewParser().Parse(strings.NewReader(imageCandidatesFeed))
+	if err != nil {
+		t.Fatalf("fixture RSS inválido: %v", err)
+	}
+	return feed.Items[0]
+}
+
+func TestCollectImageCandidates(t *testing.T) {
+	item := parseTestItem(t)
+
+	tests := []struct {
+		name   string
+		fields string
+		want   []imageCandidate
+	}{
+		{
+			"sin campos preferidos",
+			"",
+			[]imageCandidate{
+				{url: "https://cdn.example.com/pequena.jpg", width: 320, height: 180},
+				{url: "https://cdn.example.com/grande.jpg", width: 1280, height: 720},
+				{url: "https://cdn.example.com/mini.jpg", width: 120, height: 68},
+				{url: "https://cdn.example.com/adjunto.jpg"},
+				{url: "https://cdn.example.com/itunes.jpg"},
+				{url: "https://example.com/img/cuerpo.jpg", width: 800, height: 450},
+			},
+		},
+		{
+			"enclosure y descripción preferidos",
+			"enclosure|description_img",
+			[]imageCandidate{
+				{url: "https://cdn.example.com/adjunto.jpg", preferred: true},
+				{url: "https://cdn.example.com/pequena.jpg", preferred: true},
+				{url: "https://example.com/img/cuerpo.jpg", width: 800, height: 450, preferred: true},
+				{url: "https://cdn.example.com/grande.jpg", width: 1280, height: 720},
+				{url: "https://cdn.example.com/mini.jpg", width: 120, height: 68},
+				{url: "https://cdn.example.com/itunes.jpg"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectImageCandidates(item, tt.fields, 800)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collectImageCandidates:\n got  %+v\n want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsImageMedia(t *testing.T) {
+	tests := []struct {
+		attrs map[string]string
+		want  bool
+	}{
+		{map[string]string{}, true},
+		{map[string]string{"medium": "image"}, true},
+		{map[string]string{"medium": "IMAGE", "type": "video/mp4"}, true},
+		{map[string]string{"medium": "video"}, false},
+		{map[string]string{"type": "image/webp"}, true},
+		{map[string]string{"type": "audio/mpeg"}, false},
+	}
+	for _, tt := range tests {
+		if got := isImageMedia(tt.attrs); got != tt.want {
+			t.Errorf("isImageMedia(%v) = %v, want %v", tt.attrs, got, tt.want)
+		}
+	}
+}
+
+func TestImageTargetFitPenalty(t *testing.T) {
+	target := imageTarget{aspect: 16.0 / 9.0, width: 800, height: 450}
+	tests := []struct {
+		name      string
+		candidate imageCandidate
+		want      float64
+	}{
+		{"ideal", imageCandidate{width: 800, height: 450}, 0},
+		{"desconocida", imageCandidate{}, 0.5},
+		{"mitad de ancho", imageCandidate{width: 400, height: 225}, 0.5},
+		{"el doble de ancho", imageCandidate{width: 1600, height: 900}, 0.1},
+		{"cuadrada", imageCandidate{width: 800, height: 800}, 0.4375},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := target.fitPenalty(tt.candidate); got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("fitPenalty = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankImageCandidates(t *testing.T) {
+	target := imageTarget{aspect: 16.0 / 9.0, width: 800, height: 450}
+	tests := []struct {
+		name       string
+		candidates []imageCandidate
+		want       []string
+	}{
+		{
+			"mejor ajuste primero",
+			[]imageCandidate{
+				{url: "mini", width: 120, height: 68},
+				{url: "vertical", width: 800, height: 1200},
+				{url: "grande", width: 1280, height: 720},
+				{url: "ideal", width: 800, height: 450},
+			},
+			[]string{"ideal", "grande", "vertical", "mini"},
+		},
+		{
+			"las preferidas van antes aunque ajusten peor",
+			[]imageCandidate{
+				{url: "ideal", width: 800, height: 450},
+				{url: "preferida", width: 200, height: 200, preferred: true},
+			},
+			[]string{"preferida", "ideal"},
+		},
+		{
+			"en empate se conserva el orden del feed",
+			[]imageCandidate{
+				{url: "a"},
+				{url: "b"},
+				{url: "c", width: 400, height: 225},
+			},
+			[]string{"a", "b", "c"},
+		},
+		{"sin candidatos", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rankImageCandidates(tt.candidates, target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rankImageCandidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,89 @@
 package infrastructure
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"image"
+	"image/color"
 	_ "image/gif"
-	_ "image/jpeg"
-	"image/png"
-
+	"image/jpeg"
+	_ "image/png"
+	"io"
 	"log"
-	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
-	// "github.com/chai2010/webp" // Eliminado porque no se usa
+	"github.com/chai2010/webp"
 
 	"dailynews/internal/domain"
+	"dailynews/pkg/imaging"
+)
+
+// Componentes de la vista previa BlurHash: 4x3 se ajusta al aspecto 16:9 de las tarjetas
+const (
+	blurHashComponentsX = 4
+	blurHashComponentsY = 3
 )
 
+// inspectSampleSide es el lado mayor de la muestra de la que salen el hash perceptual y el BlurHash
+const inspectSampleSide = 64
+
+// defaultImageQuality es la calidad de codificación por defecto de las imágenes guardadas (WebP y JPEG)
+const defaultImageQuality = 80
+
+// ImageOutputPolicy define cómo se generan las imágenes guardadas
+type ImageOutputPolicy struct {
+	Resampling     string // "lanczos" (por defecto) o "area"
+	WebPQuality    int    // 1-100 (0 = defaultImageQuality)
+	JPEGQuality    int
+	LetterboxColor string // Color de las bandas en modo letterbox (#rrggbb)
+}
+
+// imageOutput es la ImageOutputPolicy ya interpretada
+type imageOutput struct {
+	filter      imaging.Filter
+	webpQuality int
+	jpegQuality int
+	background  color.Color
+}
+
+// normalize interpreta la política y completa los valores por defecto
+func (p ImageOutputPolicy) normalize() imageOutput {
+	quality := func(q int) int {
+		if q <= 0 || q > 100 {
+			return defaultImageQuality
+		}
+		return q
+	}
+	return imageOutput{
+		filter:      imaging.ParseFilter(p.Resampling),
+		webpQuality: quality(p.WebPQuality),
+		jpegQuality: quality(p.JPEGQuality),
+		background:  parseHexColor(p.LetterboxColor),
+	}
+}
+
+// encode codifica la imagen en JPEG o WebP con la calidad de cada formato
+func (o imageOutput) encode(w io.Writer, img image.Image, asJPEG bool) error {
+	if asJPEG {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: o.jpegQuality})
+	}
+	return webp.Encode(w, img, &webp.Options{Quality: float32(o.webpQuality)})
+}
+
+// parseHexColor interpreta un color #rrggbb (negro si no es válido)
+func parseHexColor(value string) color.Color {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.Black
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 255}
+}
+
 // imageDownloader ahora recibe los parámetros de aspecto y tolerancia
 // y el tamaño objetivo para redimensionar
 type imageDownloader struct {
@@ -29,29 +92,46 @@ type imageDownloader struct {
 	aspectTolerance float64
 	width           int
 	height          int
+	maxBytes        int64 // Tamaño máximo del archivo descargado
+	maxPixels       int64 // Máximo de píxeles antes de decodificar (protección frente a bombas de descompresión)
+	crop            ImageCropPolicy
+	output          imageOutput
 }
 
-func NewImageDownloader(targetAspect, aspectTolerance float64, width, height int) domain.ImageDownloader {
+// NewImageDownloader crea el validador y descargador de imágenes. maxBytes y maxPixels admiten 0
+// para usar los límites por defecto; crop decide si las imágenes con otro aspecto se recortan o se rechazan
+// y output el filtro de remuestreo y la calidad de cada formato.
+func NewImageDownloader(targetAspect, aspectTolerance float64, width, height int, maxBytes, maxPixels int64, crop ImageCropPolicy, output ImageOutputPolicy, transport http.RoundTripper) domain.ImageDownloader {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxImageBytes
+	}
+	if maxPixels <= 0 {
+		maxPixels = defaultMaxImagePixels
+	}
 	return &imageDownloader{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: transport,
+			Timeout:   30 * time.Second,
 		},
 		targetAspect:    targetAspect,
 		aspectTolerance: aspectTolerance,
 		width:           width,
 		height:          height,
+		maxBytes:        maxBytes,
+		maxPixels:       maxPixels,
+		crop:            crop.normalize(width, height),
+		output:          output.normalize(),
 	}
 }
 
 func (d *imageDownloader) DownloadAndValidate(ctx context.Context, imageURL, savePath string) (string, error) {
 	// 1. Descargar la imagen
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	// User-Agent de navegador para evitar ser bloqueado (o el del perfil HTTP de la fuente)
+	req, err := newSourceRequest(ctx, imageURL, browserUserAgent, "")
 	if err != nil {
-		return "", fmt.Errorf("error creando petición: %w", err)
+		return "", err
 	}
-	// Configurar headers para evitar ser bloqueado
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	resp, err := d.httpClient.Do(req)
+	resp, err := httpClientFor(ctx, d.httpClient).Do(req)
 	if err != nil {
 		return "", fmt.Errorf("error descargando imagen: %w", err)
 	}
@@ -59,134 +139,232 @@ func (d *imageDownloader) DownloadAndValidate(ctx context.Context, imageURL, sav
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
 	}
-	// 2. Validar el tipo MIME
-	contentType := resp.Header.Get("Content-Type")
-	if !isValidImageType(contentType) {
-		log.Printf("[WARN] Imagen descartada por tipo MIME no soportado: %s", contentType)
-		return "", fmt.Errorf("tipo de imagen no soportado: %s", contentType)
+	if resp.ContentLength > d.maxBytes {
+		return "", fmt.Errorf("imagen demasiado grande: %d bytes", resp.ContentLength)
 	}
-	// 3. Leer y decodificar la imagen
-	img, _, err := image.Decode(resp.Body)
+	// 2. Leer la cabecera: formato por bytes mágicos, dimensiones y límite de píxeles antes de decodificar.
+	// Los bytes leídos se guardan para decodificar después la imagen completa sin volver a pedirla.
+	body := newLimitedCountingReader(resp.Body, d.maxBytes)
+	var header bytes.Buffer
+	probe, err := probeImage(io.TeeReader(body, &header))
 	if err != nil {
-		log.Printf("[WARN] Imagen descartada por error de decodificación: %v", err)
-		return "", fmt.Errorf("error decodificando imagen: %w", err)
+		if body.n > d.maxBytes {
+			return "", fmt.Errorf("imagen demasiado grande: más de %d bytes", d.maxBytes)
+		}
+		if body.err != nil {
+			return "", fmt.Errorf("error descargando imagen: %w", body.err)
+		}
+		log.Printf("[WARN] Imagen descartada: %v", err)
+		return "", err
 	}
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-	aspectRatio := float64(width) / float64(height)
-	minAspect := d.targetAspect - (d.targetAspect * d.aspectTolerance)
-	maxAspect := d.targetAspect + (d.targetAspect * d.aspectTolerance)
-	if aspectRatio < minAspect || aspectRatio > maxAspect {
+	if probe.pixels() > d.maxPixels {
+		return "", fmt.Errorf("imagen con demasiados píxeles: %dx%d", probe.width, probe.height)
+	}
+	aspectRatio := float64(probe.width) / float64(probe.height)
+	if !d.fitsAspect(aspectRatio) && !d.crop.canCrop(probe.width, probe.height, d.targetAspect) {
 		log.Printf("[WARN] Imagen descartada por relación de aspecto: %.3f (esperado %.3f ±%.2f)", aspectRatio, d.targetAspect, d.aspectTolerance)
 		return "", fmt.Errorf("relación de aspecto no soportada: %.3f (esperado %.3f ±%.2f)", aspectRatio, d.targetAspect, d.aspectTolerance)
 	}
-	// 4. Redimensionar si es necesario
-	if width != d.width || height != d.height {
-		// Redimensionar usando image/draw (o imaging si está disponible)
-		// Aquí usamos image.NewRGBA y draw.Draw para mantener dependencias estándar
-		newImg := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
-		// Escalado simple (nearest neighbor)
-		for y := 0; y < d.height; y++ {
-			for x := 0; x < d.width; x++ {
-				srcX := x * width / d.width
-				srcY := y * height / d.height
-				newImg.Set(x, y, img.At(srcX, srcY))
-			}
+	if probe.format == "avif" {
+		// No hay decodificador AVIF: la imagen es válida pero se enlaza la original
+		return "", fmt.Errorf("formato %s no se puede recodificar", probe.format)
+	}
+	// 3. Decodificar la imagen completa
+	img, _, err := image.Decode(io.MultiReader(&header, body))
+	if body.n > d.maxBytes {
+		// Sin Content-Length el límite solo se detecta al leer: la imagen llega cortada
+		return "", fmt.Errorf("imagen demasiado grande: más de %d bytes", d.maxBytes)
+	}
+	if err != nil {
+		if body.err != nil {
+			return "", fmt.Errorf("error descargando imagen: %w", body.err)
 		}
-		img = newImg
+		log.Printf("[WARN] Imagen descartada por error de decodificación: %v", err)
+		return "", fmt.Errorf("error decodificando imagen: %w", err)
 	}
+	// Los decodificadores ignoran el EXIF: las fotos de móvil llegarían giradas
+	img = imaging.ApplyOrientation(img, probe.orientation)
+	// 4. Ajustar al tamaño objetivo: recorte o bandas y remuestreo
+	img = d.fitToTarget(img, d.fitsAspect(aspectRatio))
 	// 5. Crear directorio de destino si no existe
 	if err := os.MkdirAll(filepath.Dir(savePath), 0755); err != nil {
 		return "", fmt.Errorf("error creando directorio: %w", err)
 	}
-	savePath = strings.TrimSuffix(savePath, filepath.Ext(savePath)) + ".webp"
+	// El formato sale de la extensión: .jpg/.jpeg se guardan como JPEG y el resto como WebP
+	ext := strings.ToLower(filepath.Ext(savePath))
+	if ext != ".jpg" && ext != ".jpeg" {
+		savePath = strings.TrimSuffix(savePath, filepath.Ext(savePath)) + ".webp"
+	}
 	outputFile, err := os.Create(savePath)
 	if err != nil {
 		return "", fmt.Errorf("error creando archivo: %w", err)
 	}
 	defer outputFile.Close()
 
-	if err := png.Encode(outputFile, img); err != nil {
-		return "", fmt.Errorf("error codificando a PNG: %w", err)
+	if err := d.output.encode(outputFile, img, ext == ".jpg" || ext == ".jpeg"); err != nil {
+		os.Remove(savePath)
+		return "", fmt.Errorf("error codificando imagen: %w", err)
 	}
 	log.Printf("[INFO] Imagen procesada y guardada en: %s", savePath)
 	return savePath, nil
 }
 
-func (d *imageDownloader) ValidateImage(imageURL string) (bool, error) {
-	// 1. Descargar la imagen
-	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+// fitToTarget lleva la imagen al tamaño objetivo: las que no encajan en la tolerancia, en modo letterbox,
+// se escalan enteras con bandas; el resto se recorta (al centro o por el punto focal) y se remuestrea
+func (d *imageDownloader) fitToTarget(img image.Image, fitsAspect bool) image.Image {
+	mode := d.crop.Mode
+	if mode == ImageCropLetterbox {
+		if !fitsAspect {
+			return imaging.Letterbox(img, d.width, d.height, d.output.filter, d.output.background)
+		}
+		mode = ImageCropCenter
+	}
+	region := cropRegion(img, float64(d.width)/float64(d.height), mode)
+	return imaging.Fill(img, region, d.width, d.height, d.output.filter)
+}
+
+func (d *imageDownloader) ValidateImage(ctx context.Context, imageURL string) (bool, error) {
+	result, err := d.InspectImage(ctx, imageURL)
+	if err != nil {
+		return false, err
+	}
+	return result.Valid, nil
+}
+
+// InspectImage lee la cabecera de la imagen y devuelve el veredicto con sus datos; solo las que pasan
+// los límites de tamaño, píxeles y aspecto se decodifican enteras para calcular su hash perceptual.
+// Los rechazos definitivos (404, formato, cabecera, tamaño, píxeles, aspecto) se devuelven como resultado
+// no válido; los fallos transitorios (red, 5xx, 429, circuito abierto) como error, para no cachearlos.
+func (d *imageDownloader) InspectImage(ctx context.Context, imageURL string) (*domain.ImageValidation, error) {
+	result := &domain.ImageValidation{URL: imageURL, CheckedAt: time.Now()}
+	reject := func(reason string) (*domain.ImageValidation, error) {
+		log.Printf("[WARN] Imagen descartada por %s: %s", reason, imageURL)
+		result.Reason = reason
+		return result, nil
+	}
+
+	// 1. Descargar la imagen (con el perfil HTTP de la fuente si el contexto lo lleva)
+	req, err := newSourceRequest(ctx, imageURL, browserUserAgent, "")
 	if err != nil {
-		return false, fmt.Errorf("error creando petición: %w", err)
+		return nil, err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-	resp, err := d.httpClient.Do(req)
+	resp, err := httpClientFor(ctx, d.httpClient).Do(req)
 	if err != nil {
-		return false, fmt.Errorf("error descargando imagen: %w", err)
+		return nil, fmt.Errorf("error descargando imagen: %w", err)
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	default:
+		return reject(fmt.Sprintf("código de estado %d", resp.StatusCode))
 	}
-	contentType := resp.Header.Get("Content-Type")
-	if !isValidImageType(contentType) {
-		log.Printf("[WARN] Imagen descartada por tipo MIME no soportado: %s", contentType)
-		return false, fmt.Errorf("tipo de imagen no soportado: %s", contentType)
+
+	if resp.ContentLength > d.maxBytes {
+		result.Bytes = resp.ContentLength
+		return reject(fmt.Sprintf("archivo demasiado grande (%d bytes)", resp.ContentLength))
 	}
 
-	// 3. Leer y decodificar la imagen
-	img, _, err := image.Decode(resp.Body)
+	// 2. Leer solo la cabecera: el formato sale de los bytes mágicos, no del Content-Type
+	body := newLimitedCountingReader(resp.Body, d.maxBytes)
+	// Sin Content-Length el límite solo se detecta al leer: la imagen llega cortada y no debe
+	// confundirse con una cabecera o un archivo corruptos
+	tooLarge := func() (*domain.ImageValidation, error) {
+		result.Bytes = body.n
+		return reject(fmt.Sprintf("archivo demasiado grande (más de %d bytes)", d.maxBytes))
+	}
+	var header bytes.Buffer
+	probe, err := probeImage(io.TeeReader(body, &header))
+	result.Bytes = resp.ContentLength
+	if result.Bytes <= 0 {
+		result.Bytes = body.n // Sin Content-Length solo se conoce lo leído
+	}
 	if err != nil {
-		log.Printf("[WARN] Imagen descartada por error de decodificación: %v", err)
-		return false, fmt.Errorf("error decodificando imagen: %w", err)
+		if body.n > d.maxBytes {
+			return tooLarge()
+		}
+		if body.err != nil {
+			return nil, fmt.Errorf("error descargando imagen: %w", body.err)
+		}
+		return reject(fmt.Sprintf("%v (Content-Type %s)", err, resp.Header.Get("Content-Type")))
 	}
+	result.ContentType = imageMIMETypes[probe.format]
+	result.Width = probe.width
+	result.Height = probe.height
 
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+	if probe.pixels() > d.maxPixels {
+		return reject(fmt.Sprintf("demasiados píxeles (%dx%d)", result.Width, result.Height))
+	}
 
 	// Validar tamaño mínimo
-	if width < 400 || height < 225 {
-		log.Printf("[WARN] Imagen descartada por tamaño insuficiente: %dx%d", width, height)
-		return false, nil
+	if result.Width < 400 || result.Height < 225 {
+		return reject(fmt.Sprintf("tamaño insuficiente (%dx%d)", result.Width, result.Height))
 	}
 
-	// Validar relación de aspecto
-	aspectRatio := float64(width) / float64(height)
-	minAspect := d.targetAspect - (d.targetAspect * d.aspectTolerance)
-	maxAspect := d.targetAspect + (d.targetAspect * d.aspectTolerance)
-	if aspectRatio < minAspect || aspectRatio > maxAspect {
-		log.Printf("[WARN] Imagen descartada por relación de aspecto: %.3f (esperado %.3f ±%.2f)", aspectRatio, d.targetAspect, d.aspectTolerance)
-		return false, nil
+	// Validar relación de aspecto: fuera de la tolerancia solo se aceptan si se pueden recortar
+	aspectRatio := float64(result.Width) / float64(result.Height)
+	if !d.fitsAspect(aspectRatio) {
+		if !d.crop.canCrop(result.Width, result.Height, d.targetAspect) {
+			return reject(fmt.Sprintf("relación de aspecto %.3f (esperado %.3f ±%.2f)", aspectRatio, d.targetAspect, d.aspectTolerance))
+		}
+		log.Printf("[DEBUG] Imagen con aspecto %.3f aceptada para recorte (%s): %s", aspectRatio, d.crop.Mode, imageURL)
+		result.NeedsCrop = true
 	}
 
-	log.Printf("[DEBUG] Imagen válida: %dx%d, aspecto: %.3f", width, height, aspectRatio)
-	return true, nil
+	// 3. Solo las imágenes que pasan los límites se decodifican enteras, para su hash perceptual
+	// y su vista previa (no hay decodificador AVIF: se aceptan sin ninguno de los dos)
+	if probe.format != "avif" {
+		img, _, err := image.Decode(io.MultiReader(&header, body))
+		if result.Bytes <= 0 || body.n > result.Bytes {
+			result.Bytes = body.n
+		}
+		if body.n > d.maxBytes {
+			return tooLarge()
+		}
+		if err != nil {
+			if body.err != nil {
+				return nil, fmt.Errorf("error descargando imagen: %w", body.err)
+			}
+			return reject(fmt.Sprintf("error de decodificación (%v)", err))
+		}
+		// Una sola muestra reducida sirve para el hash y la vista previa: la imagen completa no se copia
+		sample := imaging.ApplyOrientation(imaging.Sample(img, inspectSampleSide), probe.orientation)
+		result.PHash = dHash(sample)
+		result.BlurHash = imaging.BlurHash(sample, blurHashComponentsX, blurHashComponentsY)
+	}
+
+	log.Printf("[DEBUG] Imagen válida: %dx%d, aspecto: %.3f", result.Width, result.Height, aspectRatio)
+	result.Valid = true
+	return result, nil
 }
 
-// isValidImageType verifica si el tipo MIME es una imagen soportada
-func isValidImageType(mimeType string) bool {
-	// Obtener la extensión del tipo MIME
-	ext, err := mime.ExtensionsByType(mimeType)
-	if err != nil || len(ext) == 0 {
-		return false
-	}
+// fitsAspect indica si la relación de aspecto está dentro de targetAspect ± aspectTolerance
+func (d *imageDownloader) fitsAspect(aspectRatio float64) bool {
+	minAspect := d.targetAspect - (d.targetAspect * d.aspectTolerance)
+	maxAspect := d.targetAspect + (d.targetAspect * d.aspectTolerance)
+	return aspectRatio >= minAspect && aspectRatio <= maxAspect
+}
 
-	// Verificar si la extensión está en la lista de formatos soportados
-	supported := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".webp": true,
-		".gif":  true,
-	}
+// countingReader cuenta los bytes leídos de la respuesta y guarda el primer error de red,
+// para distinguir una descarga fallida (transitoria) de una imagen corrupta (definitiva)
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
 
-	for _, e := range ext {
-		if supported[strings.ToLower(e)] {
-			return true
-		}
-	}
+// newLimitedCountingReader limita la lectura a un byte más que maxBytes: así, si n pasa de maxBytes,
+// la respuesta superaba el límite y no era solo una imagen que ocupa justo maxBytes
+func newLimitedCountingReader(r io.Reader, maxBytes int64) *countingReader {
+	return &countingReader{r: io.LimitReader(r, maxBytes+1)}
+}
 
-	return false
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if err != nil && err != io.EOF && c.err == nil {
+		c.err = err
+	}
+	return n, err
 }
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testJPEG codifica una imagen 800x450 con ruido suficiente para que ocupe varios KB
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 800, 450))
+	for y := 0; y < 450; y++ {
+		for x := 0; x < 800; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * y), uint8(x ^ y), uint8(x + y), 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageDownloaderInspectImageSizeLimit(t *testing.T) {
+	data := testJPEG(t)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		chunked    bool
+		maxBytes   int64
+		valid      bool
+		wantReason string
+	}{
+		{"dentro del límite", data, true, int64(len(data)), true, ""},
+		{"Content-Length mayor que el límite", data, false, int64(len(data)) / 2, false, "archivo demasiado grande"},
+		{"sin Content-Length y mayor que el límite", data, true, int64(len(data)) / 2, false, "archivo demasiado grande"},
+		{"cortada dentro del límite", data[:len(data)/2], true, int64(len(data)), false, "error de decodificación"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/jpeg")
+				if !tt.chunked {
+					w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+					w.Write(tt.body)
+					return
+				}
+				// Enviar en dos trozos sin Content-Length: la respuesta va con chunked encoding
+				half := len(tt.body) / 2
+				w.Write(tt.body[:half])
+				w.(http.Flusher).Flush()
+				w.Write(tt.body[half:])
+			}))
+			defer server.Close()
+
+			d := NewImageDownloader(16.0/9.0, 0.1, 800, 450, tt.maxBytes, 0, ImageCropPolicy{}, ImageOutputPolicy{}, nil)
+			result, err := d.InspectImage(context.Background(), server.URL+"/foto.jpg")
+			if err != nil {
+				t.Fatalf("InspectImage: %v", err)
+			}
+			if result.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v (motivo %q)", result.Valid, tt.valid, result.Reason)
+			}
+			if !strings.HasPrefix(result.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want prefijo %q", result.Reason, tt.wantReason)
+			}
+			if !tt.valid && tt.wantReason == "archivo demasiado grande" && result.Bytes <= tt.maxBytes {
+				t.Errorf("Bytes = %d, want más de %d", result.Bytes, tt.maxBytes)
+			}
+		})
+	}
+}
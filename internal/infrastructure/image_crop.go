@@ -0,0 +1,166 @@
+package infrastructure
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+// Modos de recorte de las imágenes fuera de targetAspect ± aspectTolerance
+const (
+	ImageCropNone      = ""          // Se rechazan
+	ImageCropCenter    = "center"    // Recorte centrado
+	ImageCropSmart     = "smart"     // Recorte alrededor del punto focal por entropía
+	ImageCropLetterbox = "letterbox" // Se conserva la imagen entera con bandas de color
+)
+
+const (
+	minCropArea       = 0.4 // Fracción mínima de la imagen que debe conservar el recorte
+	cropSampleSide    = 256 // Lado mayor de la muestra sobre la que se calcula la entropía
+	cropBlockSide     = 8   // Lado en muestras de cada bloque de entropía
+	cropHistogramBins = 32
+	cropCenterBias    = 0.15 // Preferencia por el centro ante puntuaciones parecidas
+)
+
+// ImageCropPolicy define qué hacer con las imágenes con otra relación de aspecto: con un modo de recorte
+// la tolerancia deja de ser un rechazo y las imágenes con resolución suficiente se recortan al aspecto objetivo
+type ImageCropPolicy struct {
+	Mode            string // ImageCropNone, ImageCropCenter, ImageCropSmart o ImageCropLetterbox
+	MinSourceWidth  int    // Resolución mínima de la imagen original para recortarla (0 = tamaño objetivo)
+	MinSourceHeight int
+}
+
+// normalize valida el modo y completa la resolución mínima con el tamaño objetivo
+func (p ImageCropPolicy) normalize(width, height int) ImageCropPolicy {
+	p.Mode = strings.ToLower(strings.TrimSpace(p.Mode))
+	if p.Mode != ImageCropCenter && p.Mode != ImageCropSmart && p.Mode != ImageCropLetterbox {
+		p.Mode = ImageCropNone
+	}
+	if p.MinSourceWidth <= 0 {
+		p.MinSourceWidth = width
+	}
+	if p.MinSourceHeight <= 0 {
+		p.MinSourceHeight = height
+	}
+	return p
+}
+
+// canCrop indica si una imagen de width x height admite un recorte a aspect sin perder demasiado
+// (con bandas, sin que estas ocupen demasiado: la proporción es la misma)
+func (p ImageCropPolicy) canCrop(width, height int, aspect float64) bool {
+	if p.Mode == ImageCropNone || width < p.MinSourceWidth || height < p.MinSourceHeight {
+		return false
+	}
+	crop := cropSize(width, height, aspect)
+	return float64(crop.X*crop.Y) >= minCropArea*float64(width*height)
+}
+
+// cropSize devuelve el mayor tamaño con relación de aspecto aspect que cabe en width x height
+func cropSize(width, height int, aspect float64) image.Point {
+	cropW, cropH := width, int(math.Round(float64(width)/aspect))
+	if cropH > height {
+		cropW, cropH = int(math.Round(float64(height)*aspect)), height
+	}
+	return image.Pt(cropW, cropH)
+}
+
+// cropRegion devuelve la zona de la imagen con relación de aspecto aspect: centrada o, en modo smart,
+// desplazada por el único eje libre hasta la posición con más entropía
+func cropRegion(img image.Image, aspect float64, mode string) image.Rectangle {
+	bounds := img.Bounds()
+	size := cropSize(bounds.Dx(), bounds.Dy(), aspect)
+	offX := (bounds.Dx() - size.X) / 2
+	offY := (bounds.Dy() - size.Y) / 2
+
+	if mode == ImageCropSmart {
+		if free := bounds.Dx() - size.X; free > 0 {
+			offX = focalOffset(entropyProfile(img, true), size.X, bounds.Dx())
+		} else if free := bounds.Dy() - size.Y; free > 0 {
+			offY = focalOffset(entropyProfile(img, false), size.Y, bounds.Dy())
+		}
+	}
+	origin := bounds.Min.Add(image.Pt(offX, offY))
+	return image.Rectangle{Min: origin, Max: origin.Add(size)}
+}
+
+// entropyProfile divide una muestra reducida de la imagen en bloques, calcula la entropía de luminancia
+// de cada uno y la acumula por columnas (horizontal) o por filas
+func entropyProfile(img image.Image, horizontal bool) []float64 {
+	bounds := img.Bounds()
+	step := int(math.Ceil(float64(max(bounds.Dx(), bounds.Dy())) / cropSampleSide))
+	if step < 1 {
+		step = 1
+	}
+	cols := (bounds.Dx() + step - 1) / step
+	rows := (bounds.Dy() + step - 1) / step
+	blockCols := (cols + cropBlockSide - 1) / cropBlockSide
+	blockRows := (rows + cropBlockSide - 1) / cropBlockSide
+
+	histograms := make([][cropHistogramBins]int, blockCols*blockRows)
+	for sy := 0; sy < rows; sy++ {
+		for sx := 0; sx < cols; sx++ {
+			r, g, b, _ := img.At(bounds.Min.X+sx*step, bounds.Min.Y+sy*step).RGBA()
+			luma := (299*r + 587*g + 114*b) / 1000 // 0..65535
+			bin := int(luma) * cropHistogramBins / 65536
+			histograms[(sy/cropBlockSide)*blockCols+sx/cropBlockSide][bin]++
+		}
+	}
+
+	length := blockRows
+	if horizontal {
+		length = blockCols
+	}
+	profile := make([]float64, length)
+	for i, hist := range histograms {
+		total := 0
+		for _, n := range hist {
+			total += n
+		}
+		entropy := 0.0
+		for _, n := range hist {
+			if n > 0 {
+				p := float64(n) / float64(total)
+				entropy -= p * math.Log2(p)
+			}
+		}
+		if horizontal {
+			profile[i%blockCols] += entropy
+		} else {
+			profile[i/blockCols] += entropy
+		}
+	}
+	return profile
+}
+
+// focalOffset desliza una ventana de cropLen píxeles sobre el perfil de entropía (que cubre total píxeles)
+// y devuelve el desplazamiento con más entropía, con una ligera preferencia por el centro
+func focalOffset(profile []float64, cropLen, total int) int {
+	blocks := len(profile)
+	window := int(math.Round(float64(cropLen) / float64(total) * float64(blocks)))
+	if window < 1 || window >= blocks {
+		return (total - cropLen) / 2
+	}
+
+	// Sin entropía en ningún bloque (imagen lisa) se queda centrado
+	best, bestScore := (blocks-window)/2, 0.0
+	center := float64(blocks-window) / 2
+	sum := 0.0
+	for i := 0; i < window; i++ {
+		sum += profile[i]
+	}
+	for start := 0; start+window <= blocks; start++ {
+		if start > 0 {
+			sum += profile[start+window-1] - profile[start-1]
+		}
+		score := sum * (1 - cropCenterBias*math.Abs(float64(start)-center)/math.Max(center, 1))
+		if score > bestScore {
+			best, bestScore = start, score
+		}
+	}
+
+	offset := int(math.Round(float64(best) / float64(blocks) * float64(total)))
+	if offset > total-cropLen {
+		offset = total - cropLen
+	}
+	return offset
+}
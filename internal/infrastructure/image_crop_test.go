@@ -0,0 +1,168 @@
+package infrastructure
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// noisyImage crea una imagen gris lisa con ruido solo dentro de detail
+func noisyImage(bounds, detail image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(bounds)
+	rng := rand.New(rand.NewSource(1))
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBA{128, 128, 128, 255}
+			if image.Pt(x, y).In(detail) {
+				v := uint8(rng.Intn(256))
+				c = color.RGBA{v, 255 - v, v / 2, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestImageCropPolicyNormalize(t *testing.T) {
+	tests := []struct {
+		policy ImageCropPolicy
+		want   ImageCropPolicy
+	}{
+		{ImageCropPolicy{}, ImageCropPolicy{Mode: ImageCropNone, MinSourceWidth: 800, MinSourceHeight: 450}},
+		{ImageCropPolicy{Mode: " Smart "}, ImageCropPolicy{Mode: ImageCropSmart, MinSourceWidth: 800, MinSourceHeight: 450}},
+		{ImageCropPolicy{Mode: "letterbox", MinSourceWidth: 400}, ImageCropPolicy{Mode: ImageCropLetterbox, MinSourceWidth: 400, MinSourceHeight: 450}},
+		{ImageCropPolicy{Mode: "zoom", MinSourceHeight: 200}, ImageCropPolicy{Mode: ImageCropNone, MinSourceWidth: 800, MinSourceHeight: 200}},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.normalize(800, 450); got != tt.want {
+			t.Errorf("normalize(%+v) = %+v, want %+v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestImageCropPolicyCanCrop(t *testing.T) {
+	policy := ImageCropPolicy{Mode: ImageCropCenter}.normalize(800, 450)
+	tests := []struct {
+		name          string
+		policy        ImageCropPolicy
+		width, height int
+		want          bool
+	}{
+		{"cuadrada grande", policy, 1200, 1200, true},
+		{"4:3", policy, 1024, 768, true},
+		{"por debajo de la resolución mínima", policy, 790, 790, false},
+		{"vertical: se perdería demasiado", policy, 900, 1600, false},
+		{"panorámica: se perdería demasiado", policy, 4000, 800, false},
+		{"sin modo de recorte", ImageCropPolicy{}.normalize(800, 450), 1200, 1200, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.canCrop(tt.width, tt.height, 16.0/9.0); got != tt.want {
+				t.Errorf("canCrop(%d, %d) = %v, want %v", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCropSize(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          image.Point
+	}{
+		{1600, 900, image.Pt(1600, 900)},
+		{1200, 1200, image.Pt(1200, 675)},
+		{3000, 900, image.Pt(1600, 900)},
+		{1000, 1000, image.Pt(1000, 563)},
+	}
+	for _, tt := range tests {
+		if got := cropSize(tt.width, tt.height, 16.0/9.0); got != tt.want {
+			t.Errorf("cropSize(%d, %d) = %v, want %v", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestCropRegion(t *testing.T) {
+	wide := image.Rect(0, 0, 1600, 600)
+	tall := image.Rect(0, 0, 600, 1600)
+	shifted := image.Rect(100, 50, 1700, 650)
+
+	tests := []struct {
+		name string
+		img  image.Image
+		mode string
+		want image.Rectangle
+	}{
+		{"centrado horizontal", noisyImage(wide, image.Rect(1100, 0, 1600, 600)), ImageCropCenter, image.Rect(266, 0, 1333, 600)},
+		{"centrado vertical", noisyImage(tall, image.Rect(0, 0, 600, 400)), ImageCropCenter, image.Rect(0, 631, 600, 969)},
+		{"centrado con origen desplazado", noisyImage(shifted, image.Rectangle{}), ImageCropCenter, image.Rect(366, 50, 1433, 650)},
+		{"smart: detalle a la derecha", noisyImage(wide, image.Rect(1100, 0, 1600, 600)), ImageCropSmart, image.Rect(533, 0, 1600, 600)},
+		{"smart: detalle a la izquierda", noisyImage(wide, image.Rect(0, 0, 500, 600)), ImageCropSmart, image.Rect(0, 0, 1067, 600)},
+		{"smart: detalle arriba", noisyImage(tall, image.Rect(0, 0, 600, 400)), ImageCropSmart, image.Rect(0, 0, 600, 338)},
+		{"smart: sin detalle queda centrado", noisyImage(wide, image.Rectangle{}), ImageCropSmart, image.Rect(266, 0, 1333, 600)},
+		{"smart con origen desplazado", noisyImage(shifted, image.Rect(1200, 50, 1700, 650)), ImageCropSmart, image.Rect(633, 50, 1700, 650)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cropRegion(tt.img, 16.0/9.0, tt.mode)
+			if got.Dx() != tt.want.Dx() || got.Dy() != tt.want.Dy() {
+				t.Fatalf("cropRegion = %v, tamaño %dx%d; want %v", got, got.Dx(), got.Dy(), tt.want)
+			}
+			// En modo smart el desplazamiento sale de bloques de la muestra: se admite su resolución
+			if d := got.Min.Sub(tt.want.Min); d.X < -tt.img.Bounds().Dx()/20 || d.X > tt.img.Bounds().Dx()/20 ||
+				d.Y < -tt.img.Bounds().Dy()/20 || d.Y > tt.img.Bounds().Dy()/20 {
+				t.Errorf("cropRegion = %v, want %v", got, tt.want)
+			}
+			if !got.In(tt.img.Bounds()) {
+				t.Errorf("cropRegion %v se sale de %v", got, tt.img.Bounds())
+			}
+		})
+	}
+}
+
+func TestEntropyProfile(t *testing.T) {
+	img := noisyImage(image.Rect(0, 0, 128, 64), image.Rect(64, 0, 128, 64))
+	columns := entropyProfile(img, true)
+	if len(columns) != 16 {
+		t.Fatalf("len(columnas) = %d, want 16", len(columns))
+	}
+	for i, entropy := range columns {
+		if flat := i < 8; flat != (entropy == 0) {
+			t.Errorf("columna %d: entropía %v", i, entropy)
+		}
+	}
+
+	rows := entropyProfile(img, false)
+	if len(rows) != 8 {
+		t.Fatalf("len(filas) = %d, want 8", len(rows))
+	}
+	for i := 1; i < len(rows); i++ {
+		if diff := rows[i] - rows[0]; diff < -1 || diff > 1 {
+			t.Errorf("fila %d: entropía %v, fila 0: %v", i, rows[i], rows[0])
+		}
+	}
+}
+
+func TestFocalOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile []float64
+		cropLen int
+		total   int
+		want    int
+	}{
+		{"la ventana cubre todo: centrado", []float64{1, 5, 1, 1}, 90, 100, 5},
+		{"perfil plano: centrado", []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, 50, 100, 20},
+		{"detalle al principio", []float64{5, 5, 5, 0, 0, 0, 0, 0, 0, 0}, 50, 100, 0},
+		{"detalle al final", []float64{0, 0, 0, 0, 0, 0, 0, 0, 5, 5}, 50, 100, 50},
+		{"casi empate: gana el centro", []float64{1.05, 1, 1, 1, 1, 1, 1, 1, 1, 1}, 50, 100, 20},
+		{"no se sale de la imagen", []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 9}, 53, 100, 47},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := focalOffset(tt.profile, tt.cropLen, tt.total); got != tt.want {
+				t.Errorf("focalOffset = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"fmt"
+	"image"
+
+	"dailynews/pkg/imaging"
+)
+
+// dHash calcula el hash perceptual por diferencias: reduce la imagen a 9x8 en grises y guarda un bit
+// por cada píxel más claro que su vecino de la derecha. Resiste recompresión, reescalado y pequeños
+// cambios de color, así que el mismo logo en distintos tamaños da hashes a muy pocos bits de distancia.
+func dHash(img image.Image) string {
+	small := imaging.Resize(img, 9, 8, imaging.AreaAverage)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if luminance(small, x, y) > luminance(small, x+1, y) {
+				hash |= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%016x", hash)
+}
+
+// luminance devuelve la luminancia (0-255) de un píxel de la imagen reducida
+func luminance(img *image.RGBA, x, y int) int {
+	p := img.PixOffset(img.Rect.Min.X+x, img.Rect.Min.Y+y)
+	return (299*int(img.Pix[p]) + 587*int(img.Pix[p+1]) + 114*int(img.Pix[p+2])) / 1000
+}
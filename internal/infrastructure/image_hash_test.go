@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/imaging"
+)
+
+// logoImage dibuja un logo sencillo (fondo claro, barra y cuadrado oscuros) escalado a width x height
+func logoImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx, fy := float64(x)/float64(width), float64(y)/float64(height)
+			c := color.RGBA{235, 235, 225, 255}
+			switch {
+			case fy > 0.4 && fy < 0.6 && fx > 0.1 && fx < 0.9:
+				c = color.RGBA{20, 40, 120, 255}
+			case fx > 0.15 && fx < 0.35 && fy > 0.1 && fy < 0.35:
+				c = color.RGBA{200, 30, 30, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// horizontalGradient crea un degradado de izquierda a derecha (o al revés con reverse)
+func horizontalGradient(width, height int, reverse bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := x * 255 / (width - 1)
+			if reverse {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{uint8(v)})
+		}
+	}
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		want string
+	}{
+		{"color liso", image.NewGray(image.Rect(0, 0, 90, 80)), "0000000000000000"},
+		{"se aclara hacia la derecha", horizontalGradient(90, 80, false), "0000000000000000"},
+		{"se oscurece hacia la derecha", horizontalGradient(90, 80, true), "ffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dHash(tt.img); got != tt.want {
+				t.Errorf("dHash = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDHashSimilarImages(t *testing.T) {
+	original := dHash(logoImage(900, 600))
+	if len(original) != 16 {
+		t.Fatalf("dHash = %q, want 16 dígitos hexadecimales", original)
+	}
+
+	tests := []struct {
+		name        string
+		img         image.Image
+		maxDistance int
+		minDistance int
+	}{
+		{"el mismo logo reducido", logoImage(300, 200), 4, 0},
+		{"el mismo logo reescalado con Lanczos", imaging.Resize(logoImage(900, 600), 450, 300, imaging.Lanczos), 4, 0},
+		{"el mismo logo con otro aspecto", logoImage(640, 360), 4, 0},
+		{"un degradado", horizontalGradient(900, 600, true), 64, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance := domain.ImageHashDistance(original, dHash(tt.img))
+			if distance > tt.maxDistance || distance < tt.minDistance {
+				t.Errorf("distancia = %d, want entre %d y %d", distance, tt.minDistance, tt.maxDistance)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+
+	"dailynews/pkg/imaging"
+)
+
+// Límites por defecto de la validación de imágenes
+const (
+	defaultMaxImageBytes  = 15 * 1024 * 1024
+	defaultMaxImagePixels = 40000000
+	imageSniffBytes       = 32
+	maxAVIFMetaBytes      = 256 * 1024 // La caja meta de un AVIF normal ocupa unos cientos de bytes
+)
+
+// errInvalidImageHeader indica que los primeros bytes no son una cabecera de imagen soportada
+var errInvalidImageHeader = errors.New("cabecera de imagen no válida")
+
+// imageMIMETypes relaciona cada formato reconocido por sus bytes mágicos con su tipo MIME
+var imageMIMETypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"webp": "image/webp",
+	"avif": "image/avif",
+}
+
+// imageProbe son los datos de una imagen leídos solo de su cabecera
+type imageProbe struct {
+	format      string
+	width       int // Dimensiones ya corregidas por la orientación EXIF
+	height      int
+	orientation int // Orientación EXIF (1 = normal)
+}
+
+// pixels devuelve el número de píxeles de la imagen
+func (p imageProbe) pixels() int64 {
+	return int64(p.width) * int64(p.height)
+}
+
+// sniffImageFormat reconoce el formato por los bytes mágicos, sin fiarse del Content-Type
+func sniffImageFormat(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return "gif"
+	case len(header) >= 16 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP" && string(header[12:15]) == "VP8":
+		return "webp"
+	case len(header) >= 12 && string(header[4:8]) == "ftyp" && isAVIFBrand(header):
+		return "avif"
+	}
+	return ""
+}
+
+// isAVIFBrand comprueba la marca principal y las compatibles de la caja ftyp
+func isAVIFBrand(header []byte) bool {
+	size := int(binary.BigEndian.Uint32(header[0:4]))
+	if size > len(header) {
+		size = len(header)
+	}
+	for i := 8; i+4 <= size; i += 4 {
+		if i == 12 {
+			continue // minor_version
+		}
+		if brand := string(header[i : i+4]); brand == "avif" || brand == "avis" {
+			return true
+		}
+	}
+	return false
+}
+
+// probeImage lee solo la cabecera de la imagen y devuelve su formato y dimensiones.
+// Todos los fallos se devuelven como errInvalidImageHeader: quien llama distingue los errores
+// de red mirando su propio lector (countingReader.err).
+func probeImage(r io.Reader) (imageProbe, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(imageSniffBytes)
+	if err != nil && len(header) == 0 {
+		return imageProbe{}, fmt.Errorf("%w: %v", errInvalidImageHeader, err)
+	}
+	probe := imageProbe{format: sniffImageFormat(header), orientation: 1}
+
+	switch probe.format {
+	case "":
+		return probe, fmt.Errorf("%w: formato no reconocido", errInvalidImageHeader)
+	case "webp":
+		probe.width, probe.height, err = webpSize(header)
+	case "avif":
+		probe.width, probe.height, err = avifSize(br)
+	case "jpeg":
+		// El segmento EXIF va antes del SOF: lo que lee DecodeConfig basta para la orientación
+		var consumed bytes.Buffer
+		var cfg image.Config
+		cfg, _, err = image.DecodeConfig(io.TeeReader(br, &consumed))
+		probe.width, probe.height = cfg.Width, cfg.Height
+		probe.orientation = imaging.Orientation(consumed.Bytes())
+		if imaging.SwapsAxes(probe.orientation) {
+			probe.width, probe.height = probe.height, probe.width
+		}
+	default:
+		var cfg image.Config
+		cfg, _, err = image.DecodeConfig(br)
+		probe.width, probe.height = cfg.Width, cfg.Height
+	}
+	if err != nil {
+		if !errors.Is(err, errInvalidImageHeader) {
+			err = fmt.Errorf("%w: %v", errInvalidImageHeader, err)
+		}
+		return probe, err
+	}
+	if probe.width <= 0 || probe.height <= 0 {
+		return probe, fmt.Errorf("%w: dimensiones %dx%d", errInvalidImageHeader, probe.width, probe.height)
+	}
+	return probe, nil
+}
+
+// webpSize lee las dimensiones del primer chunk de un WebP: VP8 (con pérdida), VP8L (sin pérdida) o VP8X (extendido)
+func webpSize(header []byte) (int, int, error) {
+	if len(header) < 30 {
+		return 0, 0, fmt.Errorf("%w: WebP truncado", errInvalidImageHeader)
+	}
+	chunk := header[20:]
+	switch string(header[12:16]) {
+	case "VP8 ":
+		// Fotograma clave: 3 bytes de etiqueta, código de inicio 9d 01 2a y dimensiones de 14 bits
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, fmt.Errorf("%w: WebP VP8 sin fotograma clave", errInvalidImageHeader)
+		}
+		return int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff), int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff), nil
+	case "VP8L":
+		if chunk[0] != 0x2f {
+			return 0, 0, fmt.Errorf("%w: firma VP8L incorrecta", errInvalidImageHeader)
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		// 4 bytes de flags y reservados y el lienzo en 24 bits (ancho-1, alto-1)
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1, nil
+	}
+	return 0, 0, fmt.Errorf("%w: chunk WebP desconocido", errInvalidImageHeader)
+}
+
+// avifSize recorre las cajas ISOBMFF de primer nivel hasta meta y devuelve el mayor ispe
+// (las miniaturas y el canal alfa tienen su propio ispe, más pequeño o igual)
+func avifSize(r io.Reader) (int, int, error) {
+	for {
+		var head [8]byte
+		if _, err := io.ReadFull(r, head[:]); err != nil {
+			return 0, 0, err
+		}
+		size := int64(binary.BigEndian.Uint32(head[0:4]))
+		headerLen := int64(8)
+		if size == 1 {
+			var large [8]byte
+			if _, err := io.ReadFull(r, large[:]); err != nil {
+				return 0, 0, err
+			}
+			size = int64(binary.BigEndian.Uint64(large[:]))
+			headerLen = 16
+		}
+		if size < headerLen {
+			// size 0 (hasta el final) o corrupto: sin meta no hay dimensiones
+			return 0, 0, fmt.Errorf("%w: caja AVIF inválida", errInvalidImageHeader)
+		}
+
+		boxType := string(head[4:8])
+		switch boxType {
+		case "meta":
+			if size-headerLen > maxAVIFMetaBytes {
+				return 0, 0, fmt.Errorf("%w: caja meta AVIF demasiado grande", errInvalidImageHeader)
+			}
+			data := make([]byte, size-headerLen)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return 0, 0, err
+			}
+			if len(data) < 4 {
+				return 0, 0, fmt.Errorf("%w: caja meta AVIF vacía", errInvalidImageHeader)
+			}
+			width, height := largestISPE(data[4:]) // meta es una FullBox: versión y flags
+			if width == 0 {
+				return 0, 0, fmt.Errorf("%w: AVIF sin ispe", errInvalidImageHeader)
+			}
+			return width, height, nil
+		case "mdat":
+			return 0, 0, fmt.Errorf("%w: AVIF con mdat antes de meta", errInvalidImageHeader)
+		}
+		if _, err := io.CopyN(io.Discard, r, size-headerLen); err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+// largestISPE busca las cajas ispe dentro de iprp/ipco y devuelve la de mayor área
+func largestISPE(data []byte) (int, int) {
+	bestW, bestH := 0, 0
+	for len(data) >= 8 {
+		size := int(binary.BigEndian.Uint32(data[0:4]))
+		if size < 8 || size > len(data) {
+			break
+		}
+		body := data[8:size]
+		switch string(data[4:8]) {
+		case "iprp", "ipco":
+			if w, h := largestISPE(body); w*h > bestW*bestH {
+				bestW, bestH = w, h
+			}
+		case "ispe":
+			if len(body) >= 12 {
+				w := int(binary.BigEndian.Uint32(body[4:8]))
+				h := int(binary.BigEndian.Uint32(body[8:12]))
+				if w*h > bestW*bestH {
+					bestW, bestH = w, h
+				}
+			}
+		}
+		data = data[size:]
+	}
+	return bestW, bestH
+}
@@ -0,0 +1,231 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/chai2010/webp"
+)
+
+// webpHeader arma la cabecera RIFF de un WebP con el primer chunk indicado
+func webpHeader(chunk string, data []byte) []byte {
+	header := []byte("RIFF\x00\x00\x00\x00WEBP" + chunk + "\x00\x00\x00\x00")
+	return append(header, data...)
+}
+
+// isoBox arma una caja ISOBMFF
+func isoBox(boxType string, body ...[]byte) []byte {
+	payload := bytes.Join(body, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(payload)))
+	return append(append(box, boxType...), payload...)
+}
+
+// ispeBox arma una caja ispe (FullBox) con las dimensiones indicadas
+func ispeBox(width, height uint32) []byte {
+	body := make([]byte, 12)
+	binary.BigEndian.PutUint32(body[4:8], width)
+	binary.BigEndian.PutUint32(body[8:12], height)
+	return isoBox("ispe", body)
+}
+
+// avifFixture arma un AVIF mínimo: ftyp, meta con una imagen y su miniatura, y mdat
+func avifFixture(brand string) []byte {
+	ftyp := isoBox("ftyp", []byte(brand+"\x00\x00\x00\x00mif1"+brand))
+	meta := isoBox("meta", make([]byte, 4), isoBox("hdlr", make([]byte, 24)),
+		isoBox("iprp", isoBox("ipco", ispeBox(160, 90), ispeBox(1920, 1080))))
+	return bytes.Join([][]byte{ftyp, meta, isoBox("mdat", make([]byte, 16))}, nil)
+}
+
+// exifJPEG codifica img como JPEG con un segmento EXIF que solo lleva la orientación
+func exifJPEG(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00\x12\x01\x03\x00\x01\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segment := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(app1)+2))
+	segment = append(segment, app1...)
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, segment...), data[2:]...)
+}
+
+func TestSniffImageFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0}, "jpeg"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00"), "png"},
+		{"gif87a", []byte("GIF87a"), "gif"},
+		{"gif89a", []byte("GIF89a"), "gif"},
+		{"webp", webpHeader("VP8L", nil), "webp"},
+		{"riff que no es webp", []byte("RIFF\x00\x00\x00\x00WAVEfmt "), ""},
+		{"avif", avifFixture("avif")[:32], "avif"},
+		{"avif secuencia", avifFixture("avis")[:32], "avif"},
+		{"heic no es avif", isoBox("ftyp", []byte("heic\x00\x00\x00\x00mif1heic")), ""},
+		{"html", []byte("<!doctype html><html>"), ""},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg">`), ""},
+		{"vacío", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffImageFormat(tt.header); got != tt.want {
+				t.Errorf("sniffImageFormat = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebpSize(t *testing.T) {
+	vp8 := make([]byte, 10)
+	copy(vp8[3:6], []byte{0x9d, 0x01, 0x2a})
+	binary.LittleEndian.PutUint16(vp8[6:8], 1280|0xc000) // Los 2 bits altos son la escala
+	binary.LittleEndian.PutUint16(vp8[8:10], 720)
+
+	vp8l := make([]byte, 10)
+	vp8l[0] = 0x2f
+	binary.LittleEndian.PutUint32(vp8l[1:5], (800-1)|(450-1)<<14)
+
+	vp8x := make([]byte, 10)
+	vp8x[4], vp8x[5], vp8x[6] = 0x3f, 0x42, 0x0f // 1000000 - 1
+	vp8x[7], vp8x[8] = 0x1f, 0x03                // 800 - 1
+
+	badVP8 := append([]byte(nil), vp8...)
+	badVP8[3] = 0
+	badVP8L := append([]byte(nil), vp8l...)
+	badVP8L[0] = 0
+
+	tests := []struct {
+		name          string
+		header        []byte
+		width, height int
+		wantErr       bool
+	}{
+		{"VP8 con pérdida", webpHeader("VP8 ", vp8), 1280, 720, false},
+		{"VP8L sin pérdida", webpHeader("VP8L", vp8l), 800, 450, false},
+		{"VP8X extendido", webpHeader("VP8X", vp8x), 1000000, 800, false},
+		{"VP8 sin fotograma clave", webpHeader("VP8 ", badVP8), 0, 0, true},
+		{"firma VP8L incorrecta", webpHeader("VP8L", badVP8L), 0, 0, true},
+		{"chunk desconocido", webpHeader("ALPH", vp8), 0, 0, true},
+		{"truncado", webpHeader("VP8 ", vp8[:4]), 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := webpSize(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("webpSize err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errInvalidImageHeader) {
+				t.Errorf("el error no es errInvalidImageHeader: %v", err)
+			}
+			if width != tt.width || height != tt.height {
+				t.Errorf("webpSize = %dx%d, want %dx%d", width, height, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestAvifSize(t *testing.T) {
+	ftyp := isoBox("ftyp", []byte("avif\x00\x00\x00\x00"))
+	largeFree := append(binary.BigEndian.AppendUint32(nil, 1), "free"...)
+	largeFree = binary.BigEndian.AppendUint64(largeFree, 24)
+	largeFree = append(largeFree, make([]byte, 8)...)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		width, height int
+		wantErr       bool
+	}{
+		{"mayor ispe", avifFixture("avif"), 1920, 1080, false},
+		{
+			"caja de tamaño extendido antes de meta",
+			bytes.Join([][]byte{ftyp, largeFree, isoBox("meta", make([]byte, 4), isoBox("iprp", isoBox("ipco", ispeBox(640, 360))))}, nil),
+			640, 360, false,
+		},
+		{"mdat antes de meta", bytes.Join([][]byte{ftyp, isoBox("mdat", make([]byte, 8))}, nil), 0, 0, true},
+		{"meta sin ispe", bytes.Join([][]byte{ftyp, isoBox("meta", make([]byte, 4), isoBox("hdlr", nil))}, nil), 0, 0, true},
+		{"meta vacía", bytes.Join([][]byte{ftyp, isoBox("meta")}, nil), 0, 0, true},
+		{"caja de tamaño 0", append(ftyp, 0, 0, 0, 0, 'm', 'e', 't', 'a'), 0, 0, true},
+		{"truncado", ftyp, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := avifSize(bytes.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("avifSize err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if width != tt.width || height != tt.height {
+				t.Errorf("avifSize = %dx%d, want %dx%d", width, height, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestAvifSizeLimitsMetaBox(t *testing.T) {
+	meta := binary.BigEndian.AppendUint32(nil, maxAVIFMetaBytes+16)
+	meta = append(meta, "meta"...)
+	data := append(isoBox("ftyp", []byte("avif\x00\x00\x00\x00")), meta...)
+	if _, _, err := avifSize(bytes.NewReader(data)); !errors.Is(err, errInvalidImageHeader) {
+		t.Errorf("avifSize err = %v, want errInvalidImageHeader", err)
+	}
+}
+
+func TestProbeImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 36))
+	encode := func(fn func(*bytes.Buffer) error) []byte {
+		var buf bytes.Buffer
+		if err := fn(&buf); err != nil {
+			t.Fatalf("codificando fixture: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		format      string
+		width       int
+		height      int
+		orientation int
+		wantErr     bool
+	}{
+		{"jpeg", encode(func(b *bytes.Buffer) error { return jpeg.Encode(b, img, nil) }), "jpeg", 64, 36, 1, false},
+		{"jpeg girado 90°", exifJPEG(t, img, 6), "jpeg", 36, 64, 6, false},
+		{"jpeg volteado", exifJPEG(t, img, 2), "jpeg", 64, 36, 2, false},
+		{"png", encode(func(b *bytes.Buffer) error { return png.Encode(b, img) }), "png", 64, 36, 1, false},
+		{"gif", encode(func(b *bytes.Buffer) error { return gif.Encode(b, img, nil) }), "gif", 64, 36, 1, false},
+		{"webp", encode(func(b *bytes.Buffer) error { return webp.Encode(b, img, &webp.Options{Quality: 80}) }), "webp", 64, 36, 1, false},
+		{"avif", avifFixture("avif"), "avif", 1920, 1080, 1, false},
+		{"html", []byte(strings.Repeat("<html>", 10)), "", 0, 0, 1, true},
+		{"vacío", nil, "", 0, 0, 0, true},
+		{"png truncado", encode(func(b *bytes.Buffer) error { return png.Encode(b, img) })[:12], "png", 0, 0, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe, err := probeImage(bytes.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("probeImage err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errInvalidImageHeader) {
+				t.Errorf("el error no es errInvalidImageHeader: %v", err)
+			}
+			want := imageProbe{format: tt.format, width: tt.width, height: tt.height, orientation: tt.orientation}
+			if probe != want {
+				t.Errorf("probeImage = %+v, want %+v", probe, want)
+			}
+		})
+	}
+}
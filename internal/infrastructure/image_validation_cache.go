@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// cachedImageValidator envuelve un ImageDownloader y guarda el resultado de ValidateImage por URL.
+// Mientras la entrada no caduca, la validación no hace ninguna petición.
+type cachedImageValidator struct {
+	domain.ImageDownloader
+	repo       domain.ImageValidationRepository
+	ttl        time.Duration // Vigencia de las imágenes válidas
+	failureTTL time.Duration // Vigencia de los rechazos
+}
+
+// NewCachedImageValidator añade a downloader una caché persistente de validaciones.
+// Los fallos transitorios (red, 5xx, 429, circuito abierto) no se guardan.
+func NewCachedImageValidator(downloader domain.ImageDownloader, repo domain.ImageValidationRepository, ttl, failureTTL time.Duration) domain.ImageDownloader {
+	if failureTTL <= 0 {
+		failureTTL = ttl
+	}
+	return &cachedImageValidator{
+		ImageDownloader: downloader,
+		repo:            repo,
+		ttl:             ttl,
+		failureTTL:      failureTTL,
+	}
+}
+
+// ValidateImage devuelve el veredicto guardado si sigue vigente y si no valida la imagen y lo guarda
+func (v *cachedImageValidator) ValidateImage(ctx context.Context, imageURL string) (bool, error) {
+	result, err := v.InspectImage(ctx, imageURL)
+	if err != nil {
+		return false, err
+	}
+	return result.Valid, nil
+}
+
+// InspectImage devuelve la validación guardada si sigue vigente y si no inspecciona la imagen y la guarda
+func (v *cachedImageValidator) InspectImage(ctx context.Context, imageURL string) (*domain.ImageValidation, error) {
+	urlHash := domain.HashURL(imageURL)
+	cached, err := v.repo.FindByURLHash(ctx, urlHash)
+	if err != nil {
+		utils.AppWarn("IMAGE_VALIDATION", "Error consultando la caché de validaciones", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if cached != nil {
+		ttl := v.ttl
+		if !cached.Valid {
+			ttl = v.failureTTL
+		}
+		// Las válidas guardadas antes de calcular el hash perceptual o la vista previa se vuelven a inspeccionar
+		missingHash := cached.Valid && (cached.PHash == "" || cached.BlurHash == "") && cached.ContentType != imageMIMETypes["avif"]
+		if time.Since(cached.CheckedAt) < ttl && !missingHash {
+			return cached, nil
+		}
+	}
+
+	result, err := v.ImageDownloader.InspectImage(ctx, imageURL)
+	if err != nil {
+		return nil, err
+	}
+	result.URLHash = urlHash
+	if cached != nil {
+		result.ID = cached.ID
+	}
+	if err := v.repo.Save(ctx, result); err != nil {
+		utils.AppWarn("IMAGE_VALIDATION", "Error guardando la validación de la imagen", map[string]interface{}{
+			"url":   imageURL,
+			"error": err.Error(),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dailynews/internal/domain"
+)
+
+// memoryImageValidationRepo implementa domain.ImageValidationRepository en memoria
+type memoryImageValidationRepo struct {
+	validations map[string]domain.ImageValidation
+	saves       int
+}
+
+func (r *memoryImageValidationRepo) FindByURLHash(ctx context.Context, urlHash string) (*domain.ImageValidation, error) {
+	if validation, ok := r.validations[urlHash]; ok {
+		return &validation, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryImageValidationRepo) Save(ctx context.Context, validation *domain.ImageValidation) error {
+	r.saves++
+	r.validations[validation.URLHash] = *validation
+	return nil
+}
+
+func (r *memoryImageValidationRepo) Delete(ctx context.Context, urlHash string) (int64, error) {
+	if _, ok := r.validations[urlHash]; !ok {
+		return 0, nil
+	}
+	delete(r.validations, urlHash)
+	return 1, nil
+}
+
+func (r *memoryImageValidationRepo) DeleteAll(ctx context.Context, onlyInvalid bool) (int64, error) {
+	var deleted int64
+	for hash, validation := range r.validations {
+		if !onlyInvalid || !validation.Valid {
+			delete(r.validations, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryImageValidationRepo) DeleteCheckedBefore(ctx context.Context, date time.Time) (int64, error) {
+	var deleted int64
+	for hash, validation := range r.validations {
+		if validation.CheckedAt.Before(date) {
+			delete(r.validations, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// inspectingDownloader devuelve siempre el mismo resultado de InspectImage y cuenta las inspecciones
+type inspectingDownloader struct {
+	domain.ImageDownloader
+	result      domain.ImageValidation
+	err         error
+	inspections int
+}
+
+func (d *inspectingDownloader) InspectImage(ctx context.Context, url string) (*domain.ImageValidation, error) {
+	d.inspections++
+	if d.err != nil {
+		return nil, d.err
+	}
+	result := d.result
+	result.URL = url
+	result.CheckedAt = time.Now()
+	return &result, nil
+}
+
+func TestCachedImageValidatorInspectImage(t *testing.T) {
+	const imageURL = "https://example.com/foto.jpg"
+	fresh := time.Now().Add(-time.Hour)
+	valid := domain.ImageValidation{ID: 7, Valid: true, PHash: "0f0f0f0f0f0f0f0f", BlurHash: "LKO2?U%2Tw=w"}
+	rejected := domain.ImageValidation{ID: 7, Valid: false, Reason: "demasiado pequeña"}
+
+	withCheckedAt := func(v domain.ImageValidation, at time.Time) *domain.ImageValidation {
+		v.CheckedAt = at
+		return &v
+	}
+
+	tests := []struct {
+		name            string
+		cached          *domain.ImageValidation
+		inspectErr      error
+		wantInspections int
+		wantValid       bool
+		wantSaves       int
+		wantErr         bool
+	}{
+		{"sin caché", nil, nil, 1, true, 1, false},
+		{"válida vigente", withCheckedAt(valid, fresh), nil, 0, true, 0, false},
+		{"válida caducada", withCheckedAt(valid, time.Now().Add(-48*time.Hour)), nil, 1, true, 1, false},
+		{"rechazo vigente", withCheckedAt(rejected, fresh), nil, 0, false, 0, false},
+		{"rechazo caducado con failureTTL más corto", withCheckedAt(rejected, time.Now().Add(-3*time.Hour)), nil, 1, true, 1, false},
+		{
+			"válida sin hash perceptual",
+			&domain.ImageValidation{ID: 7, Valid: true, ContentType: "image/jpeg", CheckedAt: fresh},
+			nil, 1, true, 1, false,
+		},
+		{
+			"AVIF sin hash perceptual no se reinspecciona",
+			&domain.ImageValidation{ID: 7, Valid: true, ContentType: "image/avif", CheckedAt: fresh},
+			nil, 0, true, 0, false,
+		},
+		{"fallo transitorio no se guarda", nil, errors.New("timeout"), 1, false, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryImageValidationRepo{validations: map[string]domain.ImageValidation{}}
+			if tt.cached != nil {
+				cached := *tt.cached
+				cached.URLHash = domain.HashURL(imageURL)
+				repo.validations[cached.URLHash] = cached
+			}
+			inner := &inspectingDownloader{result: domain.ImageValidation{Valid: true, PHash: valid.PHash, BlurHash: valid.BlurHash}, err: tt.inspectErr}
+			validator := NewCachedImageValidator(inner, repo, 24*time.Hour, 2*time.Hour)
+
+			result, err := validator.InspectImage(context.Background(), imageURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InspectImage err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if inner.inspections != tt.wantInspections || repo.saves != tt.wantSaves {
+				t.Errorf("inspecciones = %d, guardados = %d; want %d, %d", inner.inspections, repo.saves, tt.wantInspections, tt.wantSaves)
+			}
+			if err != nil {
+				return
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+			// Al reinspeccionar se actualiza el mismo registro
+			if tt.cached != nil && result.ID != tt.cached.ID {
+				t.Errorf("ID = %d, want %d", result.ID, tt.cached.ID)
+			}
+			if result.URLHash != domain.HashURL(imageURL) {
+				t.Errorf("URLHash = %q", result.URLHash)
+			}
+		})
+	}
+}
+
+func TestCachedImageValidatorValidateImage(t *testing.T) {
+	repo := &memoryImageValidationRepo{validations: map[string]domain.ImageValidation{}}
+	inner := &inspectingDownloader{result: domain.ImageValidation{Valid: false, Reason: "logo"}}
+	// failureTTL 0 usa el mismo TTL que las válidas
+	validator := NewCachedImageValidator(inner, repo, time.Hour, 0)
+
+	for i := 0; i < 3; i++ {
+		valid, err := validator.ValidateImage(context.Background(), "https://example.com/logo.png")
+		if err != nil || valid {
+			t.Fatalf("ValidateImage = %v, %v; want false, nil", valid, err)
+		}
+	}
+	if inner.inspections != 1 {
+		t.Errorf("inspecciones = %d, want 1", inner.inspections)
+	}
+}
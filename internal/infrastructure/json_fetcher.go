@@ -0,0 +1,340 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// maxJSONResponseSize limita el tamaño de cada respuesta JSON descargada (10MB)
+const maxJSONResponseSize = 10 * 1024 * 1024
+
+// maxJSONPages es el tope absoluto de páginas que se piden a un endpoint paginado
+const maxJSONPages = 10
+
+// jsonSourceOptions son las opciones adicionales de una fuente JSON (NewsSource.FetchOptions)
+type jsonSourceOptions struct {
+	Headers    map[string]string      `json:"headers"`
+	Pagination *jsonPaginationOptions `json:"pagination"`
+}
+
+// jsonPaginationOptions describe cómo pedir las páginas siguientes de un endpoint
+type jsonPaginationOptions struct {
+	Type     string `json:"type"`     // "page" (parámetro de página), "offset" (parámetro de desplazamiento) o "next" (URL siguiente en la respuesta)
+	Param    string `json:"param"`    // Nombre del parámetro de query para "page" y "offset"
+	Start    int    `json:"start"`    // Valor inicial del parámetro
+	Step     int    `json:"step"`     // Incremento por página (tamaño de página en "offset")
+	NextPath string `json:"nextPath"` // Ruta a la URL de la página siguiente para "next"
+	MaxPages int    `json:"maxPages"` // Máximo de páginas a pedir (incluida la primera)
+}
+
+// jsonFetcher implementa la interfaz RSSFetcher para endpoints JSON.
+// Los campos de extracción son rutas estilo JSONPath relativas a cada noticia (ej: "$.media[0].url").
+type jsonFetcher struct {
+	httpClient *http.Client
+}
+
+// NewJSONFetcher crea una nueva instancia de fetcher para fuentes de tipo "json"
+func NewJSONFetcher(transport http.RoundTripper) domain.RSSFetcher {
+	return &jsonFetcher{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+	}
+}
+
+// Fetch obtiene las noticias de un endpoint JSON sin opciones adicionales.
+// En este fetcher el parámetro filter contiene la ruta al array de noticias (ej: "$.data.articles").
+func (f *jsonFetcher) Fetch(ctx context.Context, endpoint string, filter string, titleField, imageField, linkField, dateField string) ([]domain.NewsItem, error) {
+	return f.FetchWithOptions(ctx, endpoint, filter, titleField, imageField, linkField, dateField, "")
+}
+
+// FetchWithOptions obtiene las noticias aplicando cabeceras y paginación definidas en options (JSON)
+func (f *jsonFetcher) FetchWithOptions(ctx context.Context, endpoint string, filter string, titleField, imageField, linkField, dateField, options string) ([]domain.NewsItem, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	itemsPath := strings.TrimSpace(filter)
+	if titleField == "" || linkField == "" {
+		return nil, fmt.Errorf("la fuente JSON necesita rutas de título y link")
+	}
+
+	var opts jsonSourceOptions
+	if strings.TrimSpace(options) != "" {
+		if err := json.Unmarshal([]byte(options), &opts); err != nil {
+			return nil, fmt.Errorf("opciones de fuente JSON inválidas: %w", err)
+		}
+	}
+
+	utils.AppInfo("JSON_FETCHER", "Iniciando extracción JSON", map[string]interface{}{
+		"items_path": itemsPath,
+		"url":        endpoint,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	maxPages := 1
+	if opts.Pagination != nil {
+		maxPages = opts.Pagination.MaxPages
+		if maxPages <= 0 {
+			maxPages = 3
+		}
+		if maxPages > maxJSONPages {
+			maxPages = maxJSONPages
+		}
+	}
+
+	var items []domain.NewsItem
+	total := 0
+	pageURL := endpoint
+	for page := 0; page < maxPages && pageURL != ""; page++ {
+		requestURL, err := paginatedURL(pageURL, opts.Pagination, page)
+		if err != nil {
+			return nil, err
+		}
+		baseURL, err := url.Parse(requestURL)
+		if err != nil {
+			return nil, fmt.Errorf("URL de endpoint inválida: %w", err)
+		}
+
+		doc, err := f.download(ctx, requestURL, opts.Headers)
+		if err != nil {
+			// Un fallo en páginas posteriores no invalida lo ya extraído
+			if page > 0 {
+				utils.SourceWarn(requestURL, err.Error())
+				break
+			}
+			utils.SourceError(endpoint, err.Error())
+			return nil, err
+		}
+
+		rawItems, ok := evalJSONPath(doc, itemsPath).([]interface{})
+		if !ok {
+			if page > 0 {
+				break
+			}
+			return nil, fmt.Errorf("la ruta de items '%s' no apunta a un array", itemsPath)
+		}
+		if len(rawItems) == 0 {
+			break
+		}
+		total += len(rawItems)
+
+		for i, raw := range rawItems {
+			newsNum := total - len(rawItems) + i + 1
+
+			title := strings.Join(strings.Fields(jsonField(raw, titleField)), " ")
+			if title == "" {
+				utils.NewsWarn("", "", fmt.Sprintf("Noticia %d", newsNum), fmt.Sprintf("título fallido (%s) → noticia descartada", titleField))
+				continue
+			}
+
+			linkURL := resolveURL(baseURL, jsonField(raw, linkField))
+			if linkURL == "" {
+				utils.NewsWarn("", "", fmt.Sprintf("Noticia %d", newsNum), fmt.Sprintf("link fallido (%s) → noticia descartada", linkField))
+				continue
+			}
+
+			var imageURL string
+			if imageField != "" {
+				imageURL = resolveURL(baseURL, jsonField(raw, imageField))
+			}
+
+			pubDate := time.Now()
+			if dateField != "" {
+				if t, ok := jsonDate(raw, dateField); ok {
+					pubDate = t
+				}
+			}
+
+			items = append(items, domain.NewsItem{
+				Title:   title,
+				Link:    linkURL,
+				Image:   imageURL,
+				PubDate: pubDate,
+			})
+		}
+
+		// En la paginación "next" la siguiente URL viene en la propia respuesta
+		if opts.Pagination != nil && opts.Pagination.Type == "next" {
+			next := jsonScalar(evalJSONPath(doc, opts.Pagination.NextPath))
+			pageURL = resolveURL(baseURL, next)
+		}
+	}
+
+	utils.SourceProcessingComplete(endpoint, len(items), total)
+	return items, nil
+}
+
+// download hace la petición al endpoint y decodifica el JSON conservando los números
+func (f *jsonFetcher) download(ctx context.Context, endpoint string, headers map[string]string) (interface{}, error) {
+	req, err := newSourceRequest(ctx, endpoint, browserUserAgent, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	// Las cabeceras de la fuente se envían tal cual: llegan de la API sin autenticar, así que nunca
+	// expanden variables de entorno. Las credenciales solo salen de los perfiles HTTP.
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClientFor(ctx, f.httpClient).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener JSON: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+
+	var doc interface{}
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, maxJSONResponseSize))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error al parsear JSON: %w", err)
+	}
+	return doc, nil
+}
+
+// paginatedURL calcula la URL de la página indicada según la paginación configurada
+func paginatedURL(pageURL string, pagination *jsonPaginationOptions, page int) (string, error) {
+	if pagination == nil || pagination.Type == "next" || pagination.Param == "" {
+		return pageURL, nil
+	}
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("URL de endpoint inválida: %w", err)
+	}
+
+	step := pagination.Step
+	if step <= 0 {
+		step = 1
+	}
+	value := pagination.Start + page*step
+
+	query := u.Query()
+	query.Set(pagination.Param, strconv.Itoa(value))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// jsonField evalúa un campo (rutas alternativas separadas por '|') y devuelve el primer valor no vacío
+func jsonField(item interface{}, field string) string {
+	for _, p := range strings.Split(field, "|") {
+		if v := strings.TrimSpace(jsonScalar(evalJSONPath(item, p))); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// jsonDate lee una fecha como texto o como timestamp Unix (segundos o milisegundos)
+func jsonDate(item interface{}, field string) (time.Time, bool) {
+	for _, p := range strings.Split(field, "|") {
+		value := evalJSONPath(item, p)
+		if n, ok := value.(json.Number); ok {
+			if ts, err := n.Int64(); err == nil && ts > 0 {
+				if ts > 1e12 {
+					return time.UnixMilli(ts), true
+				}
+				return time.Unix(ts, 0), true
+			}
+		}
+		if t, ok := parseFlexibleDate(jsonScalar(value)); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// jsonScalar convierte un valor JSON escalar en texto
+func jsonScalar(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// evalJSONPath evalúa una ruta estilo JSONPath sencilla: "$.a.b[0].c", "a.b", "$['clave con espacios']".
+// Un "[*]" final se ignora para poder escribir la ruta al array de noticias como "$.items[*]".
+func evalJSONPath(doc interface{}, expr string) interface{} {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimSuffix(expr, "[*]")
+
+	current := doc
+	for _, token := range tokenizeJSONPath(expr) {
+		if current == nil {
+			return nil
+		}
+		if idx, err := strconv.Atoi(token); err == nil {
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil
+			}
+			if idx < 0 {
+				idx += len(arr)
+			}
+			if idx < 0 || idx >= len(arr) {
+				return nil
+			}
+			current = arr[idx]
+			continue
+		}
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = obj[token]
+	}
+	return current
+}
+
+// tokenizeJSONPath separa una ruta en claves e índices
+func tokenizeJSONPath(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(expr); i++ {
+		switch ch := expr[i]; ch {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(expr[i:], ']')
+			if end == -1 {
+				return tokens
+			}
+			inner := strings.Trim(expr[i+1:i+end], `'"`)
+			if inner != "*" && inner != "" {
+				tokens = append(tokens, inner)
+			}
+			i += end
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	flush()
+	return tokens
+}
@@ -0,0 +1,224 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeTestJSON(t *testing.T, raw string) interface{} {
+	t.Helper()
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		t.Fatalf("fixture JSON inválido: %v", err)
+	}
+	return doc
+}
+
+func TestEvalJSONPath(t *testing.T) {
+	doc := decodeTestJSON(t, `{
+		"data": {
+			"articles": [
+				{"title": "Uno", "media": [{"url": "a.jpg"}, {"url": "b.jpg"}]},
+				{"title": "Dos", "media": []}
+			],
+			"total": 2,
+			"ok": true
+		},
+		"clave con espacios": "valor"
+	}`)
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"$.data.articles[0].title", "Uno"},
+		{"data.articles[1].title", "Dos"},
+		{"$.data.articles[0].media[1].url", "b.jpg"},
+		{"$.data.articles[-1].title", "Dos"},
+		{"$['clave con espacios']", "valor"},
+		{`$["data"]["articles"][0]["title"]`, "Uno"},
+		{"$.data.total", json.Number("2")},
+		{"$.data.ok", true},
+		{"$.data.articles[1].media[0]", nil},
+		{"$.data.articles[5]", nil},
+		{"$.data.articles[-3]", nil},
+		{"$.data.missing.deep", nil},
+		{"$.data.articles.title", nil},
+		{"$.data.total[0]", nil},
+		{"  $.data.articles[0].title  ", "Uno"},
+	}
+	for _, tt := range tests {
+		if got := evalJSONPath(doc, tt.expr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("evalJSONPath(%q) = %#v, want %#v", tt.expr, got, tt.want)
+		}
+	}
+
+	// "$" y "$.x[*]" devuelven el documento y el array completos
+	if got := evalJSONPath(doc, "$"); !reflect.DeepEqual(got, doc) {
+		t.Errorf("evalJSONPath(\"$\") no devuelve el documento")
+	}
+	if arr, ok := evalJSONPath(doc, "$.data.articles[*]").([]interface{}); !ok || len(arr) != 2 {
+		t.Errorf("evalJSONPath(\"$.data.articles[*]\") = %#v, want array de 2", arr)
+	}
+}
+
+func TestTokenizeJSONPath(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{".a.b", []string{"a", "b"}},
+		{"a[0].b", []string{"a", "0", "b"}},
+		{"['x y'][\"z\"]", []string{"x y", "z"}},
+		{"a[*].b", []string{"a", "b"}},
+		{"a[]", []string{"a"}},
+		{"a[0", []string{"a"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := tokenizeJSONPath(tt.expr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenizeJSONPath(%q) = %#v, want %#v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestJSONFieldAndDate(t *testing.T) {
+	item := decodeTestJSON(t, `{
+		"headline": "",
+		"title": "  Titular  ",
+		"seconds": 1714557600,
+		"millis": 1714557600000,
+		"iso": "2024-05-01T10:00:00Z",
+		"text": "mañana"
+	}`)
+	want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	if got := jsonField(item, "$.headline|$.title"); got != "Titular" {
+		t.Errorf("jsonField con alternativas = %q", got)
+	}
+	if got := jsonField(item, "$.missing"); got != "" {
+		t.Errorf("jsonField de ruta inexistente = %q", got)
+	}
+
+	dates := []struct {
+		field string
+		ok    bool
+	}{
+		{"$.seconds", true},
+		{"$.millis", true},
+		{"$.iso", true},
+		{"$.text|$.iso", true},
+		{"$.text", false},
+		{"$.missing", false},
+	}
+	for _, tt := range dates {
+		got, ok := jsonDate(item, tt.field)
+		if ok != tt.ok {
+			t.Errorf("jsonDate(%q) ok = %v, want %v", tt.field, ok, tt.ok)
+			continue
+		}
+		if ok && !got.Equal(want) {
+			t.Errorf("jsonDate(%q) = %v, want %v", tt.field, got, want)
+		}
+	}
+}
+
+func TestPaginatedURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination *jsonPaginationOptions
+		page       int
+		want       string
+	}{
+		{"sin paginación", nil, 3, "https://api.example.com/news?lang=es"},
+		{"next no modifica la URL", &jsonPaginationOptions{Type: "next", Param: "page"}, 1, "https://api.example.com/news?lang=es"},
+		{"page desde 1", &jsonPaginationOptions{Type: "page", Param: "page", Start: 1}, 2, "https://api.example.com/news?lang=es&page=3"},
+		{"offset con paso", &jsonPaginationOptions{Type: "offset", Param: "offset", Step: 20}, 2, "https://api.example.com/news?lang=es&offset=40"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := paginatedURL("https://api.example.com/news?lang=es", tt.pagination, tt.page)
+			if err != nil {
+				t.Fatalf("paginatedURL: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFetcherPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secreto" {
+			t.Errorf("X-Api-Key = %q", r.Header.Get("X-Api-Key"))
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprintf(w, `{"items":[{"t":"Uno","u":"/uno"},{"t":"","u":"/sin-titulo"}],"next":"%s/?cursor=2"}`, server.URL)
+		case "2":
+			w.Write([]byte(`{"items":[{"t":"Dos","u":"https://otro.example/dos","ts":1714557600}],"next":""}`))
+		default:
+			t.Errorf("página inesperada: %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	options := `{"headers":{"X-Api-Key":"secreto"},"pagination":{"type":"next","nextPath":"$.next","maxPages":5}}`
+	fetcher := NewJSONFetcher(nil).(*jsonFetcher)
+	items, err := fetcher.FetchWithOptions(context.Background(), server.URL+"/", "$.items", "$.t", "", "$.u", "$.ts", options)
+	if err != nil {
+		t.Fatalf("FetchWithOptions: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(items), items)
+	}
+	if items[0].Link != server.URL+"/uno" {
+		t.Errorf("items[0].Link = %q", items[0].Link)
+	}
+	if items[1].Title != "Dos" || !items[1].PubDate.Equal(time.Unix(1714557600, 0)) {
+		t.Errorf("items[1] = %+v", items[1])
+	}
+}
+
+func TestJSONFetcherHeadersNotExpanded(t *testing.T) {
+	// Las cabeceras de la fuente vienen de peticiones sin autenticar: ninguna variable de entorno se expande
+	t.Setenv("DN_TEST_DB_PASSWORD", "contraseña")
+	t.Setenv("DAILYNEWS_SECRET_TEST", "clave")
+
+	got := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got <- r.Header.Clone()
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	options := `{"headers":{"X-Env":"${DN_TEST_DB_PASSWORD}","X-Secret":"${DAILYNEWS_SECRET_TEST}"}}`
+	fetcher := NewJSONFetcher(nil).(*jsonFetcher)
+	if _, err := fetcher.FetchWithOptions(context.Background(), server.URL+"/", "$.items", "$.t", "", "$.u", "", options); err != nil {
+		t.Fatalf("FetchWithOptions: %v", err)
+	}
+	headers := <-got
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"X-Env", "${DN_TEST_DB_PASSWORD}"},
+		{"X-Secret", "${DAILYNEWS_SECRET_TEST}"},
+	}
+	for _, tt := range tests {
+		if value := headers.Get(tt.name); value != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, value, tt.want)
+		}
+	}
+}
@@ -1,29 +1,114 @@
 package infrastructure
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/mmcdole/gofeed"
 
 	"dailynews/internal/domain"
+	"dailynews/pkg/feedcache"
 	"dailynews/pkg/utils"
 )
 
 // rssFetcher implementa la interfaz RSSFetcher del dominio
 type rssFetcher struct {
-	parser *gofeed.Parser
+	parser      *gofeed.Parser
+	httpClient  *http.Client
+	sharedFeeds *feedcache.Cache // Feeds parseados reutilizables entre extracciones (nil = desactivado)
+	imageTarget imageTarget      // Tamaño y aspecto con los que se elige la mejor imagen de cada item
 }
 
-// NewRSSFetcher crea una nueva instancia de RSSFetcher
-func NewRSSFetcher() domain.RSSFetcher {
+// NewRSSFetcher crea una nueva instancia de RSSFetcher. Con cacheTTL > 0 los feeds parseados
+// se reutilizan durante ese tiempo también entre extracciones distintas. De las imágenes de cada
+// item se elige la más cercana a targetAspect y targetWidth x targetHeight.
+func NewRSSFetcher(transport http.RoundTripper, cacheTTL time.Duration, targetAspect float64, targetWidth, targetHeight int) domain.RSSFetcher {
+	if targetAspect <= 0 {
+		targetAspect = 16.0 / 9.0
+	}
+	var sharedFeeds *feedcache.Cache
+	if cacheTTL > 0 {
+		sharedFeeds = feedcache.New(cacheTTL)
+	}
 	return &rssFetcher{
-		parser: gofeed.NewParser(),
+		sharedFeeds: sharedFeeds,
+		imageTarget: imageTarget{aspect: targetAspect, width: targetWidth, height: targetHeight},
+		parser:      gofeed.NewParser(),
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
 	}
 }
 
+// download descarga el feed con el cliente de la fuente (perfil HTTP si lo tiene), lo parsea y anota
+// sus enlaces WebSub
+func (f *rssFetcher) download(ctx context.Context, client *http.Client, feedURL string) (*feedcache.Feed, error) {
+	req, err := newSourceRequest(ctx, feedURL, "Gofeed/1.0", "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, gofeed.HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	// Los enlaces WebSub se guardan con el feed: así detectar el hub no lo vuelve a descargar
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := f.parser.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hub, self := webSubLinks(resp.Header, resp.Request.URL, bytes.NewReader(body))
+	return &feedcache.Feed{Feed: feed, Hub: hub, Self: self}, nil
+}
+
+// loadFeed devuelve el feed parseado desde la caché de la extracción (contexto), la caché compartida
+// o descargándolo. La clave incluye el perfil HTTP porque puede cambiar la respuesta del servidor.
+func (f *rssFetcher) loadFeed(ctx context.Context, client *http.Client, feedURL string) (*gofeed.Feed, bool, error) {
+	key := feedCacheKey(ctx, feedURL)
+	f.sharedFeeds.Purge()
+	var sharedHit bool
+	loadShared := func() (*feedcache.Feed, error) {
+		feed, hit, err := f.sharedFeeds.GetOrLoad(key, func() (*feedcache.Feed, error) {
+			return f.download(ctx, client, feedURL)
+		})
+		sharedHit = hit
+		return feed, err
+	}
+	// Sin caché de extracción en el contexto, GetOrLoad sobre nil carga directamente
+	feed, runHit, err := feedcache.FromContext(ctx).GetOrLoad(key, loadShared)
+	if err != nil {
+		return nil, false, err
+	}
+	return feed.Feed, runHit || sharedHit, nil
+}
+
+// defaultImageWidth es el ancho objetivo con el que se elige de srcset fuera de Fetch
+const defaultImageWidth = 800
+
+// feedCacheKey es la clave del feed en las cachés de feeds: la URL más el perfil HTTP del contexto, si lo hay
+func feedCacheKey(ctx context.Context, feedURL string) string {
+	feedURL = strings.TrimSpace(feedURL)
+	if profile := domain.HTTPProfileFromContext(ctx); profile != nil {
+		return fmt.Sprintf("%s#profile=%d", feedURL, profile.ID)
+	}
+	return feedURL
+}
+
 // Definición de patrones de extracción basados en los feeds reales
 // PATRONES CON IMAGEN (existentes):
 // patron1: title, media:content (con alternativa media:thumbnail), link, pubDate
@@ -59,10 +144,11 @@ func (f *rssFetcher) Fetch(ctx context.Context, url string, filter string, title
 		"url":    url,
 	})
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	client := httpClientFor(ctx, f.httpClient)
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
 	defer cancel()
 
-	feed, err := f.parser.ParseURLWithContext(url, ctx)
+	feed, cached, err := f.loadFeed(ctx, client, url)
 	if err != nil {
 		utils.SourceError(url, err.Error())
 		return nil, fmt.Errorf("error al obtener feed RSS: %w", err)
@@ -71,14 +157,16 @@ func (f *rssFetcher) Fetch(ctx context.Context, url string, filter string, title
 	utils.AppInfo("RSS_FETCHER", "Feed obtenido exitosamente", map[string]interface{}{
 		"items_count": len(feed.Items),
 		"url":         url,
+		"cached":      cached,
 	})
 
 	var items []domain.NewsItem
 	for i, item := range feed.Items {
 		newsNum := i + 1
 		var title, imageURL, linkURL string
+		var imageCandidates []string
 		var pubDate time.Time
-		var titleFormat, imageFormat, linkFormat, dateFormat string
+		var titleFormat, imageFormat, linkFormat string
 
 		// ===== EXTRACCIÓN DE TÍTULO =====
 		if titleField != "" {
@@ -96,17 +184,19 @@ func (f *rssFetcher) Fetch(ctx context.Context, url string, filter string, title
 		}
 
 		// ===== EXTRACCIÓN DE IMAGEN =====
+		// Se reúnen todas las imágenes del item y se ordenan por ajuste al tamaño objetivo;
+		// las de los campos de la fuente o del patrón van primero
 		if imageField != "" {
-			imageURL = extractFieldFromItem(item, imageField)
 			imageFormat = imageField
+		} else if !strings.Contains(filter, "no_image") {
+			imageFormat = extractionPatterns[filter].ImageField
 		} else {
-			pattern := extractionPatterns[filter]
-			// Solo extraer imagen si el patrón no es "sin imagen"
-			if !strings.Contains(filter, "no_image") {
-				imageURL = extractFieldFromItem(item, pattern.ImageField)
-				imageFormat = pattern.ImageField
-			} else {
-				imageFormat = "no_image"
+			imageFormat = "no_image"
+		}
+		if imageFormat != "no_image" {
+			imageCandidates = rankImageCandidates(collectImageCandidates(item, imageFormat, f.imageTarget.width), f.imageTarget)
+			if len(imageCandidates) > 0 {
+				imageURL = imageCandidates[0]
 			}
 		}
 
@@ -130,26 +220,20 @@ func (f *rssFetcher) Fetch(ctx context.Context, url string, filter string, title
 			dateStr := extractFieldFromItem(item, dateField)
 			if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
 				pubDate = t
-				dateFormat = dateField
 			} else {
 				pubDate = time.Now()
-				dateFormat = dateField + " (fallback)"
 			}
 		} else {
 			pattern := extractionPatterns[filter]
 			dateStr := extractFieldFromItem(item, pattern.DateField)
 			if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
 				pubDate = t
-				dateFormat = pattern.DateField
 			} else if item.PublishedParsed != nil {
 				pubDate = *item.PublishedParsed
-				dateFormat = "PublishedParsed"
 			} else if item.UpdatedParsed != nil {
 				pubDate = *item.UpdatedParsed
-				dateFormat = "UpdatedParsed"
 			} else {
 				pubDate = time.Now()
-				dateFormat = "current_time"
 			}
 		}
 
@@ -158,13 +242,12 @@ func (f *rssFetcher) Fetch(ctx context.Context, url string, filter string, title
 			Link:    linkURL,
 			Image:   imageURL,
 			PubDate: pubDate,
+
+			ImageCandidates: imageCandidates,
 		}
 		items = append(items, newsItem)
 
 		// NO LOG - Eliminamos el log confuso de "Noticia X procesada"
-
-		_ = imageFormat
-		_ = dateFormat
 	}
 
 	utils.SourceProcessingComplete(url, len(items), len(feed.Items))
@@ -189,13 +272,9 @@ func extractFieldFromItem(item *gofeed.Item, field string) string {
 			if result := getMediaThumbnail(item); result != "" {
 				return result
 			}
-		case "enclosure":
-			if len(item.Enclosures) > 0 && strings.HasPrefix(item.Enclosures[0].Type, "image/") {
-				return item.Enclosures[0].URL
-			}
-		case "description_img":
-			if result := extractImgFromDescription(item.Description); result != "" {
-				return result
+		case "enclosure", "itunes:image", "description_img":
+			if candidates := imageCandidatesFromField(item, f, defaultImageWidth); len(candidates) > 0 {
+				return candidates[0].url
 			}
 		case "link":
 			if item.Link != "" {
@@ -213,55 +292,22 @@ func extractFieldFromItem(item *gofeed.Item, field string) string {
 	return ""
 }
 
-// getMediaThumbnail busca media:thumbnail en las extensiones
+// getMediaThumbnail devuelve el primer media:thumbnail de imagen (también dentro de media:group)
 func getMediaThumbnail(item *gofeed.Item) string {
-	if ext, ok := item.Extensions["media"]; ok {
-		if thumbs, ok := ext["thumbnail"]; ok && len(thumbs) > 0 {
-			if url, ok := thumbs[0].Attrs["url"]; ok {
-				return url
-			}
-		}
+	if candidates := mediaCandidates(item, "thumbnail"); len(candidates) > 0 {
+		return candidates[0].url
 	}
 	return ""
 }
 
-// getMediaContent busca media:content en las extensiones
+// getMediaContent devuelve el primer media:content de imagen (también dentro de media:group)
 func getMediaContent(item *gofeed.Item) string {
-	if ext, ok := item.Extensions["media"]; ok {
-		if contents, ok := ext["content"]; ok && len(contents) > 0 {
-			if url, ok := contents[0].Attrs["url"]; ok {
-				return url
-			}
-		}
+	if candidates := mediaCandidates(item, "content"); len(candidates) > 0 {
+		return candidates[0].url
 	}
 	return ""
 }
 
-// extractImgFromDescription busca la primera imagen en el HTML de la descripción
-func extractImgFromDescription(desc string) string {
-	start := strings.Index(desc, "<img ")
-	if start == -1 {
-		return ""
-	}
-	imgTag := desc[start:]
-	end := strings.Index(imgTag, ">")
-	if end == -1 {
-		return ""
-	}
-	imgTag = imgTag[:end]
-	srcIdx := strings.Index(imgTag, "src=")
-	if srcIdx == -1 {
-		return ""
-	}
-	quote := imgTag[srcIdx+4]
-	rest := imgTag[srcIdx+5:]
-	endQuote := strings.IndexRune(rest, rune(quote))
-	if endQuote == -1 {
-		return ""
-	}
-	return rest[:endQuote]
-}
-
 // cleanCDATA elimina CDATA y espacios
 func cleanCDATA(s string) string {
 	s = strings.TrimSpace(s)
@@ -0,0 +1,297 @@
+package infrastructure
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// maxSitemapSize limita el tamaño de cada sitemap descargado (20MB, descomprimido)
+const maxSitemapSize = 20 * 1024 * 1024
+
+// maxSitemapDepth limita los niveles de índices de sitemap que se siguen
+const maxSitemapDepth = 2
+
+// sitemapDocument representa tanto un <sitemapindex> como un <urlset>.
+// encoding/xml compara solo el nombre local, por lo que news:news e image:image encajan directamente.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	Sitemaps []sitemapRef `xml:"sitemap"`
+	URLs     []sitemapURL `xml:"url"`
+}
+
+// sitemapRef es una entrada de un índice de sitemaps
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapURL es una entrada de un urlset, con las extensiones de Google News e imágenes
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+	News    *struct {
+		Title           string `xml:"title"`
+		PublicationDate string `xml:"publication_date"`
+	} `xml:"news"`
+	Images []struct {
+		Loc   string `xml:"loc"`
+		Title string `xml:"title"`
+	} `xml:"image"`
+}
+
+// sitemapEntry es una URL candidata ya normalizada
+type sitemapEntry struct {
+	link    string
+	title   string
+	image   string
+	pubDate time.Time
+}
+
+// sitemapFetcher implementa la interfaz RSSFetcher para sitemaps de Google News y sitemaps genéricos
+type sitemapFetcher struct {
+	httpClient       *http.Client
+	maxURLs          int
+	maxAge           time.Duration
+	maxChildSitemaps int
+}
+
+// NewSitemapFetcher crea un fetcher de sitemaps con límites de URLs, antigüedad y sitemaps hijos
+func NewSitemapFetcher(transport http.RoundTripper, maxURLs int, maxAge time.Duration, maxChildSitemaps int) domain.RSSFetcher {
+	if maxURLs <= 0 {
+		maxURLs = 50
+	}
+	if maxAge <= 0 {
+		maxAge = 48 * time.Hour
+	}
+	if maxChildSitemaps <= 0 {
+		maxChildSitemaps = 5
+	}
+	return &sitemapFetcher{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+		maxURLs:          maxURLs,
+		maxAge:           maxAge,
+		maxChildSitemaps: maxChildSitemaps,
+	}
+}
+
+// Fetch lee el sitemap (o índice de sitemaps) y devuelve las URLs más recientes como noticias.
+// En este fetcher el parámetro filter es un fragmento de ruta opcional que deben contener las URLs
+// (ej: "/deportes/"), útil cuando un único sitemap cubre varias secciones.
+func (f *sitemapFetcher) Fetch(ctx context.Context, sitemapURL string, filter string, titleField, imageField, linkField, dateField string) ([]domain.NewsItem, error) {
+	sitemapURL = strings.TrimSpace(sitemapURL)
+	pathFilter := strings.TrimSpace(filter)
+
+	utils.AppInfo("SITEMAP_FETCHER", "Iniciando extracción de sitemap", map[string]interface{}{
+		"path_filter": pathFilter,
+		"url":         sitemapURL,
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cutoff := time.Now().Add(-f.maxAge)
+	entries, err := f.collect(ctx, sitemapURL, pathFilter, cutoff, 0)
+	if err != nil {
+		utils.SourceError(sitemapURL, err.Error())
+		return nil, err
+	}
+	total := len(entries)
+
+	// Más recientes primero; las URLs sin fecha van al final
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].pubDate.After(entries[j].pubDate)
+	})
+	if len(entries) > f.maxURLs {
+		entries = entries[:f.maxURLs]
+	}
+
+	items := make([]domain.NewsItem, 0, len(entries))
+	for _, entry := range entries {
+		pubDate := entry.pubDate
+		if pubDate.IsZero() {
+			pubDate = time.Now()
+		}
+		items = append(items, domain.NewsItem{
+			Title:   entry.title,
+			Link:    entry.link,
+			Image:   entry.image,
+			PubDate: pubDate,
+		})
+	}
+
+	utils.SourceProcessingComplete(sitemapURL, len(items), total)
+	return items, nil
+}
+
+// collect descarga un sitemap y devuelve sus entradas, siguiendo los índices hasta maxSitemapDepth
+func (f *sitemapFetcher) collect(ctx context.Context, sitemapURL, pathFilter string, cutoff time.Time, depth int) ([]sitemapEntry, error) {
+	doc, err := f.download(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sitemapEntry
+
+	if len(doc.Sitemaps) > 0 {
+		if depth >= maxSitemapDepth {
+			return nil, nil
+		}
+
+		// Priorizar los sitemaps hijos modificados más recientemente y descartar los antiguos
+		children := make([]sitemapRef, 0, len(doc.Sitemaps))
+		for _, ref := range doc.Sitemaps {
+			if t, ok := parseFlexibleDate(ref.LastMod); ok && t.Before(cutoff) {
+				continue
+			}
+			children = append(children, ref)
+		}
+		sort.SliceStable(children, func(i, j int) bool {
+			ti, _ := parseFlexibleDate(children[i].LastMod)
+			tj, _ := parseFlexibleDate(children[j].LastMod)
+			return ti.After(tj)
+		})
+		if len(children) > f.maxChildSitemaps {
+			children = children[:f.maxChildSitemaps]
+		}
+
+		for _, child := range children {
+			childURL := strings.TrimSpace(child.Loc)
+			if childURL == "" {
+				continue
+			}
+			childEntries, err := f.collect(ctx, childURL, pathFilter, cutoff, depth+1)
+			if err != nil {
+				utils.SourceWarn(childURL, err.Error())
+				continue
+			}
+			entries = append(entries, childEntries...)
+		}
+	}
+
+	for _, u := range doc.URLs {
+		link := strings.TrimSpace(u.Loc)
+		if link == "" {
+			continue
+		}
+		if pathFilter != "" && !strings.Contains(link, pathFilter) {
+			continue
+		}
+
+		entry := sitemapEntry{link: link}
+		if u.News != nil {
+			entry.title = strings.TrimSpace(u.News.Title)
+			entry.pubDate, _ = parseFlexibleDate(u.News.PublicationDate)
+		}
+		if entry.pubDate.IsZero() {
+			entry.pubDate, _ = parseFlexibleDate(u.LastMod)
+		}
+		if !entry.pubDate.IsZero() && entry.pubDate.Before(cutoff) {
+			continue
+		}
+
+		for _, img := range u.Images {
+			if loc := strings.TrimSpace(img.Loc); loc != "" {
+				entry.image = loc
+				if entry.title == "" {
+					entry.title = strings.TrimSpace(img.Title)
+				}
+				break
+			}
+		}
+		// Los sitemaps genéricos no incluyen titular: se deriva del slug de la URL
+		if entry.title == "" {
+			entry.title = titleFromSlug(link)
+		}
+		if entry.title == "" {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// download obtiene y parsea un sitemap, descomprimiendo gzip si es necesario
+func (f *sitemapFetcher) download(ctx context.Context, sitemapURL string) (*sitemapDocument, error) {
+	req, err := newSourceRequest(ctx, sitemapURL, browserUserAgent, "application/xml,text/xml")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClientFor(ctx, f.httpClient).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener sitemap: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+
+	// Los sitemaps .xml.gz se sirven comprimidos sin Content-Encoding: detectar por los bytes mágicos
+	var body io.Reader = bufio.NewReader(resp.Body)
+	if magic, err := body.(*bufio.Reader).Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("error descomprimiendo sitemap: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(io.LimitReader(body, maxSitemapSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error al parsear sitemap: %w", err)
+	}
+	if doc.XMLName.Local != "urlset" && doc.XMLName.Local != "sitemapindex" {
+		return nil, fmt.Errorf("el documento no es un sitemap (<%s>)", doc.XMLName.Local)
+	}
+	return &doc, nil
+}
+
+// titleFromSlug construye un titular legible a partir del último segmento de la URL
+func titleFromSlug(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	slug := path.Base(strings.TrimSuffix(u.Path, "/"))
+	slug = strings.TrimSuffix(slug, path.Ext(slug))
+	words := strings.FieldsFunc(slug, func(r rune) bool {
+		return r == '-' || r == '_' || r == '+'
+	})
+
+	// Descartar identificadores numéricos que suelen acompañar al slug
+	var kept []string
+	for _, w := range words {
+		if strings.IndexFunc(w, unicode.IsLetter) == -1 {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	if len(kept) < 3 {
+		return ""
+	}
+
+	title := strings.Join(kept, " ")
+	runes := []rune(title)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSitemapFetcherFetch(t *testing.T) {
+	now := time.Now().UTC()
+	recent := now.Add(-2 * time.Hour).Format(time.RFC3339)
+	newer := now.Add(-1 * time.Hour).Format(time.RFC3339)
+	old := now.Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.xml":
+			fmt.Fprintf(w, `<?xml version="1.0"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/news.xml.gz</loc><lastmod>%[2]s</lastmod></sitemap>
+  <sitemap><loc>%[1]s/archivo.xml</loc><lastmod>%[3]s</lastmod></sitemap>
+</sitemapindex>`, server.URL, recent, old)
+		case "/news.xml.gz":
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			fmt.Fprintf(gz, `<?xml version="1.0"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+        xmlns:image="http://www.google.com/schemas/sitemap-image/1.1">
+  <url>
+    <loc>https://example.com/deportes/final-de-copa</loc>
+    <news:news><news:title>Final de copa</news:title><news:publication_date>%[1]s</news:publication_date></news:news>
+    <image:image><image:loc>https://example.com/img/copa.jpg</image:loc></image:image>
+  </url>
+  <url>
+    <loc>https://example.com/deportes/2024/05/gran-victoria-del-equipo-local-12345.html</loc>
+    <lastmod>%[2]s</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/economia/subida-de-tipos-de-interes</loc>
+    <lastmod>%[2]s</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/deportes/noticia-antigua-del-archivo</loc>
+    <lastmod>%[3]s</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/deportes/12345</loc>
+    <lastmod>%[2]s</lastmod>
+  </url>
+</urlset>`, recent, newer, old)
+			gz.Close()
+			w.Write(buf.Bytes())
+		case "/archivo.xml":
+			t.Error("no debería descargarse un sitemap hijo anterior al límite de antigüedad")
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewSitemapFetcher(nil, 10, 48*time.Hour, 5)
+	items, err := fetcher.Fetch(context.Background(), server.URL+"/index.xml", "/deportes/", "", "", "", "")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2: %+v", len(items), items)
+	}
+
+	// Más recientes primero
+	if items[0].Title != "Gran victoria del equipo local" {
+		t.Errorf("items[0].Title = %q (derivado del slug)", items[0].Title)
+	}
+	if items[1].Title != "Final de copa" || items[1].Image != "https://example.com/img/copa.jpg" {
+		t.Errorf("items[1] = %+v", items[1])
+	}
+}
+
+func TestSitemapFetcherRejectsNonSitemap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel></channel></rss>`))
+	}))
+	defer server.Close()
+
+	fetcher := NewSitemapFetcher(nil, 0, 0, 0)
+	if _, err := fetcher.Fetch(context.Background(), server.URL, "", "", "", "", ""); err == nil {
+		t.Fatal("expected error for a non-sitemap document")
+	}
+}
+
+func TestTitleFromSlug(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"https://example.com/politica/el-gobierno-aprueba-los-presupuestos", "El gobierno aprueba los presupuestos"},
+		{"https://example.com/2024/05/01/nueva_ley_de_vivienda_98765.html", "Nueva ley de vivienda"},
+		{"https://example.com/seccion/titular+con+mas+palabras/", "Titular con mas palabras"},
+		{"https://example.com/corto-slug", ""},
+		{"https://example.com/noticia/1234567", ""},
+		{"https://example.com/", ""},
+		{"://mal", ""},
+	}
+	for _, tt := range tests {
+		if got := titleFromSlug(tt.link); got != tt.want {
+			t.Errorf("titleFromSlug(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
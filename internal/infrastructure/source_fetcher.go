@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"dailynews/internal/domain"
+)
+
+// optionsFetcher lo implementan los fetchers que admiten opciones adicionales por fuente (NewsSource.FetchOptions)
+type optionsFetcher interface {
+	FetchWithOptions(ctx context.Context, url string, filter string, titleField, imageField, linkField, dateField, options string) ([]domain.NewsItem, error)
+}
+
+// sourceFetcher implementa la interfaz SourceFetcher delegando en el fetcher de cada tipo de fuente
+type sourceFetcher struct {
+	fetchers map[string]domain.RSSFetcher
+}
+
+// NewSourceFetcher crea un SourceFetcher a partir de los fetchers registrados por tipo de fuente
+func NewSourceFetcher(fetchers map[string]domain.RSSFetcher) domain.SourceFetcher {
+	return &sourceFetcher{
+		fetchers: fetchers,
+	}
+}
+
+// FetchSource obtiene las noticias de una fuente usando el fetcher de su tipo
+func (f *sourceFetcher) FetchSource(ctx context.Context, source *domain.NewsSource) ([]domain.NewsItem, error) {
+	if source == nil {
+		return nil, fmt.Errorf("la fuente no puede ser nil")
+	}
+
+	sourceType := source.GetType()
+	fetcher, ok := f.fetchers[sourceType]
+	if !ok {
+		return nil, fmt.Errorf("tipo de fuente no soportado: %s", sourceType)
+	}
+
+	// Las fuentes RSS usan el patrón de extracción; el resto, el selector de noticias
+	filter := derefString(source.Filter)
+	if sourceType != domain.SourceTypeRSS {
+		filter = derefString(source.ItemSelector)
+	}
+
+	if of, ok := fetcher.(optionsFetcher); ok && source.FetchOptions != nil {
+		return of.FetchWithOptions(
+			ctx,
+			source.RSSURL,
+			filter,
+			derefString(source.TitleField),
+			derefString(source.ImageField),
+			derefString(source.LinkField),
+			derefString(source.CampoFecha),
+			*source.FetchOptions,
+		)
+	}
+
+	return fetcher.Fetch(
+		ctx,
+		source.RSSURL,
+		filter,
+		derefString(source.TitleField),
+		derefString(source.ImageField),
+		derefString(source.LinkField),
+		derefString(source.CampoFecha),
+	)
+}
+
+// derefString devuelve el valor de un *string o "" si es nil
+func derefString(ptr *string) string {
+	if ptr != nil {
+		return *ptr
+	}
+	return ""
+}
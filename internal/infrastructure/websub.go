@@ -0,0 +1,444 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/feedcache"
+	"dailynews/pkg/utils"
+)
+
+const (
+	webSubDefaultLease = 10 * 24 * time.Hour // Duración pedida al hub si no se configura otra
+	webSubRenewBefore  = 24 * time.Hour      // Margen con el que se renuevan las suscripciones
+	webSubRecheckAfter = 7 * 24 * time.Hour  // Espera para volver a buscar hub en feeds sin él o rechazados
+	webSubPendingRetry = 24 * time.Hour      // Espera para reintentar una suscripción sin verificar
+	webSubMaxBody      = 5 << 20             // Tamaño máximo del feed leído al detectar el hub
+)
+
+// webSubManager implementa domain.WebSubManager: detecta el hub de los feeds RSS, se suscribe
+// con un callback público por suscripción y valida el contenido que envía el hub
+type webSubManager struct {
+	repo         domain.WebSubSubscriptionRepository
+	sourceRepo   domain.NewsSourceRepository
+	httpClient   *http.Client
+	callbackBase string
+	lease        time.Duration
+}
+
+// NewWebSubManager crea el gestor de suscripciones WebSub. callbackBaseURL es la URL pública
+// del servidor (el hub llama a <callbackBaseURL>/websub/callback/<id>).
+func NewWebSubManager(repo domain.WebSubSubscriptionRepository, sourceRepo domain.NewsSourceRepository, transport http.RoundTripper, callbackBaseURL string, lease time.Duration) domain.WebSubManager {
+	if lease <= 0 {
+		lease = webSubDefaultLease
+	}
+	return &webSubManager{
+		repo:       repo,
+		sourceRepo: sourceRepo,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+		callbackBase: strings.TrimRight(callbackBaseURL, "/"),
+		lease:        lease,
+	}
+}
+
+// EnsureSubscribed detecta el hub del feed y se suscribe si la fuente no tiene una suscripción vigente
+func (m *webSubManager) EnsureSubscribed(ctx context.Context, source *domain.NewsSource) error {
+	if source.GetType() != domain.SourceTypeRSS {
+		return nil
+	}
+
+	sub, err := m.repo.FindBySourceID(ctx, source.ID)
+	if err != nil {
+		return fmt.Errorf("error obteniendo suscripción WebSub: %w", err)
+	}
+	if sub != nil && !m.needsCheck(sub) {
+		return nil
+	}
+	if sub == nil {
+		sub = &domain.WebSubSubscription{SourceID: source.ID}
+	}
+
+	hub, self, err := m.discoverHub(ctx, source.RSSURL)
+	sub.CheckedAt = time.Now()
+	if err != nil {
+		sub.LastError = err.Error()
+		if saveErr := m.repo.Save(ctx, sub); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+	if hub == "" {
+		sub.State = domain.WebSubUnsupported
+		sub.Hub = ""
+		sub.LastError = ""
+		return m.repo.Save(ctx, sub)
+	}
+
+	sub.Hub = hub
+	sub.Topic = self
+	if sub.Topic == "" {
+		sub.Topic = strings.TrimSpace(source.RSSURL)
+	}
+	if sub.Secret == "" {
+		if sub.Secret, err = newWebSubSecret(); err != nil {
+			return err
+		}
+	}
+	sub.State = domain.WebSubPending
+	sub.LastError = ""
+	// Se guarda antes de pedir la suscripción: el callback necesita el ID y el hub puede verificar
+	// la intención antes de responder a la petición
+	if err := m.repo.Save(ctx, sub); err != nil {
+		return err
+	}
+
+	utils.AppInfo("WEBSUB", "Suscribiendo feed al hub", map[string]interface{}{
+		"source_id": source.ID,
+		"topic":     sub.Topic,
+		"hub":       sub.Hub,
+	})
+	return m.subscribe(ctx, sub)
+}
+
+// needsCheck indica si hay que volver a detectar el hub o a pedir la suscripción
+func (m *webSubManager) needsCheck(sub *domain.WebSubSubscription) bool {
+	switch sub.State {
+	case domain.WebSubActive:
+		return sub.ExpiresAt != nil && time.Now().After(*sub.ExpiresAt)
+	case domain.WebSubPending:
+		return time.Since(sub.CheckedAt) > webSubPendingRetry
+	default:
+		return time.Since(sub.CheckedAt) > webSubRecheckAfter
+	}
+}
+
+// subscribe envía la petición de suscripción (o renovación) al hub. La suscripción queda
+// activa cuando el hub verifica la intención en el callback.
+func (m *webSubManager) subscribe(ctx context.Context, sub *domain.WebSubSubscription) error {
+	form := url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {sub.Topic},
+		"hub.callback":      {m.callbackURL(sub.ID)},
+		"hub.lease_seconds": {strconv.Itoa(int(m.lease.Seconds()))},
+		"hub.secret":        {sub.Secret},
+	}
+
+	err := m.postToHub(ctx, sub.Hub, form)
+	if err != nil {
+		sub.LastError = err.Error()
+		if saveErr := m.repo.Save(ctx, sub); saveErr != nil {
+			return saveErr
+		}
+		return fmt.Errorf("error suscribiendo al hub: %w", err)
+	}
+	return nil
+}
+
+// postToHub envía el formulario al hub; cualquier respuesta 2xx (normalmente 202) es aceptación
+func (m *webSubManager) postToHub(ctx context.Context, hub string, form url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creando petición: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("el hub respondió %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// callbackURL es la URL pública a la que el hub envía verificaciones y contenido
+func (m *webSubManager) callbackURL(id uint) string {
+	return fmt.Sprintf("%s/websub/callback/%d", m.callbackBase, id)
+}
+
+// discoverHub devuelve los enlaces rel="hub" y rel="self" del feed. Si la extracción en curso ya lo
+// descargó, salen de la caché de feeds del contexto; si no, se descarga solo para buscarlos.
+func (m *webSubManager) discoverHub(ctx context.Context, feedURL string) (hub, self string, err error) {
+	if feed, ok := feedcache.FromContext(ctx).Get(feedCacheKey(ctx, feedURL)); ok {
+		return feed.Hub, feed.Self, nil
+	}
+
+	client := httpClientFor(ctx, m.httpClient)
+	ctx, cancel := context.WithTimeout(ctx, client.Timeout)
+	defer cancel()
+
+	req, err := newSourceRequest(ctx, strings.TrimSpace(feedURL), "Gofeed/1.0", "")
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("error descargando feed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", "", fmt.Errorf("código de estado inesperado: %d", resp.StatusCode)
+	}
+	hub, self = webSubLinks(resp.Header, resp.Request.URL, resp.Body)
+	return hub, self, nil
+}
+
+// webSubLinks busca los enlaces rel="hub" y rel="self" en la cabecera Link y en los elementos <link>
+// (atom:link en RSS, link en Atom) previos al primer item. Los relativos se resuelven contra base.
+func webSubLinks(header http.Header, base *url.URL, body io.Reader) (hub, self string) {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			href, rel := parseLinkHeader(link)
+			switch {
+			case hasRel(rel, "hub") && hub == "":
+				hub = resolveURL(base, href)
+			case hasRel(rel, "self") && self == "":
+				self = resolveURL(base, href)
+			}
+		}
+	}
+
+	decoder := xml.NewDecoder(io.LimitReader(body, webSubMaxBody))
+	decoder.Strict = false
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+	for hub == "" || self == "" {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "item" || start.Name.Local == "entry" {
+			break
+		}
+		if start.Name.Local != "link" {
+			continue
+		}
+		var rel, href string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "rel":
+				rel = attr.Value
+			case "href":
+				href = attr.Value
+			}
+		}
+		switch {
+		case hasRel(rel, "hub") && hub == "":
+			hub = resolveURL(base, href)
+		case hasRel(rel, "self") && self == "":
+			self = resolveURL(base, href)
+		}
+	}
+	return hub, self
+}
+
+// Verify atiende la verificación de intención del hub (subscribe, unsubscribe o denied)
+func (m *webSubManager) Verify(ctx context.Context, subscriptionID uint, mode, topic, challenge string, leaseSeconds int, reason string) (string, error) {
+	sub, err := m.repo.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return "", err
+	}
+	if sub == nil || topic != sub.Topic {
+		return "", domain.ErrWebSubNotFound
+	}
+
+	switch mode {
+	case "subscribe":
+		if challenge == "" {
+			return "", errors.New("falta hub.challenge")
+		}
+		lease := time.Duration(leaseSeconds) * time.Second
+		if lease <= 0 {
+			lease = m.lease
+		}
+		expiresAt := time.Now().Add(lease)
+		sub.State = domain.WebSubActive
+		sub.LeaseSeconds = int(lease.Seconds())
+		sub.ExpiresAt = &expiresAt
+		sub.LastError = ""
+	case "unsubscribe":
+		if challenge == "" {
+			return "", errors.New("falta hub.challenge")
+		}
+		sub.State = domain.WebSubUnsubscribed
+		sub.ExpiresAt = nil
+	case "denied":
+		sub.State = domain.WebSubDenied
+		sub.ExpiresAt = nil
+		sub.LastError = reason
+	default:
+		return "", fmt.Errorf("hub.mode no soportado: %s", mode)
+	}
+
+	if err := m.repo.Save(ctx, sub); err != nil {
+		return "", err
+	}
+	utils.AppInfo("WEBSUB", "Verificación del hub atendida", map[string]interface{}{
+		"subscription_id": sub.ID,
+		"source_id":       sub.SourceID,
+		"mode":            mode,
+		"lease_seconds":   sub.LeaseSeconds,
+	})
+	return challenge, nil
+}
+
+// Receive valida la firma del contenido enviado por el hub y lo deja en la caché de feeds del
+// contexto devuelto, con la misma clave que usará el fetcher RSS de la fuente
+func (m *webSubManager) Receive(ctx context.Context, subscriptionID uint, body []byte, signature string) (uint, context.Context, error) {
+	sub, err := m.repo.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return 0, ctx, err
+	}
+	if sub == nil || sub.State != domain.WebSubActive {
+		return 0, ctx, domain.ErrWebSubNotFound
+	}
+	if sub.Secret != "" && !validWebSubSignature(sub.Secret, body, signature) {
+		return 0, ctx, domain.ErrWebSubSignature
+	}
+
+	source, err := m.sourceRepo.FindByID(ctx, sub.SourceID)
+	if err != nil {
+		return 0, ctx, err
+	}
+	if source == nil {
+		return 0, ctx, domain.ErrWebSubNotFound
+	}
+
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		return 0, ctx, fmt.Errorf("error parseando contenido recibido: %w", err)
+	}
+
+	now := time.Now()
+	sub.LastPushAt = &now
+	if err := m.repo.Save(ctx, sub); err != nil {
+		return 0, ctx, err
+	}
+
+	cached := &feedcache.Feed{Feed: feed}
+	if base, err := url.Parse(strings.TrimSpace(source.RSSURL)); err == nil {
+		cached.Hub, cached.Self = webSubLinks(nil, base, bytes.NewReader(body))
+	}
+	cache := feedcache.New(0)
+	cache.Set(feedCacheKey(domain.WithHTTPProfile(ctx, source.HTTPProfile, source.RSSURL), source.RSSURL), cached)
+	utils.AppInfo("WEBSUB", "Contenido recibido del hub", map[string]interface{}{
+		"source_id": source.ID,
+		"items":     len(feed.Items),
+	})
+	return source.ID, feedcache.NewContext(ctx, cache), nil
+}
+
+// RenewExpiring vuelve a pedir la suscripción de las que caducan en las próximas horas
+func (m *webSubManager) RenewExpiring(ctx context.Context) error {
+	subs, err := m.repo.ListExpiringBefore(ctx, time.Now().Add(webSubRenewBefore))
+	if err != nil {
+		return fmt.Errorf("error obteniendo suscripciones por caducar: %w", err)
+	}
+
+	var failed int
+	for i := range subs {
+		if err := m.subscribe(ctx, &subs[i]); err != nil {
+			failed++
+			utils.AppWarn("WEBSUB", "Error renovando suscripción", map[string]interface{}{
+				"subscription_id": subs[i].ID,
+				"source_id":       subs[i].SourceID,
+				"error":           err.Error(),
+			})
+		}
+	}
+	utils.AppInfo("WEBSUB", "Renovación de suscripciones completada", map[string]interface{}{
+		"renewed": len(subs) - failed,
+		"failed":  failed,
+	})
+	return nil
+}
+
+// validWebSubSignature comprueba la cabecera X-Hub-Signature ("sha256=<hex>", también sha1/sha384/sha512)
+func validWebSubSignature(secret string, body []byte, signature string) bool {
+	method, digest, found := strings.Cut(strings.TrimSpace(signature), "=")
+	if !found {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// newWebSubSecret genera la clave HMAC de una suscripción
+func newWebSubSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generando secreto WebSub: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// parseLinkHeader extrae la URL y el rel de un valor de cabecera Link: <url>; rel="hub"
+func parseLinkHeader(link string) (href, rel string) {
+	parts := strings.Split(link, ";")
+	href = strings.Trim(strings.TrimSpace(parts[0]), "<>")
+	for _, param := range parts[1:] {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(key, "rel") {
+			rel = strings.Trim(value, `"`)
+		}
+	}
+	return href, rel
+}
+
+// hasRel indica si la lista de rel (separada por espacios) contiene el valor indicado
+func hasRel(rel, value string) bool {
+	for _, r := range strings.Fields(rel) {
+		if strings.EqualFold(r, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,398 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/feedcache"
+)
+
+// memoryWebSubRepo es un WebSubSubscriptionRepository en memoria
+type memoryWebSubRepo struct {
+	mu   sync.Mutex
+	subs map[uint]domain.WebSubSubscription
+}
+
+func newMemoryWebSubRepo() *memoryWebSubRepo {
+	return &memoryWebSubRepo{subs: make(map[uint]domain.WebSubSubscription)}
+}
+
+func (r *memoryWebSubRepo) FindByID(ctx context.Context, id uint) (*domain.WebSubSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sub, ok := r.subs[id]; ok {
+		return &sub, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryWebSubRepo) FindBySourceID(ctx context.Context, sourceID uint) (*domain.WebSubSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subs {
+		if sub.SourceID == sourceID {
+			return &sub, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryWebSubRepo) ListAll(ctx context.Context) ([]domain.WebSubSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.WebSubSubscription
+	for _, sub := range r.subs {
+		out = append(out, sub)
+	}
+	return out, nil
+}
+
+func (r *memoryWebSubRepo) ListExpiringBefore(ctx context.Context, date time.Time) ([]domain.WebSubSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.WebSubSubscription
+	for _, sub := range r.subs {
+		if sub.State == domain.WebSubActive && sub.ExpiresAt != nil && sub.ExpiresAt.Before(date) {
+			out = append(out, sub)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryWebSubRepo) Save(ctx context.Context, sub *domain.WebSubSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sub.ID == 0 {
+		sub.ID = uint(len(r.subs) + 1)
+	}
+	r.subs[sub.ID] = *sub
+	return nil
+}
+
+// stubSourceRepo solo implementa FindByID
+type stubSourceRepo struct {
+	domain.NewsSourceRepository
+	sources map[uint]*domain.NewsSource
+}
+
+func (r stubSourceRepo) FindByID(ctx context.Context, id uint) (*domain.NewsSource, error) {
+	return r.sources[id], nil
+}
+
+// fakeHub simula un hub WebSub que verifica la intención de forma síncrona antes de aceptar
+type fakeHub struct {
+	t        *testing.T
+	manager  func() domain.WebSubManager
+	mu       sync.Mutex
+	requests []map[string]string
+	lease    int
+	deny     bool
+}
+
+func (h *fakeHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	form := map[string]string{}
+	for key := range r.PostForm {
+		form[key] = r.PostForm.Get(key)
+	}
+	h.mu.Lock()
+	h.requests = append(h.requests, form)
+	h.mu.Unlock()
+
+	callback := form["hub.callback"]
+	id, err := strconv.Atoi(callback[strings.LastIndex(callback, "/")+1:])
+	if err != nil || !strings.HasPrefix(callback, "https://dailynews.example/websub/callback/") {
+		h.t.Errorf("hub.callback inesperado: %q", callback)
+		http.Error(w, "callback", http.StatusBadRequest)
+		return
+	}
+
+	mode, reason := "subscribe", ""
+	if h.deny {
+		mode, reason = "denied", "topic no permitido"
+	}
+	challenge, err := h.manager().Verify(r.Context(), uint(id), mode, form["hub.topic"], "reto-123", h.lease, reason)
+	if err != nil {
+		h.t.Errorf("Verify: %v", err)
+	} else if mode == "subscribe" && challenge != "reto-123" {
+		h.t.Errorf("challenge = %q", challenge)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func webSubFeed(hub, self string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+<title>Feed con hub</title>
+<atom:link rel="hub" href="%s"/>
+<atom:link rel="self" href="%s"/>
+<item><title>Noticia empujada por el hub</title><link>https://example.com/1</link>
+<atom:link rel="hub" href="https://otro-hub.example/"/></item>
+</channel></rss>`, hub, self)
+}
+
+func TestWebSubSubscribeVerifyReceiveRenew(t *testing.T) {
+	var manager domain.WebSubManager
+	hub := &fakeHub{t: t, manager: func() domain.WebSubManager { return manager }, lease: 3600}
+	hubServer := httptest.NewServer(hub)
+	defer hubServer.Close()
+
+	feedRequests := 0
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		feedRequests++
+		w.Write([]byte(webSubFeed(hubServer.URL+"/", "https://feeds.example/rss")))
+	}))
+	defer feedServer.Close()
+
+	repo := newMemoryWebSubRepo()
+	source := &domain.NewsSource{ID: 42, RSSURL: feedServer.URL + "/rss"}
+	sources := stubSourceRepo{sources: map[uint]*domain.NewsSource{42: source}}
+	manager = NewWebSubManager(repo, sources, nil, "https://dailynews.example/", 0)
+	ctx := context.Background()
+
+	// Suscripción: el hub verifica la intención y la suscripción queda activa con su lease
+	if err := manager.EnsureSubscribed(ctx, source); err != nil {
+		t.Fatalf("EnsureSubscribed: %v", err)
+	}
+	sub, _ := repo.FindBySourceID(ctx, 42)
+	if sub == nil || sub.State != domain.WebSubActive {
+		t.Fatalf("suscripción = %+v, want active", sub)
+	}
+	if sub.Hub != hubServer.URL+"/" || sub.Topic != "https://feeds.example/rss" || len(sub.Secret) != 64 {
+		t.Errorf("hub/topic/secret = %q, %q, %d chars", sub.Hub, sub.Topic, len(sub.Secret))
+	}
+	if sub.LeaseSeconds != 3600 || sub.ExpiresAt == nil || time.Until(*sub.ExpiresAt) > time.Hour {
+		t.Errorf("lease = %d, expires = %v", sub.LeaseSeconds, sub.ExpiresAt)
+	}
+	first := hub.requests[0]
+	if first["hub.mode"] != "subscribe" || first["hub.secret"] != sub.Secret || first["hub.lease_seconds"] != strconv.Itoa(int(webSubDefaultLease.Seconds())) {
+		t.Errorf("petición al hub = %v", first)
+	}
+
+	// Una suscripción vigente no vuelve a consultar el feed
+	if err := manager.EnsureSubscribed(ctx, source); err != nil {
+		t.Fatalf("EnsureSubscribed vigente: %v", err)
+	}
+	if feedRequests != 1 || len(hub.requests) != 1 {
+		t.Errorf("feed requests = %d, hub requests = %d; want 1, 1", feedRequests, len(hub.requests))
+	}
+
+	// Contenido firmado: se acepta y queda en la caché con la clave del fetcher
+	body := []byte(webSubFeed(hubServer.URL, "https://feeds.example/rss"))
+	sourceID, pushCtx, err := manager.Receive(ctx, sub.ID, body, "sha256="+signWebSub(sha256.New, sub.Secret, body))
+	if err != nil || sourceID != 42 {
+		t.Fatalf("Receive = %d, %v", sourceID, err)
+	}
+	feed, hit, err := feedcache.FromContext(pushCtx).GetOrLoad(source.RSSURL, nil)
+	if err != nil || !hit || len(feed.Items) != 1 {
+		t.Errorf("feed en caché = %v, %v, %v", feed, hit, err)
+	}
+	if stored, _ := repo.FindByID(ctx, sub.ID); stored.LastPushAt == nil {
+		t.Error("Receive debería registrar LastPushAt")
+	}
+
+	if _, _, err := manager.Receive(ctx, sub.ID, body, "sha256="+signWebSub(sha256.New, "otra", body)); !errors.Is(err, domain.ErrWebSubSignature) {
+		t.Errorf("firma inválida: err = %v", err)
+	}
+	if _, _, err := manager.Receive(ctx, 999, body, ""); !errors.Is(err, domain.ErrWebSubNotFound) {
+		t.Errorf("suscripción inexistente: err = %v", err)
+	}
+
+	// Renovación: el lease de 1h cae dentro del margen y se vuelve a pedir la suscripción
+	if err := manager.RenewExpiring(ctx); err != nil {
+		t.Fatalf("RenewExpiring: %v", err)
+	}
+	if len(hub.requests) != 2 || hub.requests[1]["hub.callback"] != first["hub.callback"] || hub.requests[1]["hub.secret"] != sub.Secret {
+		t.Errorf("renovación = %v", hub.requests)
+	}
+	if renewed, _ := repo.FindByID(ctx, sub.ID); renewed.State != domain.WebSubActive || !renewed.ExpiresAt.After(*sub.ExpiresAt) {
+		t.Errorf("tras renovar = %+v", renewed)
+	}
+
+	// Un topic distinto no corresponde a la suscripción
+	if _, err := manager.Verify(ctx, sub.ID, "subscribe", "https://otro.example/rss", "x", 0, ""); !errors.Is(err, domain.ErrWebSubNotFound) {
+		t.Errorf("topic distinto: err = %v", err)
+	}
+}
+
+func TestWebSubDeniedAndUnsupported(t *testing.T) {
+	var manager domain.WebSubManager
+	hub := &fakeHub{t: t, manager: func() domain.WebSubManager { return manager }, deny: true}
+	hubServer := httptest.NewServer(hub)
+	defer hubServer.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/con-hub", func(w http.ResponseWriter, r *http.Request) {
+		// El hub anunciado en la cabecera Link tiene preferencia sobre el del feed
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="hub", <https://feeds.example/cabecera>; rel="self"`, hubServer.URL))
+		w.Write([]byte(webSubFeed("https://ignorado.example/", "https://feeds.example/ignorado")))
+	})
+	mux.HandleFunc("/sin-hub", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<rss version="2.0"><channel><title>Sin hub</title></channel></rss>`))
+	})
+	feedServer := httptest.NewServer(mux)
+	defer feedServer.Close()
+
+	repo := newMemoryWebSubRepo()
+	manager = NewWebSubManager(repo, stubSourceRepo{}, nil, "https://dailynews.example", time.Hour)
+	ctx := context.Background()
+
+	if err := manager.EnsureSubscribed(ctx, &domain.NewsSource{ID: 1, RSSURL: feedServer.URL + "/con-hub"}); err != nil {
+		t.Fatalf("EnsureSubscribed: %v", err)
+	}
+	denied, _ := repo.FindBySourceID(ctx, 1)
+	if denied.State != domain.WebSubDenied || denied.LastError != "topic no permitido" || denied.Topic != "https://feeds.example/cabecera" {
+		t.Errorf("suscripción rechazada = %+v", denied)
+	}
+
+	if err := manager.EnsureSubscribed(ctx, &domain.NewsSource{ID: 2, RSSURL: feedServer.URL + "/sin-hub"}); err != nil {
+		t.Fatalf("EnsureSubscribed sin hub: %v", err)
+	}
+	if unsupported, _ := repo.FindBySourceID(ctx, 2); unsupported.State != domain.WebSubUnsupported {
+		t.Errorf("feed sin hub = %+v", unsupported)
+	}
+
+	// Las fuentes que no son RSS se ignoran
+	if err := manager.EnsureSubscribed(ctx, &domain.NewsSource{ID: 3, SourceType: domain.SourceTypeHTML}); err != nil {
+		t.Fatalf("EnsureSubscribed html: %v", err)
+	}
+	if sub, _ := repo.FindBySourceID(ctx, 3); sub != nil {
+		t.Errorf("no debería crearse suscripción para una fuente HTML: %+v", sub)
+	}
+}
+
+func signWebSub(newHash func() hash.Hash, secret string, body []byte) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidWebSubSignature(t *testing.T) {
+	body := []byte("<rss/>")
+	tests := []struct {
+		name      string
+		signature string
+		want      bool
+	}{
+		{"sha1", "sha1=" + signWebSub(sha1.New, "clave", body), true},
+		{"sha256", "sha256=" + signWebSub(sha256.New, "clave", body), true},
+		{"sha384", "sha384=" + signWebSub(sha512.New384, "clave", body), true},
+		{"sha512", "sha512=" + signWebSub(sha512.New, "clave", body), true},
+		{"método en mayúsculas", "SHA256=" + signWebSub(sha256.New, "clave", body), true},
+		{"otra clave", "sha256=" + signWebSub(sha256.New, "otra", body), false},
+		{"método que no coincide", "sha1=" + signWebSub(sha256.New, "clave", body), false},
+		{"método desconocido", "md5=" + signWebSub(sha256.New, "clave", body), false},
+		{"hex inválido", "sha256=zz", false},
+		{"sin método", signWebSub(sha256.New, "clave", body), false},
+		{"vacía", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validWebSubSignature("clave", body, tt.signature); got != tt.want {
+				t.Errorf("validWebSubSignature(%q) = %v, want %v", tt.signature, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		link      string
+		href, rel string
+	}{
+		{`<https://hub.example/>; rel="hub"`, "https://hub.example/", "hub"},
+		{` <https://feeds.example/rss> ; REL=self`, "https://feeds.example/rss", "self"},
+		{`<https://a.example/>; type="text/html"; rel="alternate self"`, "https://a.example/", "alternate self"},
+		{`<https://a.example/>`, "https://a.example/", ""},
+	}
+	for _, tt := range tests {
+		href, rel := parseLinkHeader(tt.link)
+		if href != tt.href || rel != tt.rel {
+			t.Errorf("parseLinkHeader(%q) = %q, %q; want %q, %q", tt.link, href, rel, tt.href, tt.rel)
+		}
+	}
+
+	if !hasRel("alternate self", "self") || !hasRel("HUB", "hub") || hasRel("selfish", "self") || hasRel("", "hub") {
+		t.Error("hasRel no compara los valores de rel por palabras")
+	}
+}
+
+func TestWebSubLinks(t *testing.T) {
+	base, _ := url.Parse("https://feeds.example/noticias/rss")
+	tests := []struct {
+		name      string
+		header    http.Header
+		body      string
+		hub, self string
+	}{
+		{"en el feed", nil, webSubFeed("https://hub.example/", "https://feeds.example/rss"), "https://hub.example/", "https://feeds.example/rss"},
+		{"la cabecera Link manda", http.Header{"Link": {`<https://hub-cabecera.example/>; rel="hub", </rss>; rel="self"`}},
+			webSubFeed("https://hub.example/", "https://feeds.example/otro"), "https://hub-cabecera.example/", "https://feeds.example/rss"},
+		{"relativos", nil, `<feed xmlns="http://www.w3.org/2005/Atom"><link rel="hub" href="/hub"/><link rel="self" href="atom"/></feed>`,
+			"https://feeds.example/hub", "https://feeds.example/noticias/atom"},
+		{"solo dentro de un item", nil, `<rss><channel><item><atom:link rel="hub" href="https://hub.example/"/></item></channel></rss>`, "", ""},
+		{"no es XML", nil, "no es un feed", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub, self := webSubLinks(tt.header, base, strings.NewReader(tt.body))
+			if hub != tt.hub || self != tt.self {
+				t.Errorf("webSubLinks = %q, %q; want %q, %q", hub, self, tt.hub, tt.self)
+			}
+		})
+	}
+}
+
+func TestWebSubDiscoverHubReusesFetchedFeed(t *testing.T) {
+	var manager domain.WebSubManager
+	hub := &fakeHub{t: t, manager: func() domain.WebSubManager { return manager }, lease: 3600}
+	hubServer := httptest.NewServer(hub)
+	defer hubServer.Close()
+
+	feedRequests := 0
+	feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		feedRequests++
+		w.Header().Add("Link", "<"+hubServer.URL+`/>; rel="hub"`)
+		w.Write([]byte(webSubFeed("https://otro-hub.example/", "https://feeds.example/rss")))
+	}))
+	defer feedServer.Close()
+
+	repo := newMemoryWebSubRepo()
+	source := &domain.NewsSource{ID: 42, RSSURL: feedServer.URL + "/rss"}
+	manager = NewWebSubManager(repo, stubSourceRepo{sources: map[uint]*domain.NewsSource{42: source}}, nil, "https://dailynews.example/", 0)
+
+	// La extracción descarga el feed una vez y la detección del hub lo toma de la caché de la extracción
+	ctx := feedcache.NewContext(context.Background(), feedcache.New(0))
+	fetcher := NewRSSFetcher(nil, 0, 0, 0, 0)
+	if _, err := fetcher.Fetch(ctx, source.RSSURL, "patron1_no_image", "title", "", "link", "pubDate"); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if err := manager.EnsureSubscribed(ctx, source); err != nil {
+		t.Fatalf("EnsureSubscribed: %v", err)
+	}
+	if feedRequests != 1 {
+		t.Errorf("feed descargado %d veces, want 1", feedRequests)
+	}
+	if sub, _ := repo.FindBySourceID(ctx, 42); sub == nil || sub.Hub != hubServer.URL+"/" || sub.State != domain.WebSubActive {
+		t.Errorf("suscripción = %+v, want hub de la cabecera Link", sub)
+	}
+}
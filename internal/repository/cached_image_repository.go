@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"dailynews/internal/domain"
+)
+
+type cachedImageRepository struct {
+	db *gorm.DB
+}
+
+// NewCachedImageRepository crea una nueva instancia de CachedImageRepository
+func NewCachedImageRepository(db *gorm.DB) domain.CachedImageRepository {
+	return &cachedImageRepository{db: db}
+}
+
+// FindByURLHash busca la imagen cacheada de una URL (nil si no está en caché)
+func (r *cachedImageRepository) FindByURLHash(ctx context.Context, urlHash string) (*domain.CachedImage, error) {
+	var image domain.CachedImage
+	err := r.db.WithContext(ctx).Where("url_hash = ?", urlHash).First(&image).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+// Save crea el registro si no existe (ID 0) o lo actualiza
+func (r *cachedImageRepository) Save(ctx context.Context, image *domain.CachedImage) error {
+	if image == nil {
+		return errors.New("la imagen no puede ser nil")
+	}
+	return r.db.WithContext(ctx).Save(image).Error
+}
+
+// Delete elimina el registro de una imagen cacheada (el archivo lo borra la caché)
+func (r *cachedImageRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.CachedImage{}, id).Error
+}
+
+// CountByContentHash cuenta las URLs que comparten el mismo archivo
+func (r *cachedImageRepository) CountByContentHash(ctx context.Context, contentHash string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.CachedImage{}).Where("content_hash = ?", contentHash).Count(&count).Error
+	return count, err
+}
+
+// ListUnreferenced devuelve las imágenes cuya URL local no usa ninguna noticia, por uso más antiguo
+func (r *cachedImageRepository) ListUnreferenced(ctx context.Context) ([]domain.CachedImage, error) {
+	var images []domain.CachedImage
+	used := r.db.Model(&domain.NewsItem{}).Select("image")
+	err := r.db.WithContext(ctx).
+		Where("local_url NOT IN (?)", used).
+		Order("last_used_at ASC").
+		Find(&images).Error
+	return images, err
+}
+
+// TotalBytes suma el tamaño de los archivos de la caché contando una vez cada contenido
+func (r *cachedImageRepository) TotalBytes(ctx context.Context) (int64, error) {
+	var total int64
+	files := r.db.Model(&domain.CachedImage{}).Select("MAX(bytes) AS bytes").Group("content_hash")
+	err := r.db.WithContext(ctx).Table("(?) AS files", files).Select("COALESCE(SUM(bytes), 0)").Scan(&total).Error
+	return total, err
+}
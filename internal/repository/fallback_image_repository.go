@@ -24,9 +24,16 @@ func (r *fallbackImageRepository) Create(ctx context.Context, image *domain.Fall
 	return r.db.WithContext(ctx).Create(image).Error
 }
 
+// shared limita la consulta a las imágenes compartidas: sin fuente propietaria y sin ninguna fuente
+// que las tenga como FallbackImageID (las subidas para una fuente antes de existir SourceID)
+func (r *fallbackImageRepository) shared(ctx context.Context) *gorm.DB {
+	owned := r.db.Model(&domain.NewsSource{}).Select("fallback_image_id").Where("fallback_image_id IS NOT NULL")
+	return r.db.WithContext(ctx).Where("source_id IS NULL AND id NOT IN (?)", owned)
+}
+
 func (r *fallbackImageRepository) GetByCategoryAndLang(ctx context.Context, categoryCode, languageCode string) (*domain.FallbackImage, error) {
 	var image domain.FallbackImage
-	err := r.db.WithContext(ctx).
+	err := r.shared(ctx).
 		Where("category_code = ? AND language_code = ?", categoryCode, languageCode).
 		First(&image).Error
 
@@ -49,7 +56,7 @@ func (r *fallbackImageRepository) Update(ctx context.Context, image *domain.Fall
 }
 
 func (r *fallbackImageRepository) Delete(ctx context.Context, categoryCode, languageCode string) error {
-	return r.db.WithContext(ctx).
+	return r.shared(ctx).
 		Where("category_code = ? AND language_code = ?", categoryCode, languageCode).
 		Delete(&domain.FallbackImage{}).Error
 }
@@ -60,6 +67,27 @@ func (r *fallbackImageRepository) ListAll(ctx context.Context) ([]domain.Fallbac
 	return images, err
 }
 
+// ListForFallback devuelve las imágenes de la fuente y las compartidas de su categoría+idioma,
+// de su categoría y globales, ordenadas por ID para que el reparto entre noticias sea estable
+func (r *fallbackImageRepository) ListForFallback(ctx context.Context, sourceID uint, categoryCode, languageCode string) ([]domain.FallbackImage, error) {
+	var images []domain.FallbackImage
+	sourceImage := r.db.Model(&domain.NewsSource{}).Select("fallback_image_id").Where("id = ?", sourceID)
+	sharedIDs := r.shared(ctx).Model(&domain.FallbackImage{}).Select("id").
+		Where("category_code IN (?, '') AND language_code IN (?, '')", categoryCode, languageCode)
+	err := r.db.WithContext(ctx).
+		Where("source_id = ? OR id IN (?) OR id IN (?)", sourceID, sourceImage, sharedIDs).
+		Order("id").
+		Find(&images).Error
+	return images, err
+}
+
+// ListBySource devuelve las imágenes propias de la fuente
+func (r *fallbackImageRepository) ListBySource(ctx context.Context, sourceID uint) ([]domain.FallbackImage, error) {
+	var images []domain.FallbackImage
+	err := r.db.WithContext(ctx).Where("source_id = ?", sourceID).Order("id").Find(&images).Error
+	return images, err
+}
+
 // GetByID obtiene una imagen fallback por su ID
 func (r *fallbackImageRepository) GetByID(ctx context.Context, id uint) (*domain.FallbackImage, error) {
 	var image domain.FallbackImage
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"dailynews/internal/domain"
+)
+
+type httpProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewHTTPProfileRepository crea una nueva instancia de HTTPProfileRepository
+func NewHTTPProfileRepository(db *gorm.DB) domain.HTTPProfileRepository {
+	return &httpProfileRepository{db: db}
+}
+
+// FindByID busca un perfil HTTP por su ID (nil si no existe)
+func (r *httpProfileRepository) FindByID(ctx context.Context, id uint) (*domain.HTTPProfile, error) {
+	var profile domain.HTTPProfile
+	err := r.db.WithContext(ctx).First(&profile, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ListAll devuelve todos los perfiles HTTP ordenados por nombre
+func (r *httpProfileRepository) ListAll(ctx context.Context) ([]domain.HTTPProfile, error) {
+	var profiles []domain.HTTPProfile
+	err := r.db.WithContext(ctx).Order("name").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *httpProfileRepository) Create(ctx context.Context, profile *domain.HTTPProfile) error {
+	if profile == nil {
+		return errors.New("el perfil no puede ser nil")
+	}
+	return r.db.WithContext(ctx).Create(profile).Error
+}
+
+func (r *httpProfileRepository) Update(ctx context.Context, profile *domain.HTTPProfile) error {
+	if profile == nil {
+		return errors.New("el perfil no puede ser nil")
+	}
+	return r.db.WithContext(ctx).Save(profile).Error
+}
+
+// Delete desasocia el perfil de sus fuentes y lo elimina en una transacción
+func (r *httpProfileRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.NewsSource{}).
+			Where("http_profile_id = ?", id).
+			Update("http_profile_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.HTTPProfile{}, id).Error
+	})
+}
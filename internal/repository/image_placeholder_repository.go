@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"dailynews/internal/domain"
+)
+
+type imagePlaceholderRepository struct {
+	db *gorm.DB
+}
+
+// NewImagePlaceholderRepository crea una nueva instancia de ImagePlaceholderRepository
+func NewImagePlaceholderRepository(db *gorm.DB) domain.ImagePlaceholderRepository {
+	return &imagePlaceholderRepository{db: db}
+}
+
+// ListBySource devuelve las imágenes genéricas de una fuente
+func (r *imagePlaceholderRepository) ListBySource(ctx context.Context, sourceID uint) ([]domain.ImagePlaceholder, error) {
+	var placeholders []domain.ImagePlaceholder
+	err := r.db.WithContext(ctx).
+		Where("source_id = ?", sourceID).
+		Order("created_at DESC").
+		Find(&placeholders).Error
+	return placeholders, err
+}
+
+// Save crea o actualiza una imagen genérica
+func (r *imagePlaceholderRepository) Save(ctx context.Context, placeholder *domain.ImagePlaceholder) error {
+	if placeholder == nil {
+		return errors.New("la imagen genérica no puede ser nil")
+	}
+	if placeholder.SourceID == 0 || placeholder.Hash == "" {
+		return errors.New("la imagen genérica necesita fuente y hash")
+	}
+	if placeholder.ID == 0 {
+		// El mismo hash ya registrado para la fuente se reutiliza en vez de duplicarlo
+		var existing domain.ImagePlaceholder
+		err := r.db.WithContext(ctx).Where("source_id = ? AND hash = ?", placeholder.SourceID, placeholder.Hash).First(&existing).Error
+		if err == nil {
+			placeholder.ID = existing.ID
+			placeholder.CreatedAt = existing.CreatedAt
+			placeholder.Hits = existing.Hits
+		}
+	}
+	return r.db.WithContext(ctx).Save(placeholder).Error
+}
+
+// Delete elimina una imagen genérica
+func (r *imagePlaceholderRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.ImagePlaceholder{}, id).Error
+}
+
+// RecordSighting anota la imagen vista en una noticia de la fuente (una vez por link y hash) y borra
+// las más antiguas de la fuente a partir de las keep más recientes
+func (r *imagePlaceholderRepository) RecordSighting(ctx context.Context, sighting *domain.ImageHashSighting, keep int) error {
+	if sighting == nil || sighting.SourceID == 0 || sighting.LinkHash == "" || sighting.Hash == "" {
+		return errors.New("la imagen vista necesita fuente, link y hash")
+	}
+	sighting.SeenAt = time.Now()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing domain.ImageHashSighting
+		err := tx.Where("source_id = ? AND link_hash = ? AND hash = ?", sighting.SourceID, sighting.LinkHash, sighting.Hash).
+			First(&existing).Error
+		switch {
+		case err == nil:
+			sighting.ID = existing.ID
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+		if err := tx.Save(sighting).Error; err != nil {
+			return err
+		}
+		if keep <= 0 {
+			return nil
+		}
+
+		var cutoff []time.Time
+		if err := tx.Model(&domain.ImageHashSighting{}).
+			Where("source_id = ?", sighting.SourceID).
+			Order("seen_at DESC").
+			Offset(keep).
+			Limit(1).
+			Pluck("seen_at", &cutoff).Error; err != nil {
+			return err
+		}
+		if len(cutoff) == 0 {
+			return nil
+		}
+		return tx.Where("source_id = ? AND seen_at <= ?", sighting.SourceID, cutoff[0]).
+			Delete(&domain.ImageHashSighting{}).Error
+	})
+}
+
+// RecentSightings devuelve los hashes de las últimas imágenes vistas en noticias de la fuente
+func (r *imagePlaceholderRepository) RecentSightings(ctx context.Context, sourceID uint, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.WithContext(ctx).Model(&domain.ImageHashSighting{}).
+		Where("source_id = ?", sourceID).
+		Order("seen_at DESC").
+		Limit(limit).
+		Pluck("hash", &hashes).Error
+	return hashes, err
+}
+
+// IncrementHits suma un descarte a la imagen genérica
+func (r *imagePlaceholderRepository) IncrementHits(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&domain.ImagePlaceholder{}).
+		Where("id = ?", id).
+		UpdateColumn("hits", gorm.Expr("hits + ?", 1)).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"dailynews/internal/domain"
+)
+
+type imageValidationRepository struct {
+	db *gorm.DB
+}
+
+// NewImageValidationRepository crea una nueva instancia de ImageValidationRepository
+func NewImageValidationRepository(db *gorm.DB) domain.ImageValidationRepository {
+	return &imageValidationRepository{db: db}
+}
+
+// FindByURLHash busca la validación guardada de una URL (nil si no se ha validado)
+func (r *imageValidationRepository) FindByURLHash(ctx context.Context, urlHash string) (*domain.ImageValidation, error) {
+	var validation domain.ImageValidation
+	err := r.db.WithContext(ctx).Where("url_hash = ?", urlHash).First(&validation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &validation, nil
+}
+
+// Save crea o actualiza la validación de una URL
+func (r *imageValidationRepository) Save(ctx context.Context, validation *domain.ImageValidation) error {
+	if validation == nil {
+		return errors.New("la validación no puede ser nil")
+	}
+	if validation.ID == 0 {
+		// Otra extracción pudo guardarla mientras tanto: se reutiliza su registro
+		if existing, err := r.FindByURLHash(ctx, validation.URLHash); err == nil && existing != nil {
+			validation.ID = existing.ID
+		}
+	}
+	return r.db.WithContext(ctx).Save(validation).Error
+}
+
+// Delete elimina la validación de una URL
+func (r *imageValidationRepository) Delete(ctx context.Context, urlHash string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("url_hash = ?", urlHash).Delete(&domain.ImageValidation{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteAll elimina todas las validaciones, o solo las de imágenes rechazadas
+func (r *imageValidationRepository) DeleteAll(ctx context.Context, onlyInvalid bool) (int64, error) {
+	query := r.db.WithContext(ctx).Where("1 = 1")
+	if onlyInvalid {
+		query = r.db.WithContext(ctx).Where("valid = ?", false)
+	}
+	result := query.Delete(&domain.ImageValidation{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteCheckedBefore elimina las validaciones comprobadas antes de la fecha indicada
+func (r *imageValidationRepository) DeleteCheckedBefore(ctx context.Context, date time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("checked_at < ?", date).Delete(&domain.ImageValidation{})
+	return result.RowsAffected, result.Error
+}
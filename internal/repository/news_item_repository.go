@@ -8,6 +8,7 @@ import (
 	"dailynews/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type newsItemRepository struct {
@@ -234,7 +235,19 @@ func (r *newsItemRepository) GetByCategory(ctx context.Context, category, lang s
 	return items, nil
 }
 
-// SearchByTitle busca noticias por título con filtros opcionales
+// searchCondition busca el término en el título y en el texto completo de la noticia
+const searchCondition = "(title LIKE ? OR content_text LIKE ?)"
+
+// searchRelevanceOrder ordena primero las noticias cuyo título contiene el término y después por fecha
+func searchRelevanceOrder(query string) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "title LIKE ? DESC, pub_date DESC",
+		Vars:               []interface{}{"%" + query + "%"},
+		WithoutParentheses: true,
+	}}
+}
+
+// SearchByTitle busca noticias por título o texto completo con filtros opcionales
 func (r *newsItemRepository) SearchByTitle(ctx context.Context, query, lang, category string, limit, offset int) ([]domain.NewsItem, error) {
 	if query == "" {
 		return nil, errors.New("el término de búsqueda es requerido")
@@ -250,9 +263,9 @@ func (r *newsItemRepository) SearchByTitle(ctx context.Context, query, lang, cat
 		offset = 0
 	}
 
-	// Construir query base
+	// Construir query base (título o texto completo de la noticia si se extrajo)
 	dbQuery := r.db.WithContext(ctx).
-		Where("title LIKE ?", "%"+query+"%").
+		Where(searchCondition, "%"+query+"%", "%"+query+"%").
 		Preload("Source")
 
 	// Aplicar filtros opcionales
@@ -265,7 +278,7 @@ func (r *newsItemRepository) SearchByTitle(ctx context.Context, query, lang, cat
 
 	var items []domain.NewsItem
 	err := dbQuery.
-		Order("pub_date DESC").
+		Order(searchRelevanceOrder(query)).
 		Limit(limit).
 		Offset(offset).
 		Find(&items).Error
@@ -277,6 +290,16 @@ func (r *newsItemRepository) SearchByTitle(ctx context.Context, query, lang, cat
 	return items, nil
 }
 
+// ExistsByLink indica si ya hay una noticia guardada con ese link
+func (r *newsItemRepository) ExistsByLink(ctx context.Context, link string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.NewsItem{}).
+		Where("link = ?", link).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // CountTotal cuenta el total de noticias para un idioma
 func (r *newsItemRepository) CountTotal(ctx context.Context, lang string) (int, error) {
 	if lang == "" {
@@ -316,7 +339,7 @@ func (r *newsItemRepository) CountSearchResults(ctx context.Context, query, lang
 	// Construir query base
 	dbQuery := r.db.WithContext(ctx).
 		Model(&domain.NewsItem{}).
-		Where("title LIKE ?", "%"+query+"%")
+		Where(searchCondition, "%"+query+"%", "%"+query+"%")
 
 	// Aplicar filtros opcionales
 	if lang != "" {
@@ -373,15 +396,21 @@ func (r *newsItemRepository) GetFilteredNews(ctx context.Context, filters domain
 		dbQuery = dbQuery.Where("pub_date <= ?", *filters.DateTo)
 	}
 	if filters.Search != "" {
-		dbQuery = dbQuery.Where("title LIKE ?", "%"+filters.Search+"%")
+		dbQuery = dbQuery.Where(searchCondition, "%"+filters.Search+"%", "%"+filters.Search+"%")
 	}
 	if len(filters.ExcludeCategories) > 0 {
 		dbQuery = dbQuery.Where("category_code NOT IN ?", filters.ExcludeCategories)
 	}
 
+	// Con búsqueda, las coincidencias en el título van antes que las del texto completo
+	var order interface{} = "pub_date DESC"
+	if filters.Search != "" {
+		order = searchRelevanceOrder(filters.Search)
+	}
+
 	var items []domain.NewsItem
 	err := dbQuery.
-		Order("pub_date DESC").
+		Order(order).
 		Limit(limit).
 		Offset(offset).
 		Find(&items).Error
@@ -420,7 +449,7 @@ func (r *newsItemRepository) CountFilteredNews(ctx context.Context, filters doma
 		dbQuery = dbQuery.Where("pub_date <= ?", *filters.DateTo)
 	}
 	if filters.Search != "" {
-		dbQuery = dbQuery.Where("title LIKE ?", "%"+filters.Search+"%")
+		dbQuery = dbQuery.Where(searchCondition, "%"+filters.Search+"%", "%"+filters.Search+"%")
 	}
 	if len(filters.ExcludeCategories) > 0 {
 		dbQuery = dbQuery.Where("category_code NOT IN ?", filters.ExcludeCategories)
@@ -431,3 +460,22 @@ func (r *newsItemRepository) CountFilteredNews(ctx context.Context, filters doma
 
 	return int(count), err
 }
+
+// ImagesWithPrefix devuelve las imágenes distintas de las noticias guardadas que empiezan por prefix
+func (r *newsItemRepository) ImagesWithPrefix(ctx context.Context, prefix string) ([]string, error) {
+	var images []string
+	err := r.db.WithContext(ctx).
+		Model(&domain.NewsItem{}).
+		Where("image LIKE ?", prefix+"%").
+		Distinct().
+		Pluck("image", &images).Error
+	return images, err
+}
+
+// Update guarda los cambios de una noticia existente
+func (r *newsItemRepository) Update(ctx context.Context, item *domain.NewsItem) error {
+	if item == nil || item.ID == 0 {
+		return errors.New("la noticia a actualizar no es válida")
+	}
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(item).Error
+}
@@ -32,6 +32,7 @@ func (r *newsSourceRepository) FindByID(ctx context.Context, id uint) (*domain.N
 	err := r.db.WithContext(ctx).
 		Preload("News").
 		Preload("Lang").
+		Preload("HTTPProfile").
 		First(&source, id).Error
 
 	if err != nil {
@@ -59,6 +60,7 @@ func (r *newsSourceRepository) FindActiveByLangAndCategory(
 		Where("lang_id = ? AND news_id = ? AND is_active = ?", langID, categoryID, true).
 		Preload("News").
 		Preload("Lang").
+		Preload("HTTPProfile").
 		Find(&sources).Error
 
 	if err != nil {
@@ -76,6 +78,7 @@ func (r *newsSourceRepository) ListActive(ctx context.Context) ([]domain.NewsSou
 		Where("is_active = ?", true).
 		Preload("News").
 		Preload("Lang").
+		Preload("HTTPProfile").
 		Find(&sources).Error
 
 	if err != nil {
@@ -92,6 +95,7 @@ func (r *newsSourceRepository) ListAll(ctx context.Context) ([]domain.NewsSource
 	err := r.db.WithContext(ctx).
 		Preload("News").
 		Preload("Lang").
+		Preload("HTTPProfile").
 		Find(&sources).Error
 
 	if err != nil {
@@ -145,7 +149,8 @@ func (r *newsSourceRepository) Update(ctx context.Context, source *domain.NewsSo
 		"user_added":  source.UserAdded,
 	})
 
-	err := r.db.WithContext(ctx).Save(source).Error
+	// El perfil HTTP se gestiona en su propio repositorio: solo se guarda HTTPProfileID
+	err := r.db.WithContext(ctx).Omit("HTTPProfile").Save(source).Error
 
 	if err != nil {
 		utils.AppError("REPOSITORY_UPDATE", "Error al actualizar fuente", err, map[string]interface{}{
@@ -166,24 +171,42 @@ func (r *newsSourceRepository) Delete(ctx context.Context, id uint) error {
 		"id": id,
 	})
 
-	// Primero eliminar las noticias asociadas a esta fuente
-	if err := r.db.Where("source_id = ?", id).Delete(&domain.NewsItem{}).Error; err != nil {
-		utils.AppError("REPOSITORY_DELETE", "Error al eliminar noticias asociadas", err, map[string]interface{}{
+	// La fuente y todo lo que cuelga de ella se borra en una transacción: no quedan registros huérfanos
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Primero eliminar las noticias asociadas a esta fuente
+		if err := tx.Where("source_id = ?", id).Delete(&domain.NewsItem{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar noticias asociadas: %w", err)
+		}
+
+		utils.AppInfo("REPOSITORY_DELETE", "Noticias asociadas eliminadas", map[string]interface{}{
 			"id": id,
 		})
-		return fmt.Errorf("error al eliminar noticias asociadas: %w", err)
-	}
 
-	utils.AppInfo("REPOSITORY_DELETE", "Noticias asociadas eliminadas", map[string]interface{}{
-		"id": id,
-	})
+		// El registro de salud, la suscripción WebSub y las imágenes genéricas dejan de tener sentido
+		if err := tx.Where("source_id = ?", id).Delete(&domain.SourceHealth{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar salud de la fuente: %w", err)
+		}
+		if err := tx.Where("source_id = ?", id).Delete(&domain.WebSubSubscription{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar suscripción WebSub de la fuente: %w", err)
+		}
+		if err := tx.Where("source_id = ?", id).Delete(&domain.ImagePlaceholder{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar imágenes genéricas de la fuente: %w", err)
+		}
+		if err := tx.Where("source_id = ?", id).Delete(&domain.ImageHashSighting{}).Error; err != nil {
+			return fmt.Errorf("error al eliminar historial de imágenes de la fuente: %w", err)
+		}
 
-	// Luego eliminar la fuente
-	if err := r.db.Delete(&domain.NewsSource{}, id).Error; err != nil {
+		// Luego eliminar la fuente
+		if err := tx.Delete(&domain.NewsSource{}, id).Error; err != nil {
+			return fmt.Errorf("error al eliminar fuente: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		utils.AppError("REPOSITORY_DELETE", "Error al eliminar fuente", err, map[string]interface{}{
 			"id": id,
 		})
-		return fmt.Errorf("error al eliminar fuente: %w", err)
+		return err
 	}
 
 	utils.AppInfo("REPOSITORY_DELETE", "Fuente eliminada exitosamente", map[string]interface{}{
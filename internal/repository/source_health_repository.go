@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"dailynews/internal/domain"
+)
+
+type sourceHealthRepository struct {
+	db *gorm.DB
+}
+
+// NewSourceHealthRepository crea una nueva instancia de SourceHealthRepository
+func NewSourceHealthRepository(db *gorm.DB) domain.SourceHealthRepository {
+	return &sourceHealthRepository{db: db}
+}
+
+// FindBySourceID busca el registro de salud de una fuente (nil si aún no se ha extraído)
+func (r *sourceHealthRepository) FindBySourceID(ctx context.Context, sourceID uint) (*domain.SourceHealth, error) {
+	var health domain.SourceHealth
+	err := r.db.WithContext(ctx).Where("source_id = ?", sourceID).First(&health).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &health, nil
+}
+
+// ListAll devuelve la salud de todas las fuentes extraídas alguna vez
+func (r *sourceHealthRepository) ListAll(ctx context.Context) ([]domain.SourceHealth, error) {
+	var healths []domain.SourceHealth
+	err := r.db.WithContext(ctx).Order("source_id").Find(&healths).Error
+	return healths, err
+}
+
+// Save crea el registro si no existe (ID 0) o lo actualiza
+func (r *sourceHealthRepository) Save(ctx context.Context, health *domain.SourceHealth) error {
+	if health == nil {
+		return errors.New("el registro de salud no puede ser nil")
+	}
+	if health.SourceID == 0 {
+		return errors.New("el ID de la fuente no puede ser cero")
+	}
+	return r.db.WithContext(ctx).Save(health).Error
+}
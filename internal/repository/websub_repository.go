@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"dailynews/internal/domain"
+)
+
+type webSubSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewWebSubSubscriptionRepository crea una nueva instancia de WebSubSubscriptionRepository
+func NewWebSubSubscriptionRepository(db *gorm.DB) domain.WebSubSubscriptionRepository {
+	return &webSubSubscriptionRepository{db: db}
+}
+
+// FindByID busca una suscripción por su ID (nil si no existe)
+func (r *webSubSubscriptionRepository) FindByID(ctx context.Context, id uint) (*domain.WebSubSubscription, error) {
+	var subscription domain.WebSubSubscription
+	err := r.db.WithContext(ctx).First(&subscription, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// FindBySourceID busca la suscripción de una fuente (nil si no tiene)
+func (r *webSubSubscriptionRepository) FindBySourceID(ctx context.Context, sourceID uint) (*domain.WebSubSubscription, error) {
+	var subscription domain.WebSubSubscription
+	err := r.db.WithContext(ctx).Where("source_id = ?", sourceID).First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ListAll devuelve todas las suscripciones
+func (r *webSubSubscriptionRepository) ListAll(ctx context.Context) ([]domain.WebSubSubscription, error) {
+	var subscriptions []domain.WebSubSubscription
+	err := r.db.WithContext(ctx).Order("source_id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// ListExpiringBefore devuelve las suscripciones activas que caducan antes de date
+func (r *webSubSubscriptionRepository) ListExpiringBefore(ctx context.Context, date time.Time) ([]domain.WebSubSubscription, error) {
+	var subscriptions []domain.WebSubSubscription
+	err := r.db.WithContext(ctx).
+		Where("state = ? AND expires_at IS NOT NULL AND expires_at < ?", domain.WebSubActive, date).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Save crea la suscripción si no existe (ID 0) o la actualiza
+func (r *webSubSubscriptionRepository) Save(ctx context.Context, subscription *domain.WebSubSubscription) error {
+	if subscription == nil {
+		return errors.New("la suscripción no puede ser nil")
+	}
+	if subscription.SourceID == 0 {
+		return errors.New("el ID de la fuente no puede ser cero")
+	}
+	return r.db.WithContext(ctx).Save(subscription).Error
+}
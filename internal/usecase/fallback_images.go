@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strings"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// Niveles de las imágenes de fallback, del más al menos específico
+const (
+	fallbackLevelSource   = "source"
+	fallbackLevelCategory = "category_lang"
+	fallbackLevelCatOnly  = "category"
+	fallbackLevelGlobal   = "global"
+)
+
+// localFallbackDirs relaciona la ruta pública de las imágenes locales (subidas y generadas) con su directorio
+var localFallbackDirs = map[string]string{
+	"/images/fallback/":  "fallback",
+	"/images/generated/": "generated",
+}
+
+// isLocalFallbackImage indica si la imagen es una de fallback local, que no se valida por HTTP
+func isLocalFallbackImage(imageURL string) bool {
+	for prefix := range localFallbackDirs {
+		if strings.Contains(imageURL, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// localFallbackPath devuelve la ruta en disco de una imagen de fallback local
+func localFallbackPath(projectRoot, imageURL string) string {
+	dir := "fallback"
+	for prefix, prefixDir := range localFallbackDirs {
+		if strings.Contains(imageURL, prefix) {
+			dir = prefixDir
+		}
+	}
+	return filepath.Join(projectRoot, "frontend", "assets", "images", dir, filepath.Base(imageURL))
+}
+
+// getFallbackImage devuelve la imagen de fallback de una noticia sin imagen válida ("" si no hay ninguna).
+// Se usa el primer nivel con imágenes: la fuente, su categoría+idioma, su categoría y las globales.
+// Dentro del nivel la imagen se elige por el hash del enlace de la noticia, para que las fuentes
+// sin imágenes no repitan la misma en todas las tarjetas y cada noticia conserve siempre la suya.
+// Si no hay ninguna subida, el último nivel es una imagen de marca generada con el titular.
+func (uc *FetchNewsUseCase) getFallbackImage(ctx context.Context, source *domain.NewsSource, categoryCode, languageCode, link, title string) string {
+	images, err := uc.fallbackImageRepo.ListForFallback(ctx, source.ID, categoryCode, languageCode)
+	if err != nil {
+		utils.AppWarn("FALLBACK_IMAGE", "Error consultando las imágenes de fallback", map[string]interface{}{
+			"source_id":     source.ID,
+			"category_code": categoryCode,
+			"language_code": languageCode,
+			"error":         err.Error(),
+		})
+	}
+
+	pools := make(map[string][]domain.FallbackImage)
+	for _, image := range images {
+		level := fallbackLevel(source, image, categoryCode, languageCode)
+		if level != "" {
+			pools[level] = append(pools[level], image)
+		}
+	}
+	for _, level := range []string{fallbackLevelSource, fallbackLevelCategory, fallbackLevelCatOnly, fallbackLevelGlobal} {
+		pool := pools[level]
+		if len(pool) == 0 {
+			continue
+		}
+		image := pool[poolIndex(link, len(pool))]
+
+		// Usar URL relativa que funcione en cualquier entorno
+		// Esto evita problemas de protocolo (HTTP vs HTTPS)
+		fallbackURL := fmt.Sprintf("/images/fallback/%s", image.Filename)
+		utils.AppInfo("FALLBACK_IMAGE", "URL de imagen de fallback generada", map[string]interface{}{
+			"source_id":     source.ID,
+			"category_code": categoryCode,
+			"language_code": languageCode,
+			"level":         level,
+			"pool_size":     len(pool),
+			"filename":      image.Filename,
+			"url":           fallbackURL,
+		})
+		return fallbackURL
+	}
+	return uc.generateFallbackImage(ctx, source, categoryCode, title)
+}
+
+// generateFallbackImage devuelve la imagen de marca generada para la noticia ("" si el generador está
+// desactivado o falla)
+func (uc *FetchNewsUseCase) generateFallbackImage(ctx context.Context, source *domain.NewsSource, categoryCode, title string) string {
+	if uc.imageGenerator == nil {
+		return ""
+	}
+	generatedURL, err := uc.imageGenerator.Generate(ctx, source.SourceName, categoryCode, title)
+	if err != nil {
+		utils.AppWarn("GENERATED_IMAGE", "No se pudo generar la imagen de fallback", map[string]interface{}{
+			"source_id":     source.ID,
+			"category_code": categoryCode,
+			"error":         err.Error(),
+		})
+		return ""
+	}
+	return generatedURL
+}
+
+// fallbackLevel devuelve el nivel de la imagen para la fuente ("" si no le corresponde)
+func fallbackLevel(source *domain.NewsSource, image domain.FallbackImage, categoryCode, languageCode string) string {
+	switch {
+	case image.SourceID != nil:
+		if *image.SourceID == source.ID {
+			return fallbackLevelSource
+		}
+		return ""
+	case source.FallbackImageID != nil && *source.FallbackImageID == image.ID:
+		return fallbackLevelSource
+	case image.CategoryCode == categoryCode && image.LanguageCode == languageCode:
+		return fallbackLevelCategory
+	case image.CategoryCode == categoryCode && image.LanguageCode == "":
+		return fallbackLevelCatOnly
+	case image.CategoryCode == "" && image.LanguageCode == "":
+		return fallbackLevelGlobal
+	}
+	return ""
+}
+
+// poolIndex elige de forma determinista una posición del conjunto a partir de la clave
+func poolIndex(key string, size int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(size))
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/config"
+)
+
+// staticImageGenerator devuelve siempre la misma URL (o el mismo error)
+type staticImageGenerator struct {
+	url string
+	err error
+}
+
+func (g *staticImageGenerator) Generate(ctx context.Context, sourceName, categoryCode, title string) (string, error) {
+	return g.url, g.err
+}
+
+func (g *staticImageGenerator) CollectGarbage(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestFallbackLevel(t *testing.T) {
+	own, other, assigned := uint(7), uint(8), uint(20)
+	source := &domain.NewsSource{ID: own, FallbackImageID: &assigned}
+
+	tests := []struct {
+		name  string
+		image domain.FallbackImage
+		want  string
+	}{
+		{"propia de la fuente", domain.FallbackImage{ID: 1, SourceID: &own, CategoryCode: "sports", LanguageCode: "es"}, fallbackLevelSource},
+		{"de otra fuente", domain.FallbackImage{ID: 2, SourceID: &other, CategoryCode: "sports", LanguageCode: "es"}, ""},
+		{"asignada a la fuente", domain.FallbackImage{ID: assigned, CategoryCode: "tech", LanguageCode: "en"}, fallbackLevelSource},
+		{"categoría e idioma", domain.FallbackImage{ID: 3, CategoryCode: "sports", LanguageCode: "es"}, fallbackLevelCategory},
+		{"solo categoría", domain.FallbackImage{ID: 4, CategoryCode: "sports"}, fallbackLevelCatOnly},
+		{"global", domain.FallbackImage{ID: 5}, fallbackLevelGlobal},
+		{"categoría con otro idioma", domain.FallbackImage{ID: 6, CategoryCode: "sports", LanguageCode: "en"}, ""},
+		{"otra categoría", domain.FallbackImage{ID: 7, CategoryCode: "tech"}, ""},
+		{"solo idioma", domain.FallbackImage{ID: 8, LanguageCode: "es"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fallbackLevel(source, tt.image, "sports", "es"); got != tt.want {
+				t.Errorf("fallbackLevel = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoolIndex(t *testing.T) {
+	// Siempre dentro del conjunto y estable para la misma clave
+	for _, size := range []int{1, 2, 3, 10} {
+		for _, key := range []string{"", "https://a.com/1", "https://a.com/2", "https://b.com/noticia?id=3"} {
+			index := poolIndex(key, size)
+			if index < 0 || index >= size {
+				t.Fatalf("poolIndex(%q, %d) = %d fuera del conjunto", key, size, index)
+			}
+			if again := poolIndex(key, size); again != index {
+				t.Errorf("poolIndex(%q, %d) = %d y después %d", key, size, index, again)
+			}
+		}
+	}
+
+	// Noticias distintas se reparten por todo el conjunto
+	counts := make([]int, 4)
+	for i := 0; i < 400; i++ {
+		counts[poolIndex(fmt.Sprintf("https://example.com/noticias/%d", i), 4)]++
+	}
+	for i, count := range counts {
+		if count < 60 || count > 140 {
+			t.Errorf("posición %d elegida %d de 400 veces: %v", i, count, counts)
+		}
+	}
+}
+
+func TestGetFallbackImage(t *testing.T) {
+	own := uint(7)
+	ownImages := []domain.FallbackImage{
+		{ID: 1, SourceID: &own, Filename: "propia-1.jpg"},
+		{ID: 2, SourceID: &own, Filename: "propia-2.jpg"},
+		{ID: 3, SourceID: &own, Filename: "propia-3.jpg"},
+	}
+	shared := []domain.FallbackImage{
+		{ID: 10, Filename: "global.jpg"},
+		{ID: 11, CategoryCode: "sports", Filename: "deportes.jpg"},
+		{ID: 12, CategoryCode: "sports", LanguageCode: "es", Filename: "deportes-es.jpg"},
+	}
+
+	tests := []struct {
+		name      string
+		images    []domain.FallbackImage
+		category  string
+		generator *staticImageGenerator
+		want      []string // Imágenes admitidas
+	}{
+		{"el conjunto de la fuente primero", append(append([]domain.FallbackImage{}, shared...), ownImages...), "sports", nil,
+			[]string{"/images/fallback/propia-1.jpg", "/images/fallback/propia-2.jpg", "/images/fallback/propia-3.jpg"}},
+		{"después categoría e idioma", shared, "sports", nil, []string{"/images/fallback/deportes-es.jpg"}},
+		{"después solo categoría", shared[:2], "sports", nil, []string{"/images/fallback/deportes.jpg"}},
+		{"después la global", shared, "tech", nil, []string{"/images/fallback/global.jpg"}},
+		{"sin imágenes se genera", nil, "tech", &staticImageGenerator{url: "/images/generated/abc.webp"}, []string{"/images/generated/abc.webp"}},
+		{"sin imágenes ni generador", nil, "tech", nil, []string{""}},
+		{"el generador falla", nil, "tech", &staticImageGenerator{err: errors.New("sin fuente")}, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &FetchNewsUseCase{fallbackImageRepo: &staticFallbackRepo{images: tt.images}, config: &config.Config{}}
+			if tt.generator != nil {
+				uc.imageGenerator = tt.generator
+			}
+			source := &domain.NewsSource{ID: own, SourceName: "Diario"}
+			got := uc.getFallbackImage(context.Background(), source, tt.category, "es", "https://a.com/1", "Titular")
+			if !containsString(tt.want, got) {
+				t.Errorf("getFallbackImage = %q, want uno de %v", got, tt.want)
+			}
+			// Cada noticia conserva siempre la misma imagen del conjunto
+			if again := uc.getFallbackImage(context.Background(), source, tt.category, "es", "https://a.com/1", "Titular"); again != got {
+				t.Errorf("getFallbackImage = %q y después %q", got, again)
+			}
+		})
+	}
+}
+
+func TestGetFallbackImageRotatesPool(t *testing.T) {
+	own := uint(7)
+	uc := &FetchNewsUseCase{fallbackImageRepo: &staticFallbackRepo{images: []domain.FallbackImage{
+		{ID: 1, SourceID: &own, Filename: "a.jpg"},
+		{ID: 2, SourceID: &own, Filename: "b.jpg"},
+		{ID: 3, SourceID: &own, Filename: "c.jpg"},
+	}}, config: &config.Config{}}
+	source := &domain.NewsSource{ID: own}
+
+	used := make(map[string]bool)
+	for i := 0; i < 30; i++ {
+		link := fmt.Sprintf("https://a.com/noticia-%d", i)
+		used[uc.getFallbackImage(context.Background(), source, "sports", "es", link, "")] = true
+	}
+	if len(used) != 3 {
+		t.Errorf("30 noticias usan %d imágenes del conjunto de 3: %v", len(used), used)
+	}
+}
+
+func TestLocalFallbackImage(t *testing.T) {
+	tests := []struct {
+		imageURL  string
+		wantLocal bool
+		wantPath  string
+	}{
+		{"/images/fallback/a.jpg", true, filepath.Join("/app", "frontend", "assets", "images", "fallback", "a.jpg")},
+		{"https://dailynews.example/images/fallback/a.jpg", true, filepath.Join("/app", "frontend", "assets", "images", "fallback", "a.jpg")},
+		{"/images/generated/ab.webp", true, filepath.Join("/app", "frontend", "assets", "images", "generated", "ab.webp")},
+		{"/images/cache/ab/abcd.webp", false, ""},
+		{"https://example.com/foto.jpg", false, ""},
+	}
+	for _, tt := range tests {
+		if got := isLocalFallbackImage(tt.imageURL); got != tt.wantLocal {
+			t.Errorf("isLocalFallbackImage(%q) = %v, want %v", tt.imageURL, got, tt.wantLocal)
+		}
+		if tt.wantLocal {
+			if got := localFallbackPath("/app", tt.imageURL); got != tt.wantPath {
+				t.Errorf("localFallbackPath(%q) = %q, want %q", tt.imageURL, got, tt.wantPath)
+			}
+		}
+	}
+}
+
+// containsString indica si values contiene value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 
 	"dailynews/internal/domain"
 	"dailynews/pkg/config"
+	"dailynews/pkg/feedcache"
 	"dailynews/pkg/utils"
 )
 
@@ -51,8 +53,15 @@ type FetchNewsUseCase struct {
 	countryRepo       domain.CountryRepository
 	newsSourceRepo    domain.NewsSourceRepository
 	fallbackImageRepo domain.FallbackImageRepository // NUEVO
-	rssFetcher        domain.RSSFetcher
+	sourceHealthRepo  domain.SourceHealthRepository
+	webSub            domain.WebSubManager // nil si WebSub está desactivado
+	sourceFetcher     domain.SourceFetcher
 	imageDownloader   domain.ImageDownloader
+	articleImages     domain.ArticleImageExtractor   // nil si no se buscan imágenes en la página de la noticia
+	articleContent    domain.ArticleContentExtractor // nil si la extracción de texto completo está desactivada
+	imageCache        domain.ImageCache              // nil si las imágenes se enlazan directamente del medio
+	placeholderRepo   domain.ImagePlaceholderRepository
+	imageGenerator    domain.FallbackImageGenerator // nil si no se generan imágenes de marca
 	config            *config.Config
 }
 
@@ -63,8 +72,15 @@ func NewFetchNewsUseCase(
 	countryRepo domain.CountryRepository,
 	newsSourceRepo domain.NewsSourceRepository,
 	fallbackImageRepo domain.FallbackImageRepository, // NUEVO
-	rssFetcher domain.RSSFetcher,
+	sourceHealthRepo domain.SourceHealthRepository,
+	webSub domain.WebSubManager,
+	sourceFetcher domain.SourceFetcher,
 	imageDownloader domain.ImageDownloader,
+	articleImages domain.ArticleImageExtractor,
+	articleContent domain.ArticleContentExtractor,
+	imageCache domain.ImageCache,
+	placeholderRepo domain.ImagePlaceholderRepository,
+	imageGenerator domain.FallbackImageGenerator,
 	config *config.Config,
 ) *FetchNewsUseCase {
 	return &FetchNewsUseCase{
@@ -73,8 +89,15 @@ func NewFetchNewsUseCase(
 		countryRepo:       countryRepo,
 		newsSourceRepo:    newsSourceRepo,
 		fallbackImageRepo: fallbackImageRepo, // NUEVO
-		rssFetcher:        rssFetcher,
+		sourceHealthRepo:  sourceHealthRepo,
+		webSub:            webSub,
+		sourceFetcher:     sourceFetcher,
 		imageDownloader:   imageDownloader,
+		articleImages:     articleImages,
+		articleContent:    articleContent,
+		imageCache:        imageCache,
+		placeholderRepo:   placeholderRepo,
+		imageGenerator:    imageGenerator,
 		config:            config,
 	}
 }
@@ -99,6 +122,9 @@ func (uc *FetchNewsUseCase) Execute(ctx context.Context) error {
 		"total_sources": len(sources),
 	})
 
+	// Varias fuentes pueden compartir URL (distinta categoría o idioma): cada feed se descarga una vez por extracción
+	ctx = feedcache.NewContext(ctx, feedcache.New(0))
+
 	groups := make(map[string][]domain.NewsSource) // key: <categoryCode>_<langCode>
 	for _, src := range sources {
 		lang := src.Lang.Code
@@ -131,24 +157,23 @@ func (uc *FetchNewsUseCase) Execute(ctx context.Context) error {
 
 		linksVistos := make(map[string]struct{})
 		titulosVistos := make(map[string]struct{})
+		var imagenesVistas []seenImage // Imágenes aceptadas, para detectar la misma noticia en otro medio
 		descartadas := 0
 		sourceCounts := make(map[string]int) // Contador por fuente para maxPerSource
 
 		for _, src := range groupSources {
 			utils.SourceProcessing(src.SourceName, src.RSSURL)
 
-			// Llamar a Fetch pasando el patrón y los campos personalizados
-			feedItems, err := uc.rssFetcher.Fetch(
-				ctx,
-				src.RSSURL,
-				getString(src.Filter),
-				getString(src.TitleField),
-				getString(src.ImageField),
-				getString(src.LinkField),
-				getString(src.CampoFecha),
-			)
+			// Las peticiones de la fuente (feed e imágenes) usan su perfil HTTP si lo tiene
+			// y acumulan sus estadísticas HTTP para la salud de la fuente
+			stats := &domain.FetchStats{}
+			srcCtx := domain.WithFetchStats(domain.WithHTTPProfile(ctx, src.HTTPProfile, src.RSSURL), stats)
+
+			// Obtener noticias con el fetcher correspondiente al tipo de fuente
+			feedItems, err := uc.sourceFetcher.FetchSource(srcCtx, &src)
 			if err != nil {
 				utils.SourceError(src.RSSURL, err.Error())
+				uc.recordSourceHealth(ctx, src.ID, 0, 0, stats, err)
 				continue
 			}
 
@@ -167,117 +192,42 @@ func (uc *FetchNewsUseCase) Execute(ctx context.Context) error {
 					break
 				}
 
-				titulo := item.Title
-				imagen := item.Image
-				link := item.Link
-				fecha := item.PubDate
-				tituloLimpio := cleanText(titulo)
-
-				// Validaciones con logs específicos
-				if isBlacklisted(tituloLimpio) {
-					utils.NewsWarn(cat, lang, tituloLimpio, "título en lista negra")
-					descartadas++
-					continue
-				}
-
-				if len(tituloLimpio) < uc.config.Filters.MinTitle || len(tituloLimpio) > uc.config.Filters.MaxTitle {
-					utils.NewsWarn(cat, lang, tituloLimpio, fmt.Sprintf("título inválido por longitud: %d caracteres", len(tituloLimpio)))
+				tituloLimpio := cleanText(item.Title)
+				if reason := uc.screenItem(tituloLimpio, item.PubDate, maxDays); reason != "" {
+					discardItem(cat, lang, tituloLimpio, reason, nil)
 					descartadas++
 					continue
 				}
 
 				// Verificar duplicados
-				if _, exists := linksVistos[link]; exists {
-					utils.NewsWarn(cat, lang, tituloLimpio, "duplicada o paquete lleno")
-					descartadas++
-					continue
-				}
-				if _, exists := titulosVistos[tituloLimpio]; exists {
-					utils.NewsWarn(cat, lang, tituloLimpio, "duplicada o paquete lleno")
+				_, linkVisto := linksVistos[item.Link]
+				_, tituloVisto := titulosVistos[tituloLimpio]
+				if linkVisto || tituloVisto {
+					discardItem(cat, lang, tituloLimpio, "duplicada o paquete lleno", nil)
 					descartadas++
 					continue
 				}
 
-				// Verificar edad de la noticia
-				antiguedad := time.Since(fecha)
-				if antiguedad > time.Duration(maxDays)*24*time.Hour {
-					utils.NewsWarn(cat, lang, tituloLimpio, fmt.Sprintf("noticia antigua, ideal: %d días, antigüedad: %.1f días", maxDays, antiguedad.Hours()/24))
+				item.Title = tituloLimpio
+				newsItem, reason, err := uc.processItem(srcCtx, &src, cat, lang, item, imagenesVistas)
+				if newsItem == nil {
+					discardItem(cat, lang, tituloLimpio, reason, err)
 					descartadas++
 					continue
 				}
 
-				// Validar imagen
-				if imagen == "" {
-					// Si no hay imagen y el patrón es sin imagen, usar fallback
-					if strings.Contains(getString(src.Filter), "no_image") {
-						fallbackImage := uc.getFallbackImage(ctx, cat, lang)
-						if fallbackImage != "" {
-							imagen = fallbackImage
-							utils.NewsInfo(cat, lang, tituloLimpio, src.SourceName, map[string]interface{}{
-								"using_fallback": true,
-								"fallback_image": fallbackImage,
-							})
-						} else {
-							utils.NewsWarn(cat, lang, tituloLimpio, "sin imagen y sin fallback configurado")
-							descartadas++
-							continue
-						}
-					} else {
-						utils.NewsWarn(cat, lang, tituloLimpio, "imagen no encontrada")
-						descartadas++
-						continue
-					}
-				}
-
-				// Validar imagen (excepto si es una imagen de fallback local)
-				if !strings.Contains(imagen, "/images/fallback/") {
-					valid, err := uc.imageDownloader.ValidateImage(imagen)
-					if err != nil {
-						utils.NewsError(cat, lang, tituloLimpio, fmt.Sprintf("error al procesar imagen: %s", err.Error()))
-						descartadas++
-						continue
-					}
-					if !valid {
-						utils.NewsWarn(cat, lang, tituloLimpio, "imagen inválida")
-						descartadas++
-						continue
-					}
-				} else {
-					// Para imágenes de fallback, solo verificar que el archivo existe
-					projectRoot := uc.getProjectRoot()
-					imagePath := filepath.Join(projectRoot, "frontend", "assets", "images", "fallback", filepath.Base(imagen))
-					if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-						utils.NewsWarn(cat, lang, tituloLimpio, "imagen de fallback no encontrada en disco")
-						descartadas++
-						continue
-					}
-					utils.NewsInfo(cat, lang, tituloLimpio, src.SourceName, map[string]interface{}{
-						"fallback_validated": true,
-						"image_path":         imagePath,
-					})
-				}
-
-				// Crear noticia para la BD
-				newsItem := domain.NewsItem{
-					Title:        tituloLimpio,
-					Link:         link,
-					Image:        imagen,
-					PubDate:      fecha,
-					LangCode:     lang,
-					CategoryCode: cat,
-					SourceID:     src.ID,
-					Source:       src,
-				}
-
 				// Guardar en la BD
-				if err := uc.newsItemRepo.Create(ctx, &newsItem); err != nil {
+				if err := uc.newsItemRepo.Create(ctx, newsItem); err != nil {
 					utils.NewsError(cat, lang, tituloLimpio, fmt.Sprintf("error guardando en BD: %s", err.Error()))
 					continue
 				}
 
-				noticias = append(noticias, newsItem)
-				linksVistos[link] = struct{}{}
+				noticias = append(noticias, *newsItem)
+				linksVistos[item.Link] = struct{}{}
 				titulosVistos[tituloLimpio] = struct{}{}
+				if newsItem.ImageHash != "" {
+					imagenesVistas = append(imagenesVistas, seenImage{hash: newsItem.ImageHash, title: tituloLimpio})
+				}
 				sourceValidCount++
 				sourceCounts[src.SourceName]++ // Incrementar contador por fuente
 
@@ -293,6 +243,8 @@ func (uc *FetchNewsUseCase) Execute(ctx context.Context) error {
 			} else {
 				utils.SourceProcessingComplete(src.SourceName, sourceValidCount, len(feedItems))
 			}
+			uc.recordSourceHealth(ctx, src.ID, len(feedItems), sourceValidCount, stats, nil)
+			uc.ensureWebSub(srcCtx, &src)
 
 			if len(noticias) >= tope {
 				break
@@ -330,6 +282,16 @@ func (uc *FetchNewsUseCase) ExecuteForSource(ctx context.Context, sourceID uint)
 		"language":    source.Lang.Code,
 	})
 
+	// Las peticiones de la fuente (feed e imágenes) usan su perfil HTTP si lo tiene
+	// y acumulan sus estadísticas HTTP para la salud de la fuente
+	stats := &domain.FetchStats{}
+	ctx = domain.WithFetchStats(domain.WithHTTPProfile(ctx, source.HTTPProfile, source.RSSURL), stats)
+	// El feed descargado queda en la caché para que la suscripción WebSub tome de él su hub
+	// (un push de WebSub ya trae la suya, con el contenido recibido)
+	if feedcache.FromContext(ctx) == nil {
+		ctx = feedcache.NewContext(ctx, feedcache.New(0))
+	}
+
 	// Obtener configuración para esta categoría+idioma
 	cat := source.News.Code
 	lang := source.Lang.Code
@@ -341,26 +303,20 @@ func (uc *FetchNewsUseCase) ExecuteForSource(ctx context.Context, sourceID uint)
 		"max_per_source": maxPerSource,
 	})
 
-	// Obtener noticias del RSS
-	feedItems, err := uc.rssFetcher.Fetch(
-		ctx,
-		source.RSSURL,
-		getString(source.Filter),
-		getString(source.TitleField),
-		getString(source.ImageField),
-		getString(source.LinkField),
-		getString(source.CampoFecha),
-	)
+	// Obtener noticias de la fuente
+	feedItems, err := uc.sourceFetcher.FetchSource(ctx, source)
 	if err != nil {
-		return fmt.Errorf("error obteniendo RSS: %w", err)
+		uc.recordSourceHealth(ctx, source.ID, 0, 0, stats, err)
+		return fmt.Errorf("error obteniendo noticias de la fuente: %w", err)
 	}
 
-	utils.AppInfo("FETCH_NEWS_SOURCE", "Items RSS obtenidos", map[string]interface{}{
+	utils.AppInfo("FETCH_NEWS_SOURCE", "Items de la fuente obtenidos", map[string]interface{}{
 		"total_items": len(feedItems),
 	})
 
 	// Procesar items
 	extractedCount := 0
+	discardedCount := 0
 	linksVistos := make(map[string]struct{})
 	titulosVistos := make(map[string]struct{})
 
@@ -372,130 +328,49 @@ func (uc *FetchNewsUseCase) ExecuteForSource(ctx context.Context, sourceID uint)
 			break
 		}
 
-		titulo := item.Title
-		imagen := item.Image
-		link := item.Link
-		fecha := item.PubDate
-		tituloLimpio := cleanText(titulo)
-
-		// Validaciones básicas
-		if isBlacklisted(tituloLimpio) {
-			utils.AppWarn("FETCH_NEWS_SOURCE", "Título en lista negra", map[string]interface{}{
-				"title": tituloLimpio,
-			})
-			continue
-		}
-
-		if len(tituloLimpio) < uc.config.Filters.MinTitle || len(tituloLimpio) > uc.config.Filters.MaxTitle {
-			utils.AppWarn("FETCH_NEWS_SOURCE", "Título inválido por longitud", map[string]interface{}{
-				"title":  tituloLimpio,
-				"length": len(tituloLimpio),
-				"min":    uc.config.Filters.MinTitle,
-				"max":    uc.config.Filters.MaxTitle,
-			})
+		tituloLimpio := cleanText(item.Title)
+		if reason := uc.screenItem(tituloLimpio, item.PubDate, maxDays); reason != "" {
+			discardItem(cat, lang, tituloLimpio, reason, nil)
+			discardedCount++
 			continue
 		}
 
 		// Verificar duplicados
-		if _, exists := linksVistos[link]; exists {
-			utils.AppWarn("FETCH_NEWS_SOURCE", "Link duplicado", map[string]interface{}{
-				"link": link,
-			})
+		_, linkVisto := linksVistos[item.Link]
+		_, tituloVisto := titulosVistos[tituloLimpio]
+		if linkVisto || tituloVisto {
+			discardItem(cat, lang, tituloLimpio, "duplicada", nil)
+			discardedCount++
 			continue
 		}
-		if _, exists := titulosVistos[tituloLimpio]; exists {
-			utils.AppWarn("FETCH_NEWS_SOURCE", "Título duplicado", map[string]interface{}{
-				"title": tituloLimpio,
-			})
+		// La extracción de una fuente (manual o por WebSub, cuyos hubs suelen reenviar el feed completo)
+		// no borra las noticias anteriores: las ya guardadas no se vuelven a insertar
+		if exists, err := uc.newsItemRepo.ExistsByLink(ctx, item.Link); err != nil {
+			discardItem(cat, lang, tituloLimpio, "error comprobando si ya está guardada", err)
+			discardedCount++
 			continue
-		}
-
-		// Verificar edad de la noticia
-		antiguedad := time.Since(fecha)
-		if antiguedad > time.Duration(maxDays)*24*time.Hour {
-			utils.AppWarn("FETCH_NEWS_SOURCE", "Noticia antigua", map[string]interface{}{
-				"pub_date": fecha,
-				"max_days": maxDays,
-			})
+		} else if exists {
+			discardItem(cat, lang, tituloLimpio, "ya guardada", nil)
+			discardedCount++
 			continue
 		}
 
-		// Validar imagen
-		if imagen == "" {
-			// Si no hay imagen y el patrón es sin imagen, usar fallback
-			if strings.Contains(getString(source.Filter), "no_image") {
-				fallbackImage := uc.getFallbackImage(ctx, cat, lang)
-				if fallbackImage != "" {
-					imagen = fallbackImage
-					utils.AppInfo("FETCH_NEWS_SOURCE", "Usando imagen de fallback", map[string]interface{}{
-						"fallback_image": fallbackImage,
-					})
-				} else {
-					utils.AppWarn("FETCH_NEWS_SOURCE", "Sin imagen y sin fallback", map[string]interface{}{
-						"title": tituloLimpio,
-					})
-					continue
-				}
-			} else {
-				utils.AppWarn("FETCH_NEWS_SOURCE", "Imagen no encontrada", map[string]interface{}{
-					"title": tituloLimpio,
-				})
-				continue
-			}
-		}
-
-		// Validar imagen (excepto si es una imagen de fallback local)
-		if !strings.Contains(imagen, "/images/fallback/") {
-			valid, err := uc.imageDownloader.ValidateImage(imagen)
-			if err != nil {
-				utils.AppError("FETCH_NEWS_SOURCE", "Error validando imagen", err, map[string]interface{}{
-					"title": tituloLimpio,
-					"image": imagen,
-				})
-				continue
-			}
-			if !valid {
-				utils.AppWarn("FETCH_NEWS_SOURCE", "Imagen inválida", map[string]interface{}{
-					"title": tituloLimpio,
-					"image": imagen,
-				})
-				continue
-			}
-		} else {
-			// Para imágenes de fallback, solo verificar que el archivo existe
-			projectRoot := uc.getProjectRoot()
-			imagePath := filepath.Join(projectRoot, "frontend", "assets", "images", "fallback", filepath.Base(imagen))
-			if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-				utils.AppWarn("FETCH_NEWS_SOURCE", "Imagen de fallback no encontrada", map[string]interface{}{
-					"title":      tituloLimpio,
-					"image_path": imagePath,
-				})
-				continue
-			}
-		}
-
-		// Crear noticia para la BD
-		newsItem := domain.NewsItem{
-			Title:        tituloLimpio,
-			Link:         link,
-			Image:        imagen,
-			PubDate:      fecha,
-			LangCode:     lang,
-			CategoryCode: cat,
-			SourceID:     source.ID,
-			Source:       *source,
+		item.Title = tituloLimpio
+		newsItem, reason, err := uc.processItem(ctx, source, cat, lang, item, nil)
+		if newsItem == nil {
+			discardItem(cat, lang, tituloLimpio, reason, err)
+			discardedCount++
+			continue
 		}
 
 		// Guardar en la BD
-		if err := uc.newsItemRepo.Create(ctx, &newsItem); err != nil {
-			utils.AppError("FETCH_NEWS_SOURCE", "Error guardando noticia", err, map[string]interface{}{
-				"title": tituloLimpio,
-			})
+		if err := uc.newsItemRepo.Create(ctx, newsItem); err != nil {
+			utils.NewsError(cat, lang, tituloLimpio, fmt.Sprintf("error guardando en BD: %s", err.Error()))
 			continue
 		}
 
 		// Marcar como vistos
-		linksVistos[link] = struct{}{}
+		linksVistos[item.Link] = struct{}{}
 		titulosVistos[tituloLimpio] = struct{}{}
 		extractedCount++
 
@@ -508,7 +383,10 @@ func (uc *FetchNewsUseCase) ExecuteForSource(ctx context.Context, sourceID uint)
 	utils.AppInfo("FETCH_NEWS_SOURCE", "Extracción completada", map[string]interface{}{
 		"source_id":       source.ID,
 		"extracted_count": extractedCount,
+		"discarded_count": discardedCount,
 	})
+	uc.recordSourceHealth(ctx, source.ID, len(feedItems), extractedCount, stats, nil)
+	uc.ensureWebSub(ctx, source)
 
 	return nil
 }
@@ -550,56 +428,306 @@ func (uc *FetchNewsUseCase) getNewsCount(lang, cat string) int {
 	return 10
 }
 
-// Función processSource eliminada - no se usa
-// La lógica de procesamiento está implementada en Execute()
+// screenItem aplica los filtros que no dependen de la imagen (lista negra, longitud del título y antigüedad)
+// y devuelve el motivo del descarte, o "" si la noticia los pasa
+func (uc *FetchNewsUseCase) screenItem(title string, pubDate time.Time, maxDays int) string {
+	if isBlacklisted(title) {
+		return "título en lista negra"
+	}
+	if len(title) < uc.config.Filters.MinTitle || len(title) > uc.config.Filters.MaxTitle {
+		return fmt.Sprintf("título inválido por longitud: %d caracteres", len(title))
+	}
+	if antiguedad := time.Since(pubDate); antiguedad > time.Duration(maxDays)*24*time.Hour {
+		return fmt.Sprintf("noticia antigua, ideal: %d días, antigüedad: %.1f días", maxDays, antiguedad.Hours()/24)
+	}
+	return ""
+}
 
-func (uc *FetchNewsUseCase) processItem(ctx context.Context, item *domain.NewsItem, source domain.NewsSource, category *domain.Category, country *domain.Country) error {
-	// 1. Validar si ya existe (por Link)
-	// Aquí deberías tener un método FindByLink o similar, si no existe, puedes omitir esta validación o implementarla
-	// exists, err := uc.newsItemRepo.ExistsByLink(ctx, item.Link)
-	// if err != nil {
-	// 	return fmt.Errorf("error al verificar la existencia del link: %w", err)
-	// }
-	// if exists {
-	// 	return fmt.Errorf("la noticia ya existe")
-	// }
+// processItem resuelve la imagen de una noticia ya filtrada (candidatas del feed, página de la noticia,
+// imagen genérica, circuito abierto y niveles de fallback), la cachea con sus versiones reducidas y añade
+// el texto completo. seen son las imágenes ya aceptadas en la extracción: la misma imagen con un título
+// parecido se descarta como duplicada. Devuelve la noticia lista para guardar o, si se descarta, nil con
+// el motivo y el error que lo causó, si lo hubo. item.Title debe venir ya limpio.
+func (uc *FetchNewsUseCase) processItem(ctx context.Context, src *domain.NewsSource, cat, lang string, item domain.NewsItem, seen []seenImage) (*domain.NewsItem, string, error) {
+	titulo := item.Title
+	imagen := item.Image
+	link := item.Link
+
+	articleTried := false
+	if imagen == "" {
+		// Sin imagen en el feed: buscarla en la página de la noticia antes de recurrir al fallback
+		articleTried = true
+		if images := uc.articlePageImages(ctx, src, link); len(images) > 0 {
+			imagen = images[0]
+			item.ImageCandidates = images
+			utils.NewsInfo(cat, lang, titulo, src.SourceName, map[string]interface{}{
+				"image_from_article": true,
+			})
+		}
+	}
+	if imagen == "" {
+		// Si no hay imagen y el patrón es sin imagen, usar fallback
+		if !usesFallbackImage(src) {
+			return nil, "imagen no encontrada", nil
+		}
+		fallbackImage := uc.getFallbackImage(ctx, src, cat, lang, link, titulo)
+		if fallbackImage == "" {
+			return nil, "sin imagen y sin fallback configurado", nil
+		}
+		imagen = fallbackImage
+		utils.NewsInfo(cat, lang, titulo, src.SourceName, map[string]interface{}{
+			"using_fallback": true,
+			"fallback_image": fallbackImage,
+		})
+	}
 
-	// 2. Validar título
-	if len(item.Title) < uc.config.Filters.MinTitle || len(item.Title) > uc.config.Filters.MaxTitle {
-		return fmt.Errorf("longitud de título inválida")
+	imageHash, imageBlurHash := "", ""
+	if !isLocalFallbackImage(imagen) {
+		validImage, result, err := uc.firstValidImage(ctx, src, &item, imagen)
+		if validImage == "" && !articleTried {
+			// Ninguna imagen del feed es válida: probar con las de la página de la noticia
+			if images := uc.articlePageImages(ctx, src, link); len(images) > 0 {
+				item.ImageCandidates = images
+				validImage, result, err = uc.firstValidImage(ctx, src, &item, images[0])
+			}
+		}
+		if errors.Is(err, errPlaceholderImage) {
+			// La fuente solo adjunta su imagen genérica: se trata como una noticia sin imagen
+			fallbackImage := uc.getFallbackImage(ctx, src, cat, lang, link, titulo)
+			if fallbackImage == "" {
+				return nil, "imagen genérica de la fuente y sin fallback configurado", nil
+			}
+			validImage, result, err = fallbackImage, nil, nil
+		}
+		if errors.Is(err, domain.ErrCircuitOpen) {
+			return nil, "host de la imagen no disponible (circuito abierto)", nil
+		}
+		if err != nil {
+			return nil, "error al procesar imagen", err
+		}
+		if validImage == "" {
+			return nil, "imagen inválida", nil
+		}
+		imagen = validImage
+		if result != nil { // nil si se sustituyó por el fallback
+			imageHash, imageBlurHash = result.PHash, result.BlurHash
+		}
+	}
+	if isLocalFallbackImage(imagen) {
+		// Para imágenes de fallback, solo verificar que el archivo existe
+		imagePath := localFallbackPath(uc.getProjectRoot(), imagen)
+		if _, err := os.Stat(imagePath); os.IsNotExist(err) {
+			return nil, "imagen de fallback no encontrada en disco", nil
+		}
+		utils.NewsInfo(cat, lang, titulo, src.SourceName, map[string]interface{}{
+			"fallback_validated": true,
+			"image_path":         imagePath,
+		})
 	}
 
-	// 3. Validar antigüedad
-	if time.Since(item.PubDate).Hours() > float64(uc.config.Filters.MaxDays*24) {
-		return fmt.Errorf("artículo demasiado antiguo")
+	// La misma imagen con un título parecido es la misma noticia contada por otro medio
+	if original := uc.duplicateByImage(seen, imageHash, titulo); original != "" {
+		return nil, fmt.Sprintf("posible duplicada de \"%s\" (misma imagen)", original), nil
 	}
 
-	// 4. Validar y procesar imagen
-	if item.Image == "" {
-		return fmt.Errorf("el artículo no tiene imagen")
+	newsItem := &domain.NewsItem{
+		Title:         titulo,
+		Link:          link,
+		Image:         imagen,
+		ImageHash:     imageHash,
+		ImageBlurHash: imageBlurHash,
+		PubDate:       item.PubDate,
+		LangCode:      lang,
+		CategoryCode:  cat,
+		SourceID:      src.ID,
+		Source:        *src,
 	}
-	valid, err := uc.imageDownloader.ValidateImage(item.Image)
-	if err != nil || !valid {
-		return fmt.Errorf("validación de imagen fallida: %w", err)
+	uc.cacheImage(ctx, newsItem)
+	uc.fillArticleContent(ctx, src, newsItem)
+	return newsItem, "", nil
+}
+
+// discardItem registra por qué se descarta una noticia
+func discardItem(cat, lang, title, reason string, err error) {
+	if err != nil {
+		utils.NewsError(cat, lang, title, fmt.Sprintf("%s: %s", reason, err.Error()))
+		return
 	}
+	utils.NewsWarn(cat, lang, title, reason)
+}
 
-	// 5. Crear y guardar la noticia
-	newsItem := &domain.NewsItem{
-		Title:        item.Title,
-		Link:         item.Link,
-		Image:        item.Image,
-		SourceID:     source.ID,
-		PubDate:      item.PubDate,
-		LangCode:     country.Code,
-		CategoryCode: category.Code,
-		CreatedAt:    time.Now(),
+// recordSourceHealth guarda el resultado de la extracción de una fuente junto con sus estadísticas HTTP.
+// Un fallo al guardar solo se registra: no debe interrumpir la extracción.
+func (uc *FetchNewsUseCase) recordSourceHealth(ctx context.Context, sourceID uint, fetched, accepted int, stats *domain.FetchStats, fetchErr error) {
+	if uc.sourceHealthRepo == nil {
+		return
+	}
+
+	health, err := uc.sourceHealthRepo.FindBySourceID(ctx, sourceID)
+	if err != nil {
+		utils.AppWarn("SOURCE_HEALTH", "Error obteniendo salud de la fuente", map[string]interface{}{
+			"source_id": sourceID,
+			"error":     err.Error(),
+		})
+		return
+	}
+	if health == nil {
+		health = &domain.SourceHealth{SourceID: sourceID}
 	}
 
-	if err := uc.newsItemRepo.Create(ctx, newsItem); err != nil {
-		return fmt.Errorf("error al guardar la noticia en la BD: %w", err)
+	now := time.Now()
+	health.LastRunAt = now
+	health.ItemsFetched = fetched
+	health.ItemsAccepted = accepted
+	health.Requests, health.Retries, health.Failures, health.RateLimited, health.RobotsBlocked = stats.Snapshot()
+	if fetchErr != nil {
+		health.LastStatus = "error"
+		health.LastError = fetchErr.Error()
+		health.ConsecutiveFailures++
+	} else {
+		health.LastStatus = "ok"
+		health.LastError = ""
+		health.LastSuccessAt = &now
+		health.ConsecutiveFailures = 0
 	}
 
-	return nil
+	if err := uc.sourceHealthRepo.Save(ctx, health); err != nil {
+		utils.AppWarn("SOURCE_HEALTH", "Error guardando salud de la fuente", map[string]interface{}{
+			"source_id": sourceID,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// maxImageCandidates es el número máximo de imágenes de un item que se validan antes de descartarlo
+const maxImageCandidates = 3
+
+// firstValidImage valida las imágenes del item en orden de ajuste y devuelve la primera válida que no sea
+// la imagen genérica de la fuente, con su validación (hash perceptual y vista previa). Las que solo valen
+// recortadas deben quedar antes en la caché local. Si ninguna lo es
+// devuelve "" y errPlaceholderImage si alguna era la genérica (la noticia puede usar el fallback), si no
+// el último error de validación (nil si solo eran inválidas).
+func (uc *FetchNewsUseCase) firstValidImage(ctx context.Context, source *domain.NewsSource, item *domain.NewsItem, image string) (string, *domain.ImageValidation, error) {
+	candidates := item.ImageCandidates
+	if len(candidates) == 0 {
+		candidates = []string{image}
+	}
+	if len(candidates) > maxImageCandidates {
+		candidates = candidates[:maxImageCandidates]
+	}
+
+	var lastErr error
+	placeholder := false
+	for _, candidate := range candidates {
+		result, err := uc.imageDownloader.InspectImage(ctx, candidate)
+		if err == nil && result.Valid {
+			if uc.isPlaceholderImage(ctx, source, item.Link, candidate, result.PHash) {
+				placeholder = true
+				continue
+			}
+			if result.NeedsCrop && !uc.storeCropped(ctx, candidate) {
+				continue
+			}
+			return candidate, result, nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+	if placeholder {
+		return "", nil, errPlaceholderImage
+	}
+	return "", nil, lastErr
+}
+
+// storeCropped guarda en la caché local la versión recortada de una imagen con otro aspecto. Sin ella
+// la imagen se enlazaría sin recortar, así que si la caché no está o falla la candidata se descarta.
+func (uc *FetchNewsUseCase) storeCropped(ctx context.Context, imageURL string) bool {
+	if uc.imageCache == nil {
+		return false
+	}
+	if _, err := uc.imageCache.Store(ctx, imageURL); err != nil {
+		utils.AppWarn("IMAGE_CACHE", "No se pudo recortar la imagen, se prueba la siguiente", map[string]interface{}{
+			"image": imageURL,
+			"error": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// articlePageImages devuelve las imágenes de la página de la noticia (og:image, twitter:image, JSON-LD)
+// si la fuente o la configuración global activan el enriquecimiento. Los errores solo se registran.
+func (uc *FetchNewsUseCase) articlePageImages(ctx context.Context, source *domain.NewsSource, link string) []string {
+	if uc.articleImages == nil || link == "" || !(source.EnrichImages || uc.config.ImageEnrich.Enabled) {
+		return nil
+	}
+	images, err := uc.articleImages.ExtractImages(ctx, link)
+	if err != nil {
+		utils.AppWarn("IMAGE_ENRICH", "No se pudo leer la imagen de la página de la noticia", map[string]interface{}{
+			"source_id": source.ID,
+			"link":      link,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return images
+}
+
+// cacheImage sustituye la imagen de la noticia por su copia optimizada en la caché local, con sus versiones
+// reducidas y dimensiones, y guarda la URL original. Si la caché falla se sigue enlazando la imagen del medio.
+func (uc *FetchNewsUseCase) cacheImage(ctx context.Context, item *domain.NewsItem) {
+	if uc.imageCache == nil || strings.HasPrefix(item.Image, "/images/") {
+		return
+	}
+	cached, err := uc.imageCache.Store(ctx, item.Image)
+	if err != nil {
+		utils.AppWarn("IMAGE_CACHE", "No se pudo cachear la imagen, se enlaza la original", map[string]interface{}{
+			"image": item.Image,
+			"error": err.Error(),
+		})
+		return
+	}
+	item.OriginalImage = item.Image
+	item.Image = cached.LocalURL
+	item.ImageSrcset = cached.Srcset()
+	item.ImageWidth = cached.Width
+	item.ImageHeight = cached.Height
+}
+
+// fillArticleContent añade a la noticia su texto completo, palabras y tiempo de lectura si la fuente
+// lo tiene activado. Si la extracción falla la noticia se guarda igualmente sin texto.
+func (uc *FetchNewsUseCase) fillArticleContent(ctx context.Context, source *domain.NewsSource, item *domain.NewsItem) {
+	if uc.articleContent == nil || !source.ExtractContent || item.Link == "" {
+		return
+	}
+	content, err := uc.articleContent.Extract(ctx, item.Link)
+	if err != nil {
+		utils.AppWarn("ARTICLE_CONTENT", "No se pudo extraer el texto de la noticia", map[string]interface{}{
+			"source_id": source.ID,
+			"link":      item.Link,
+			"error":     err.Error(),
+		})
+		return
+	}
+	item.Content = content.HTML
+	item.ContentText = content.Text
+	item.WordCount = content.WordCount
+	item.ReadingTime = content.ReadingTime
+}
+
+// ensureWebSub suscribe la fuente a su hub WebSub si el feed lo declara. Los errores solo se registran:
+// la fuente se sigue extrayendo por cron.
+func (uc *FetchNewsUseCase) ensureWebSub(ctx context.Context, source *domain.NewsSource) {
+	if uc.webSub == nil {
+		return
+	}
+	if err := uc.webSub.EnsureSubscribed(ctx, source); err != nil {
+		utils.AppWarn("WEBSUB", "No se pudo suscribir la fuente", map[string]interface{}{
+			"source_id": source.ID,
+			"error":     err.Error(),
+		})
+	}
 }
 
 // Helper para obtener el valor string de un *string
@@ -610,6 +738,22 @@ func getString(ptr *string) string {
 	return ""
 }
 
+// usesFallbackImage indica si la fuente no aporta imagen y sus noticias deben usar la imagen de fallback
+func usesFallbackImage(source *domain.NewsSource) bool {
+	if strings.Contains(getString(source.Filter), "no_image") {
+		return true
+	}
+	switch source.GetType() {
+	case domain.SourceTypeHTML, domain.SourceTypeJSON:
+		// Las fuentes HTML y JSON sin campo de imagen se tratan como patrones sin imagen
+		return getString(source.ImageField) == ""
+	case domain.SourceTypeSitemap:
+		// image:image es opcional en los sitemaps: las URLs sin imagen recurren al fallback
+		return true
+	}
+	return false
+}
+
 // cleanOldNews limpia noticias anteriores de la BD para evitar duplicación
 func (uc *FetchNewsUseCase) cleanOldNews(ctx context.Context) error {
 	utils.AppInfo("FETCH_NEWS", "Limpiando noticias anteriores de la base de datos", nil)
@@ -624,27 +768,6 @@ func (uc *FetchNewsUseCase) cleanOldNews(ctx context.Context) error {
 	return nil
 }
 
-// getFallbackImage obtiene la imagen de fallback para una categoría+idioma
-func (uc *FetchNewsUseCase) getFallbackImage(ctx context.Context, categoryCode, languageCode string) string {
-	fallbackImage, err := uc.fallbackImageRepo.GetByCategoryAndLang(ctx, categoryCode, languageCode)
-	if err != nil || fallbackImage == nil {
-		return ""
-	}
-
-	// Usar URL relativa que funcione en cualquier entorno
-	// Esto evita problemas de protocolo (HTTP vs HTTPS)
-	fallbackURL := fmt.Sprintf("/images/fallback/%s", fallbackImage.Filename)
-
-	utils.AppInfo("FALLBACK_IMAGE", "URL de imagen de fallback generada", map[string]interface{}{
-		"category_code": categoryCode,
-		"language_code": languageCode,
-		"filename":      fallbackImage.Filename,
-		"url":           fallbackURL,
-	})
-
-	return fallbackURL
-}
-
 // getProjectRoot obtiene la ruta raíz del proyecto.
 func (uc *FetchNewsUseCase) getProjectRoot() string {
 	// Obtener el directorio de trabajo actual
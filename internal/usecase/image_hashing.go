@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/utils"
+)
+
+// errPlaceholderImage indica que las imágenes válidas de la noticia eran la imagen genérica de la fuente
+var errPlaceholderImage = errors.New("imagen genérica de la fuente")
+
+// Valores por defecto de la detección de imágenes genéricas y duplicadas
+const (
+	defaultPlaceholderDistance = 4  // Bits de diferencia entre dHash para considerar dos imágenes la misma
+	defaultPlaceholderWindow   = 50 // Últimas noticias de la fuente en las que se busca la repetición
+	minTitleWordLength         = 4  // Palabras más cortas no cuentan para la similitud de títulos
+)
+
+// seenImage es una noticia ya aceptada en la extracción, para detectar duplicadas por su imagen
+type seenImage struct {
+	hash  string
+	title string
+}
+
+// imageHashDistance es la distancia máxima entre hashes perceptuales de la misma imagen
+func (uc *FetchNewsUseCase) imageHashDistance() int {
+	if uc.config.ImageHashing.PlaceholderDistance > 0 {
+		return uc.config.ImageHashing.PlaceholderDistance
+	}
+	return defaultPlaceholderDistance
+}
+
+// isPlaceholderImage indica si la imagen es la genérica de la fuente: la tiene en su lista o se repite
+// en al menos learnThreshold de sus últimas noticias, en cuyo caso se añade a la lista automáticamente.
+// Las imágenes vistas se anotan en un historial propio, que sobrevive a la limpieza de noticias.
+func (uc *FetchNewsUseCase) isPlaceholderImage(ctx context.Context, source *domain.NewsSource, link, imageURL, hash string) bool {
+	if uc.placeholderRepo == nil || source == nil || source.ID == 0 || hash == "" {
+		return false
+	}
+	distance := uc.imageHashDistance()
+
+	placeholders, err := uc.placeholderRepo.ListBySource(ctx, source.ID)
+	if err != nil {
+		utils.AppWarn("IMAGE_PLACEHOLDER", "Error consultando las imágenes genéricas de la fuente", map[string]interface{}{
+			"source_id": source.ID,
+			"error":     err.Error(),
+		})
+		return false
+	}
+	for _, placeholder := range placeholders {
+		if domain.ImageHashDistance(placeholder.Hash, hash) <= distance {
+			if err := uc.placeholderRepo.IncrementHits(ctx, placeholder.ID); err != nil {
+				utils.AppWarn("IMAGE_PLACEHOLDER", "Error actualizando la imagen genérica", map[string]interface{}{
+					"id":    placeholder.ID,
+					"error": err.Error(),
+				})
+			}
+			return true
+		}
+	}
+
+	// Aprendizaje: la misma imagen en muchas noticias distintas de la fuente es su logo o un marcador
+	threshold := uc.config.ImageHashing.LearnThreshold
+	if threshold <= 0 {
+		return false
+	}
+	window := uc.config.ImageHashing.LearnWindow
+	if window <= 0 {
+		window = defaultPlaceholderWindow
+	}
+	sighting := &domain.ImageHashSighting{SourceID: source.ID, LinkHash: domain.HashURL(link), Hash: hash}
+	if err := uc.placeholderRepo.RecordSighting(ctx, sighting, window); err != nil {
+		utils.AppWarn("IMAGE_PLACEHOLDER", "Error anotando la imagen vista", map[string]interface{}{
+			"source_id": source.ID,
+			"error":     err.Error(),
+		})
+		return false
+	}
+	recent, err := uc.placeholderRepo.RecentSightings(ctx, source.ID, window)
+	if err != nil {
+		utils.AppWarn("IMAGE_PLACEHOLDER", "Error consultando las imágenes recientes de la fuente", map[string]interface{}{
+			"source_id": source.ID,
+			"error":     err.Error(),
+		})
+		return false
+	}
+	repeats := 0 // El historial ya incluye la imagen actual
+	for _, other := range recent {
+		if domain.ImageHashDistance(other, hash) <= distance {
+			repeats++
+		}
+	}
+	if repeats < threshold {
+		return false
+	}
+
+	placeholder := &domain.ImagePlaceholder{
+		SourceID:    source.ID,
+		Hash:        hash,
+		ImageURL:    imageURL,
+		AutoLearned: true,
+	}
+	if err := uc.placeholderRepo.Save(ctx, placeholder); err != nil {
+		utils.AppWarn("IMAGE_PLACEHOLDER", "Error guardando la imagen genérica aprendida", map[string]interface{}{
+			"source_id": source.ID,
+			"error":     err.Error(),
+		})
+	} else {
+		utils.AppWarn("IMAGE_PLACEHOLDER", "Imagen genérica de la fuente aprendida", map[string]interface{}{
+			"source_id": source.ID,
+			"source":    source.SourceName,
+			"hash":      hash,
+			"repeats":   repeats,
+			"image":     imageURL,
+		})
+		uc.replacePlaceholderImages(ctx, source, hash)
+	}
+	return true
+}
+
+// replacePlaceholderImages pone la imagen de fallback a las noticias ya guardadas de la fuente que traían
+// la imagen genérica recién aprendida: las aceptadas antes de que se alcanzara el umbral
+func (uc *FetchNewsUseCase) replacePlaceholderImages(ctx context.Context, source *domain.NewsSource, hash string) {
+	items, err := uc.newsItemRepo.FindBySourceID(ctx, source.ID)
+	if err != nil {
+		utils.AppWarn("IMAGE_PLACEHOLDER", "Error obteniendo las noticias de la fuente", map[string]interface{}{
+			"source_id": source.ID,
+			"error":     err.Error(),
+		})
+		return
+	}
+	distance := uc.imageHashDistance()
+	for i := range items {
+		item := &items[i]
+		if domain.ImageHashDistance(item.ImageHash, hash) > distance {
+			continue
+		}
+		fallbackImage := uc.getFallbackImage(ctx, source, item.CategoryCode, item.LangCode, item.Link, item.Title)
+		if fallbackImage == "" {
+			continue // Sin fallback, mejor la imagen genérica que ninguna
+		}
+		item.Image = fallbackImage
+		item.OriginalImage = ""
+		item.ImageHash = ""
+		item.ImageBlurHash = ""
+		item.ImageSrcset = ""
+		item.ImageWidth, item.ImageHeight = 0, 0
+		if err := uc.newsItemRepo.Update(ctx, item); err != nil {
+			utils.AppWarn("IMAGE_PLACEHOLDER", "Error sustituyendo la imagen genérica de la noticia", map[string]interface{}{
+				"news_id": item.ID,
+				"error":   err.Error(),
+			})
+		}
+	}
+}
+
+// duplicateByImage devuelve el título de una noticia ya aceptada con la misma imagen y un título parecido
+// ("" si no hay). La imagen sola no basta: las agencias reparten la misma foto para noticias distintas.
+func (uc *FetchNewsUseCase) duplicateByImage(seen []seenImage, hash, title string) string {
+	minSimilarity := uc.config.ImageHashing.DuplicateTitleSimilarity
+	if minSimilarity <= 0 || hash == "" {
+		return ""
+	}
+	distance := uc.imageHashDistance()
+	for _, other := range seen {
+		if domain.ImageHashDistance(other.hash, hash) <= distance && titleSimilarity(other.title, title) >= minSimilarity {
+			return other.title
+		}
+	}
+	return ""
+}
+
+// titleSimilarity es el índice de Jaccard entre las palabras significativas de dos títulos (0-1)
+func titleSimilarity(a, b string) float64 {
+	wordsA, wordsB := titleWords(a), titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+// titleWords devuelve las palabras de al menos minTitleWordLength letras del título, en minúsculas
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if len([]rune(word)) >= minTitleWordLength {
+			words[word] = true
+		}
+	}
+	return words
+}
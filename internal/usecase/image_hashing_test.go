@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"dailynews/internal/domain"
+	"dailynews/pkg/config"
+)
+
+// memoryPlaceholderRepo implementa domain.ImagePlaceholderRepository en memoria
+type memoryPlaceholderRepo struct {
+	placeholders []domain.ImagePlaceholder
+	sightings    []domain.ImageHashSighting
+}
+
+func (r *memoryPlaceholderRepo) ListBySource(ctx context.Context, sourceID uint) ([]domain.ImagePlaceholder, error) {
+	var result []domain.ImagePlaceholder
+	for _, placeholder := range r.placeholders {
+		if placeholder.SourceID == sourceID {
+			result = append(result, placeholder)
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryPlaceholderRepo) Save(ctx context.Context, placeholder *domain.ImagePlaceholder) error {
+	placeholder.ID = uint(len(r.placeholders) + 1)
+	r.placeholders = append(r.placeholders, *placeholder)
+	return nil
+}
+
+func (r *memoryPlaceholderRepo) Delete(ctx context.Context, id uint) error {
+	for i, placeholder := range r.placeholders {
+		if placeholder.ID == id {
+			r.placeholders = append(r.placeholders[:i], r.placeholders[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryPlaceholderRepo) IncrementHits(ctx context.Context, id uint) error {
+	for i := range r.placeholders {
+		if r.placeholders[i].ID == id {
+			r.placeholders[i].Hits++
+		}
+	}
+	return nil
+}
+
+func (r *memoryPlaceholderRepo) RecordSighting(ctx context.Context, sighting *domain.ImageHashSighting, keep int) error {
+	var kept []domain.ImageHashSighting
+	for _, other := range r.sightings {
+		// Volver a ver la misma imagen en la misma noticia no cuenta dos veces
+		if other.SourceID != sighting.SourceID || other.LinkHash != sighting.LinkHash || other.Hash != sighting.Hash {
+			kept = append(kept, other)
+		}
+	}
+	kept = append(kept, *sighting)
+	if len(kept) > keep {
+		kept = kept[len(kept)-keep:]
+	}
+	r.sightings = kept
+	return nil
+}
+
+func (r *memoryPlaceholderRepo) RecentSightings(ctx context.Context, sourceID uint, limit int) ([]string, error) {
+	var hashes []string
+	for i := len(r.sightings) - 1; i >= 0 && len(hashes) < limit; i-- {
+		if r.sightings[i].SourceID == sourceID {
+			hashes = append(hashes, r.sightings[i].Hash)
+		}
+	}
+	return hashes, nil
+}
+
+// memoryNewsItemRepo sirve y actualiza noticias en memoria; el resto de operaciones no se usan
+type memoryNewsItemRepo struct {
+	domain.NewsItemRepository
+	items []domain.NewsItem
+}
+
+func (r *memoryNewsItemRepo) FindBySourceID(ctx context.Context, sourceID uint) ([]domain.NewsItem, error) {
+	return append([]domain.NewsItem(nil), r.items...), nil
+}
+
+func (r *memoryNewsItemRepo) Update(ctx context.Context, item *domain.NewsItem) error {
+	for i := range r.items {
+		if r.items[i].ID == item.ID {
+			r.items[i] = *item
+		}
+	}
+	return nil
+}
+
+// staticFallbackRepo devuelve siempre las mismas imágenes de fallback; el resto de operaciones no se usan
+type staticFallbackRepo struct {
+	domain.FallbackImageRepository
+	images []domain.FallbackImage
+}
+
+func (r *staticFallbackRepo) ListForFallback(ctx context.Context, sourceID uint, categoryCode, languageCode string) ([]domain.FallbackImage, error) {
+	return r.images, nil
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"El Gobierno aprueba los presupuestos", "El Gobierno aprueba los presupuestos", 1},
+		{"El Gobierno aprueba los presupuestos", "EL GOBIERNO APRUEBA LOS PRESUPUESTOS.", 1},
+		// gobierno, aprueba, presupuestos frente a gobierno, aprueba, decreto: 2 de 4
+		{"El Gobierno aprueba los presupuestos", "El Gobierno aprueba un decreto", 0.5},
+		{"Lluvias en Galicia", "Incendio en Valencia", 0},
+		{"Sí", "Sí", 0}, // Sin palabras significativas
+		{"", "Gobierno", 0},
+		{"Récord de la Bolsa en 2024", "La bolsa marca un récord en 2024", 0.75},
+	}
+	for _, tt := range tests {
+		if got := titleSimilarity(tt.a, tt.b); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDuplicateByImage(t *testing.T) {
+	seen := []seenImage{
+		{hash: "ff00ff00ff00ff00", title: "El Gobierno aprueba los presupuestos de 2025"},
+		{hash: "0000000000000000", title: "Lluvias torrenciales en Galicia"},
+	}
+	tests := []struct {
+		name       string
+		similarity float64
+		hash       string
+		title      string
+		want       string
+	}{
+		{"misma imagen y título parecido", 0.5, "ff00ff00ff00ff01", "El Gobierno aprueba hoy los presupuestos de 2025", seen[0].title},
+		{"misma imagen con otro titular", 0.5, "ff00ff00ff00ff00", "Nuevo récord de temperaturas en Sevilla", ""},
+		{"titular parecido con otra imagen", 0.5, "00ff00ff00ff00ff", "El Gobierno aprueba los presupuestos de 2025", ""},
+		{"sin hash", 0.5, "", "Lluvias torrenciales en Galicia", ""},
+		{"detección desactivada", 0, "0000000000000000", "Lluvias torrenciales en Galicia", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &FetchNewsUseCase{config: &config.Config{ImageHashing: config.ImageHashingConfig{DuplicateTitleSimilarity: tt.similarity}}}
+			if got := uc.duplicateByImage(seen, tt.hash, tt.title); got != tt.want {
+				t.Errorf("duplicateByImage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPlaceholderImageKnown(t *testing.T) {
+	repo := &memoryPlaceholderRepo{placeholders: []domain.ImagePlaceholder{
+		{ID: 1, SourceID: 7, Hash: "f0f0f0f0f0f0f0f0"},
+		{ID: 2, SourceID: 8, Hash: "0000000000000000"},
+	}}
+	uc := &FetchNewsUseCase{placeholderRepo: repo, config: &config.Config{}}
+	source := &domain.NewsSource{ID: 7}
+
+	tests := []struct {
+		name   string
+		source *domain.NewsSource
+		hash   string
+		want   bool
+	}{
+		{"en la lista de la fuente", source, "f0f0f0f0f0f0f0f0", true},
+		{"a pocos bits de distancia", source, "f0f0f0f0f0f0f0f3", true},
+		{"demasiado distinta", source, "f0f0f0f0f0f0ffff", false},
+		{"en la lista de otra fuente", source, "0000000000000000", false},
+		{"sin hash", source, "", false},
+		{"fuente sin guardar", &domain.NewsSource{}, "f0f0f0f0f0f0f0f0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uc.isPlaceholderImage(context.Background(), tt.source, "https://a.com/1", "https://a.com/logo.png", tt.hash); got != tt.want {
+				t.Errorf("isPlaceholderImage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if repo.placeholders[0].Hits != 2 {
+		t.Errorf("Hits = %d, want 2", repo.placeholders[0].Hits)
+	}
+}
+
+func TestIsPlaceholderImageLearns(t *testing.T) {
+	const logo = "a5a5a5a5a5a5a5a5"
+	repo := &memoryPlaceholderRepo{}
+	newsRepo := &memoryNewsItemRepo{items: []domain.NewsItem{
+		{ID: 1, Link: "https://a.com/1", Image: "https://a.com/logo.png", ImageHash: logo, ImageWidth: 800},
+		{ID: 2, Link: "https://a.com/2", Image: "https://a.com/foto.jpg", ImageHash: "5a5a5a5a5a5a5a5a"},
+	}}
+	uc := &FetchNewsUseCase{
+		placeholderRepo:   repo,
+		newsItemRepo:      newsRepo,
+		fallbackImageRepo: &staticFallbackRepo{images: []domain.FallbackImage{{ID: 3, Filename: "global.jpg"}}},
+		config:            &config.Config{ImageHashing: config.ImageHashingConfig{LearnThreshold: 3, LearnWindow: 10}},
+	}
+	source := &domain.NewsSource{ID: 7, SourceName: "Diario"}
+
+	steps := []struct {
+		link string
+		want bool
+	}{
+		{"https://a.com/1", false},
+		{"https://a.com/1", false}, // La misma noticia otra vez no suma
+		{"https://a.com/3", false},
+		{"https://a.com/4", true}, // Tercera noticia con la imagen: se aprende
+		{"https://a.com/5", true}, // Ya está en la lista
+	}
+	for i, step := range steps {
+		if got := uc.isPlaceholderImage(context.Background(), source, step.link, "https://a.com/logo.png", logo); got != step.want {
+			t.Fatalf("paso %d: isPlaceholderImage = %v, want %v", i, got, step.want)
+		}
+	}
+
+	if len(repo.placeholders) != 1 || !repo.placeholders[0].AutoLearned || repo.placeholders[0].Hits != 1 {
+		t.Fatalf("imágenes genéricas = %+v", repo.placeholders)
+	}
+	// Las noticias ya guardadas con la imagen genérica pasan a la de fallback
+	if item := newsRepo.items[0]; item.Image != "/images/fallback/global.jpg" || item.ImageHash != "" || item.ImageWidth != 0 {
+		t.Errorf("noticia con la imagen genérica = %+v", item)
+	}
+	if item := newsRepo.items[1]; item.Image != "https://a.com/foto.jpg" {
+		t.Errorf("noticia con otra imagen = %+v", item)
+	}
+}
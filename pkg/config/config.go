@@ -9,14 +9,24 @@ import (
 )
 
 type Config struct {
-	Database     DatabaseConfig         `mapstructure:"database"`
-	Server       ServerConfig           `mapstructure:"server"`
-	Logger       LoggerConfig           `mapstructure:"logger"`
-	NewsCount    map[string]interface{} `mapstructure:"newsCount"`
-	MaxPerSource map[string]interface{} `mapstructure:"maxPerSource"`
-	MaxDays      map[string]interface{} `mapstructure:"maxDays"`
-	Cron         CronConfig             `mapstructure:"cron"`
-	Filters      FiltersConfig          `mapstructure:"filters"`
+	Database        DatabaseConfig         `mapstructure:"database"`
+	Server          ServerConfig           `mapstructure:"server"`
+	Logger          LoggerConfig           `mapstructure:"logger"`
+	NewsCount       map[string]interface{} `mapstructure:"newsCount"`
+	MaxPerSource    map[string]interface{} `mapstructure:"maxPerSource"`
+	MaxDays         map[string]interface{} `mapstructure:"maxDays"`
+	Cron            CronConfig             `mapstructure:"cron"`
+	Filters         FiltersConfig          `mapstructure:"filters"`
+	Sitemap         SitemapConfig          `mapstructure:"sitemap"`
+	Politeness      PolitenessConfig       `mapstructure:"politeness"`
+	FeedCache       FeedCacheConfig        `mapstructure:"feedCache"`
+	WebSub          WebSubConfig           `mapstructure:"websub"`
+	ImageEnrich     ImageEnrichConfig      `mapstructure:"imageEnrichment"`
+	ArticleContent  ArticleContentConfig   `mapstructure:"articleContent"`
+	ImageCache      ImageCacheConfig       `mapstructure:"imageCache"`
+	GeneratedImages GeneratedImagesConfig  `mapstructure:"generatedImages"`
+	ImageValidation ImageValidationConfig  `mapstructure:"imageValidation"`
+	ImageHashing    ImageHashingConfig     `mapstructure:"imageHashing"`
 }
 
 type DatabaseConfig struct {
@@ -62,6 +72,81 @@ type FiltersConfig struct {
 	MaxDaysForNewsWithFewSources int     `mapstructure:"maxDaysForNewsWithFewSources"`
 	AspectTolerance              float64 `mapstructure:"aspectTolerance"`
 	TargetAspect                 float64 `mapstructure:"targetAspect"`
+	MaxImageBytes                int64   `mapstructure:"maxImageBytes"`
+	MaxImagePixels               int64   `mapstructure:"maxImagePixels"`
+}
+
+type SitemapConfig struct {
+	MaxURLs          int `mapstructure:"maxURLs"`
+	MaxAgeHours      int `mapstructure:"maxAgeHours"`
+	MaxChildSitemaps int `mapstructure:"maxChildSitemaps"`
+}
+
+type FeedCacheConfig struct {
+	TTLSeconds int `mapstructure:"ttlSeconds"`
+}
+
+type WebSubConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	CallbackBaseURL string `mapstructure:"callbackBaseURL"`
+	LeaseHours      int    `mapstructure:"leaseHours"`
+}
+
+type ImageEnrichConfig struct {
+	Enabled        bool `mapstructure:"enabled"`
+	MaxBytes       int  `mapstructure:"maxBytes"`
+	TimeoutSeconds int  `mapstructure:"timeoutSeconds"`
+}
+
+type ArticleContentConfig struct {
+	Enabled        bool `mapstructure:"enabled"`
+	MaxBytes       int  `mapstructure:"maxBytes"`
+	TimeoutSeconds int  `mapstructure:"timeoutSeconds"`
+	WordsPerMinute int  `mapstructure:"wordsPerMinute"`
+}
+
+type ImageCacheConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	Format          string `mapstructure:"format"`
+	MaxAgeDays      int    `mapstructure:"maxAgeDays"`
+	MaxSizeMB       int    `mapstructure:"maxSizeMB"`
+	Crop            string `mapstructure:"crop"`
+	MinSourceWidth  int    `mapstructure:"minSourceWidth"`
+	MinSourceHeight int    `mapstructure:"minSourceHeight"`
+	Resampling      string `mapstructure:"resampling"`
+	WebPQuality     int    `mapstructure:"webpQuality"`
+	JPEGQuality     int    `mapstructure:"jpegQuality"`
+	LetterboxColor  string `mapstructure:"letterboxColor"`
+	Renditions      []int  `mapstructure:"renditions"`
+}
+
+type GeneratedImagesConfig struct {
+	Enabled      bool `mapstructure:"enabled"`
+	IncludeTitle bool `mapstructure:"includeTitle"`
+	MaxAgeDays   int  `mapstructure:"maxAgeDays"`
+}
+
+type ImageValidationConfig struct {
+	TTLHours        int `mapstructure:"ttlHours"`
+	FailureTTLHours int `mapstructure:"failureTTLHours"`
+}
+
+type ImageHashingConfig struct {
+	PlaceholderDistance      int     `mapstructure:"placeholderDistance"`
+	LearnThreshold           int     `mapstructure:"learnThreshold"`
+	LearnWindow              int     `mapstructure:"learnWindow"`
+	DuplicateTitleSimilarity float64 `mapstructure:"duplicateTitleSimilarity"`
+}
+
+type PolitenessConfig struct {
+	RequestsPerSecondPerHost float64 `mapstructure:"requestsPerSecondPerHost"`
+	MaxConcurrentPerHost     int     `mapstructure:"maxConcurrentPerHost"`
+	RespectRobots            bool    `mapstructure:"respectRobots"`
+	MaxRetries               int     `mapstructure:"maxRetries"`
+	RetryBaseDelayMs         int     `mapstructure:"retryBaseDelayMs"`
+	RetryMaxDelaySeconds     int     `mapstructure:"retryMaxDelaySeconds"`
+	CircuitFailureThreshold  int     `mapstructure:"circuitFailureThreshold"`
+	CircuitCooldownSeconds   int     `mapstructure:"circuitCooldownSeconds"`
 }
 
 // LoadConfig carga la configuración desde el archivo YAML
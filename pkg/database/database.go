@@ -124,9 +124,16 @@ func (db *DB) Migrate() error {
 	if err := db.DB.AutoMigrate(
 		&domain.Country{},
 		&domain.Category{},
+		&domain.HTTPProfile{},
 		&domain.NewsSource{},
 		&domain.NewsItem{},
 		&domain.FallbackImage{}, // NUEVO
+		&domain.SourceHealth{},
+		&domain.WebSubSubscription{},
+		&domain.CachedImage{},
+		&domain.ImageValidation{},
+		&domain.ImagePlaceholder{},
+		&domain.ImageHashSighting{},
 	); err != nil {
 		return fmt.Errorf("error al migrar la base de datos: %w", err)
 	}
@@ -0,0 +1,154 @@
+// Package feedcache guarda feeds ya descargados y parseados para compartirlos entre fuentes.
+package feedcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Cache guarda por clave (normalmente la URL del feed) feeds ya descargados y parseados,
+// para que varias fuentes con la misma URL no la descarguen más de una vez.
+// Con ttl 0 las entradas no caducan: se usa una caché nueva por extracción.
+// Es seguro para uso concurrente: si dos fuentes piden la misma clave a la vez, solo una la carga.
+type Cache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// Feed es un feed parseado junto con los enlaces WebSub de su descarga (cabecera Link y <link>
+// previos al primer item), para no volver a descargarlo al buscar su hub
+type Feed struct {
+	*gofeed.Feed
+	Hub  string // Enlace rel="hub" ("" si el feed no declara hub)
+	Self string // Enlace rel="self"
+}
+
+type entry struct {
+	ready    chan struct{} // Se cierra cuando termina la carga
+	feed     *Feed
+	err      error
+	storedAt time.Time
+}
+
+// New crea una caché cuyas entradas caducan tras ttl (0 = no caducan)
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]*entry)}
+}
+
+// GetOrLoad devuelve el feed de la clave o lo carga con load. Los errores no se guardan:
+// la siguiente petición de la misma clave vuelve a intentarlo. El segundo valor indica si vino de caché.
+// Con receptor nil carga siempre.
+func (c *Cache) GetOrLoad(key string, load func() (*Feed, error)) (*Feed, bool, error) {
+	if c == nil {
+		feed, err := load()
+		return feed, false, err
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		select {
+		case <-e.ready:
+			if e.err == nil && (c.ttl <= 0 || time.Since(e.storedAt) < c.ttl) {
+				c.mu.Unlock()
+				return e.feed, true, nil
+			}
+			// Caducada o fallida: se vuelve a cargar
+		default:
+			// Otra fuente la está cargando: esperar su resultado
+			c.mu.Unlock()
+			<-e.ready
+			if e.err != nil {
+				return c.GetOrLoad(key, load)
+			}
+			return e.feed, true, nil
+		}
+	}
+	e := &entry{ready: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	e.feed, e.err = load()
+	e.storedAt = time.Now()
+	close(e.ready)
+
+	if e.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	return e.feed, false, e.err
+}
+
+// Get devuelve el feed de la clave si ya está cargado y vigente, sin cargarlo ni esperar a una carga en curso
+func (c *Cache) Get(key string) (*Feed, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	select {
+	case <-e.ready:
+		if e.err == nil && (c.ttl <= 0 || time.Since(e.storedAt) < c.ttl) {
+			return e.feed, true
+		}
+	default:
+	}
+	return nil, false
+}
+
+// Set guarda un feed ya cargado (por ejemplo, uno recibido por WebSub)
+func (c *Cache) Set(key string, feed *Feed) {
+	if c == nil {
+		return
+	}
+	e := &entry{ready: make(chan struct{}), feed: feed, storedAt: time.Now()}
+	close(e.ready)
+	c.mu.Lock()
+	c.entries[key] = e
+	c.mu.Unlock()
+}
+
+// Purge elimina las entradas caducadas (solo tiene efecto con ttl > 0)
+func (c *Cache) Purge() {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		select {
+		case <-e.ready:
+			if time.Since(e.storedAt) >= c.ttl {
+				delete(c.entries, key)
+			}
+		default:
+		}
+	}
+}
+
+// contextKey es la clave de contexto de la caché de la extracción en curso
+type contextKey struct{}
+
+// NewContext devuelve un contexto cuyas descargas de feeds se comparten a través de cache
+func NewContext(ctx context.Context, cache *Cache) context.Context {
+	if cache == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, cache)
+}
+
+// FromContext devuelve la caché del contexto o nil si no hay (una caché nil carga siempre)
+func FromContext(ctx context.Context) *Cache {
+	cache, _ := ctx.Value(contextKey{}).(*Cache)
+	return cache
+}
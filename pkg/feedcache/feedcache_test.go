@@ -0,0 +1,216 @@
+package feedcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestCacheGetOrLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		ttl       time.Duration
+		wait      time.Duration
+		wantLoads int
+		wantHit   bool
+	}{
+		{"sin ttl no caduca", 0, 15 * time.Millisecond, 1, true},
+		{"dentro del ttl", time.Hour, 0, 1, true},
+		{"ttl vencido vuelve a cargar", 10 * time.Millisecond, 15 * time.Millisecond, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := New(tt.ttl)
+			loads := 0
+			load := func() (*Feed, error) {
+				loads++
+				return &Feed{Feed: &gofeed.Feed{Title: "Portada"}}, nil
+			}
+
+			feed, hit, err := cache.GetOrLoad("https://a.example/rss", load)
+			if err != nil || hit || feed.Title != "Portada" {
+				t.Fatalf("primera carga = %v, %v, %v", feed, hit, err)
+			}
+			time.Sleep(tt.wait)
+			feed, hit, err = cache.GetOrLoad("https://a.example/rss", load)
+			if err != nil || feed == nil {
+				t.Fatalf("segunda carga = %v, %v", feed, err)
+			}
+			if hit != tt.wantHit || loads != tt.wantLoads {
+				t.Errorf("hit = %v, loads = %d; want %v, %d", hit, loads, tt.wantHit, tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestCacheErrorsAreNotCached(t *testing.T) {
+	cache := New(0)
+	calls := 0
+	load := func() (*Feed, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("503")
+		}
+		return &Feed{Feed: &gofeed.Feed{Title: "ok"}}, nil
+	}
+
+	if _, _, err := cache.GetOrLoad("k", load); err == nil {
+		t.Fatal("expected load error")
+	}
+	feed, hit, err := cache.GetOrLoad("k", load)
+	if err != nil || hit || feed.Title != "ok" || calls != 2 {
+		t.Errorf("reintento = %v, %v, %v (calls %d)", feed, hit, err, calls)
+	}
+}
+
+func TestCacheSet(t *testing.T) {
+	cache := New(time.Hour)
+	cache.Set("k", &Feed{Feed: &gofeed.Feed{Title: "push"}})
+	feed, hit, err := cache.GetOrLoad("k", func() (*Feed, error) {
+		t.Error("no debería cargarse una clave guardada con Set")
+		return nil, nil
+	})
+	if err != nil || !hit || feed.Title != "push" {
+		t.Errorf("GetOrLoad tras Set = %v, %v, %v", feed, hit, err)
+	}
+
+	// Set sustituye la entrada existente
+	cache.Set("k", &Feed{Feed: &gofeed.Feed{Title: "push 2"}})
+	if feed, _, _ := cache.GetOrLoad("k", nil); feed.Title != "push 2" {
+		t.Errorf("title = %q", feed.Title)
+	}
+}
+
+func TestCacheConcurrentLoadsShareResult(t *testing.T) {
+	cache := New(0)
+	var loads int32
+	release := make(chan struct{})
+	load := func() (*Feed, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return &Feed{Feed: &gofeed.Feed{Title: "compartido"}}, nil
+	}
+
+	const callers = 8
+	var wg sync.WaitGroup
+	hits := make([]bool, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			feed, hit, err := cache.GetOrLoad("k", load)
+			if err != nil || feed.Title != "compartido" {
+				t.Errorf("caller %d = %v, %v", i, feed, err)
+			}
+			hits[i] = hit
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Errorf("loads = %d, want 1", n)
+	}
+	misses := 0
+	for _, hit := range hits {
+		if !hit {
+			misses++
+		}
+	}
+	if misses != 1 {
+		t.Errorf("misses = %d, want 1 (solo quien carga)", misses)
+	}
+}
+
+func TestCachePurge(t *testing.T) {
+	cache := New(10 * time.Millisecond)
+	cache.Set("vieja", &Feed{Feed: &gofeed.Feed{}})
+	time.Sleep(15 * time.Millisecond)
+	cache.Set("nueva", &Feed{Feed: &gofeed.Feed{}})
+	cache.Purge()
+
+	if _, ok := cache.entries["vieja"]; ok {
+		t.Error("Purge debería eliminar la entrada caducada")
+	}
+	if _, ok := cache.entries["nueva"]; !ok {
+		t.Error("Purge no debería eliminar la entrada vigente")
+	}
+
+	// Sin ttl Purge no elimina nada
+	forever := New(0)
+	forever.Set("k", &Feed{Feed: &gofeed.Feed{}})
+	forever.Purge()
+	if len(forever.entries) != 1 {
+		t.Error("Purge sin ttl no debería eliminar entradas")
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var cache *Cache
+	calls := 0
+	load := func() (*Feed, error) {
+		calls++
+		return &Feed{Feed: &gofeed.Feed{}}, nil
+	}
+	cache.GetOrLoad("k", load)
+	_, hit, _ := cache.GetOrLoad("k", load)
+	if hit || calls != 2 {
+		t.Errorf("una caché nil debería cargar siempre (hit %v, calls %d)", hit, calls)
+	}
+	cache.Set("k", &Feed{Feed: &gofeed.Feed{}})
+	cache.Purge()
+}
+
+func TestContext(t *testing.T) {
+	cache := New(0)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *Cache
+	}{
+		{"sin caché", context.Background(), nil},
+		{"con caché", NewContext(context.Background(), cache), cache},
+		{"caché nil", NewContext(context.Background(), nil), nil},
+	}
+	for _, tt := range tests {
+		if got := FromContext(tt.ctx); got != tt.want {
+			t.Errorf("%s: FromContext = %p, want %p", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCacheGet(t *testing.T) {
+	cache := New(10 * time.Millisecond)
+	cache.Set("vigente", &Feed{Feed: &gofeed.Feed{}, Hub: "https://hub.example/"})
+	cache.Set("caducada", &Feed{Feed: &gofeed.Feed{}})
+	cache.entries["caducada"].storedAt = time.Now().Add(-time.Hour)
+	cache.GetOrLoad("fallida", func() (*Feed, error) { return nil, errors.New("404") })
+
+	tests := []struct {
+		key    string
+		wantOK bool
+	}{
+		{"vigente", true},
+		{"caducada", false},
+		{"fallida", false},
+		{"desconocida", false},
+	}
+	for _, tt := range tests {
+		feed, ok := cache.Get(tt.key)
+		if ok != tt.wantOK {
+			t.Errorf("Get(%q) = %v, want %v", tt.key, ok, tt.wantOK)
+		}
+		if ok && feed.Hub != "https://hub.example/" {
+			t.Errorf("Get(%q).Hub = %q", tt.key, feed.Hub)
+		}
+	}
+	if _, ok := (*Cache)(nil).Get("vigente"); ok {
+		t.Error("una caché nil no tiene entradas")
+	}
+}
@@ -0,0 +1,192 @@
+package imaging
+
+import (
+	"errors"
+	"image"
+	"math"
+	"strings"
+)
+
+// blurHashChars es el alfabeto base 83 de BlurHash
+const blurHashChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// blurHashSampleSide es el lado mayor de la muestra sobre la que se calculan los componentes:
+// el resultado apenas cambia y el coste deja de depender del tamaño de la imagen
+const blurHashSampleSide = 64
+
+// ErrInvalidBlurHash indica que la cadena no es un BlurHash válido
+var ErrInvalidBlurHash = errors.New("blurhash no válido")
+
+// BlurHash codifica la imagen como un BlurHash (https://blurha.sh) de xComponents x yComponents
+// componentes (1-9 cada uno): una vista previa difuminada en unos 20-30 caracteres
+func BlurHash(img image.Image, xComponents, yComponents int) string {
+	xComponents = min(max(xComponents, 1), 9)
+	yComponents = min(max(yComponents, 1), 9)
+
+	bounds := img.Bounds()
+	scale := math.Min(1, blurHashSampleSide/float64(max(bounds.Dx(), bounds.Dy())))
+	width := max(1, int(math.Round(float64(bounds.Dx())*scale)))
+	height := max(1, int(math.Round(float64(bounds.Dy())*scale)))
+	sample := Resize(img, width, height, AreaAverage)
+
+	// Componentes DCT en espacio lineal, por filas de componentes
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				basisY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+				row := sample.Pix[y*sample.Stride:]
+				for x := 0; x < width; x++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) * basisY
+					factor[0] += basis * srgbToLinear(row[x*4])
+					factor[1] += basis * srgbToLinear(row[x*4+1])
+					factor[2] += basis * srgbToLinear(row[x*4+2])
+				}
+			}
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+			for c := range factor {
+				factor[c] *= normalisation / float64(width*height)
+			}
+			factors = append(factors, factor)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	maxValue := 1.0
+	if len(factors) > 1 {
+		actualMax := 0.0
+		for _, factor := range factors[1:] {
+			for _, v := range factor {
+				actualMax = math.Max(actualMax, math.Abs(v))
+			}
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		sb.WriteString(encode83(quantisedMax, 1))
+	} else {
+		sb.WriteString(encode83(0, 1))
+	}
+
+	dc := factors[0]
+	sb.WriteString(encode83(linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4))
+	for _, factor := range factors[1:] {
+		quant := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+		}
+		sb.WriteString(encode83(quant(factor[0])*19*19+quant(factor[1])*19+quant(factor[2]), 2))
+	}
+	return sb.String()
+}
+
+// DecodeBlurHash reconstruye la vista previa de un BlurHash a width x height píxeles.
+// Basta un tamaño muy pequeño (16x9): el navegador la escala y el resultado ya es difuso.
+func DecodeBlurHash(hash string, width, height int) (*image.RGBA, error) {
+	if len(hash) < 6 || width <= 0 || height <= 0 {
+		return nil, ErrInvalidBlurHash
+	}
+	sizeFlag, ok := decode83(hash[0:1])
+	if !ok {
+		return nil, ErrInvalidBlurHash
+	}
+	xComponents, yComponents := sizeFlag%9+1, sizeFlag/9+1
+	if len(hash) != 4+2*xComponents*yComponents {
+		return nil, ErrInvalidBlurHash
+	}
+	quantisedMax, ok := decode83(hash[1:2])
+	if !ok {
+		return nil, ErrInvalidBlurHash
+	}
+	maxValue := float64(quantisedMax+1) / 166
+
+	colors := make([][3]float64, xComponents*yComponents)
+	dc, ok := decode83(hash[2:6])
+	if !ok {
+		return nil, ErrInvalidBlurHash
+	}
+	colors[0] = [3]float64{srgbToLinear(uint8(dc >> 16)), srgbToLinear(uint8(dc >> 8)), srgbToLinear(uint8(dc))}
+	for k := 1; k < len(colors); k++ {
+		value, ok := decode83(hash[4+k*2 : 6+k*2])
+		if !ok {
+			return nil, ErrInvalidBlurHash
+		}
+		unquant := func(q int) float64 {
+			return signPow(float64(q-9)/9, 2) * maxValue
+		}
+		colors[k] = [3]float64{unquant(value / (19 * 19)), unquant(value / 19 % 19), unquant(value % 19)}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var pixel [3]float64
+			for j := 0; j < yComponents; j++ {
+				basisY := math.Cos(math.Pi * float64(y) * float64(j) / float64(height))
+				for i := 0; i < xComponents; i++ {
+					basis := math.Cos(math.Pi*float64(x)*float64(i)/float64(width)) * basisY
+					color := colors[j*xComponents+i]
+					pixel[0] += color[0] * basis
+					pixel[1] += color[1] * basis
+					pixel[2] += color[2] * basis
+				}
+			}
+			p := y*dst.Stride + x*4
+			dst.Pix[p] = uint8(linearToSRGB(pixel[0]))
+			dst.Pix[p+1] = uint8(linearToSRGB(pixel[1]))
+			dst.Pix[p+2] = uint8(linearToSRGB(pixel[2]))
+			dst.Pix[p+3] = 255
+		}
+	}
+	return dst, nil
+}
+
+// encode83 escribe value en base 83 con length dígitos
+func encode83(value, length int) string {
+	out := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		out[i] = blurHashChars[value%83]
+		value /= 83
+	}
+	return string(out)
+}
+
+// decode83 lee un número en base 83
+func decode83(s string) (int, bool) {
+	value := 0
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(blurHashChars, s[i])
+		if digit < 0 {
+			return 0, false
+		}
+		value = value*83 + digit
+	}
+	return value, true
+}
+
+// srgbToLinear convierte un canal sRGB (0-255) a luz lineal (0-1)
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB convierte luz lineal (0-1) a un canal sRGB (0-255)
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow eleva el valor absoluto a exp conservando el signo
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
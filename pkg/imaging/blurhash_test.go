@@ -0,0 +1,140 @@
+package imaging
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestBlurHashLength(t *testing.T) {
+	img := gradientImage(120, 80)
+	tests := []struct {
+		xComponents, yComponents int
+		wantLength               int
+		wantSize                 byte // Primer carácter: (x-1) + (y-1)*9
+	}{
+		{4, 3, 28, 'L'},
+		{1, 1, 6, '0'},
+		{9, 9, 166, '|'},
+		{0, 12, 22, '='}, // Se limita a 1x9
+	}
+	for _, tt := range tests {
+		hash := BlurHash(img, tt.xComponents, tt.yComponents)
+		if len(hash) != tt.wantLength || hash[0] != tt.wantSize {
+			t.Errorf("BlurHash(%d, %d) = %q (%d caracteres), want %d empezando por %q",
+				tt.xComponents, tt.yComponents, hash, len(hash), tt.wantLength, tt.wantSize)
+		}
+	}
+}
+
+func TestBlurHashRoundTrip(t *testing.T) {
+	orange := color.RGBA{230, 120, 30, 255}
+	red := color.RGBA{220, 20, 20, 255}
+	blue := color.RGBA{20, 40, 220, 255}
+	halves := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(halves, image.Rect(0, 0, 100, 100), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(halves, image.Rect(100, 0, 200, 100), image.NewUniform(blue), image.Point{}, draw.Src)
+
+	tests := []struct {
+		name      string
+		img       image.Image
+		checks    map[image.Point]color.RGBA // Puntos de la vista previa de 32x16 y su color aproximado
+		tolerance int
+	}{
+		{
+			"color liso",
+			uniformImage(300, 200, orange),
+			map[image.Point]color.RGBA{{0, 0}: orange, {16, 8}: orange, {31, 15}: orange},
+			12, // La base de BlurHash no anula del todo los componentes AC de un color liso
+		},
+		{
+			"dos mitades",
+			halves,
+			map[image.Point]color.RGBA{{2, 8}: red, {29, 8}: blue},
+			45,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := BlurHash(tt.img, 4, 3)
+			preview, err := DecodeBlurHash(hash, 32, 16)
+			if err != nil {
+				t.Fatalf("DecodeBlurHash(%q): %v", hash, err)
+			}
+			if preview.Bounds() != image.Rect(0, 0, 32, 16) {
+				t.Fatalf("Bounds = %v", preview.Bounds())
+			}
+			for p, want := range tt.checks {
+				if got := preview.RGBAAt(p.X, p.Y); !closeColor(got, want, tt.tolerance) {
+					t.Errorf("píxel %v = %v, want %v ± %d", p, got, want, tt.tolerance)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeBlurHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    string
+		width   int
+		height  int
+		wantErr bool
+	}{
+		{"ejemplo de blurha.sh", "LEHV6nWB2yk8pyo0adR*.7kCMdnj", 16, 9, false},
+		{"un componente", "00OZZy", 4, 4, false},
+		{"demasiado corto", "LEHV6", 16, 9, true},
+		{"longitud que no cuadra con los componentes", "LEHV6nWB2yk8pyo0adR*.7kCMdn", 16, 9, true},
+		{"carácter fuera del alfabeto", "LEHV6nWB2yk8pyo0adR*.7kCMd\"j", 16, 9, true},
+		{"tamaño vacío", "LEHV6nWB2yk8pyo0adR*.7kCMdnj", 0, 9, true},
+		{"vacío", "", 16, 9, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := DecodeBlurHash(tt.hash, tt.width, tt.height)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidBlurHash) {
+					t.Errorf("DecodeBlurHash err = %v, want ErrInvalidBlurHash", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeBlurHash: %v", err)
+			}
+			if img.Bounds() != image.Rect(0, 0, tt.width, tt.height) {
+				t.Errorf("Bounds = %v", img.Bounds())
+			}
+			if a := img.RGBAAt(tt.width-1, tt.height-1).A; a != 255 {
+				t.Errorf("alfa = %d, want 255", a)
+			}
+		})
+	}
+}
+
+func TestEncode83(t *testing.T) {
+	tests := []struct {
+		value  int
+		length int
+		want   string
+	}{
+		{0, 1, "0"},
+		{82, 1, "~"},
+		{83, 2, "10"},
+		{21, 1, "L"},
+		{0xFFFFFF, 4, "TSUA"},
+	}
+	for _, tt := range tests {
+		got := encode83(tt.value, tt.length)
+		if got != tt.want {
+			t.Errorf("encode83(%d, %d) = %q, want %q", tt.value, tt.length, got, tt.want)
+		}
+		if value, ok := decode83(got); !ok || value != tt.value {
+			t.Errorf("decode83(%q) = %d, %v; want %d", got, value, ok, tt.value)
+		}
+	}
+	if _, ok := decode83("a b"); ok {
+		t.Errorf("decode83 acepta caracteres fuera del alfabeto")
+	}
+}
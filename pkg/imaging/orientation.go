@@ -0,0 +1,121 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// exifOrientationTag es la etiqueta TIFF de la orientación en el IFD0
+const exifOrientationTag = 0x0112
+
+// Orientation lee la orientación EXIF (1-8) de un JPEG; devuelve 1 si no la tiene o no es un JPEG.
+// Basta con los primeros bytes del archivo: el segmento APP1 va antes de los datos de imagen.
+func Orientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xD8 || (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 || marker == 0xFF {
+			pos++ // Marcadores sin longitud o relleno
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return 1 // Comienzo de los datos de imagen: ya no habrá EXIF
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if marker == 0xE1 && length >= 8 && string(data[pos+4:pos+10]) == "Exif\x00\x00" {
+			return tiffOrientation(data[pos+10 : end])
+		}
+		pos = end
+	}
+	return 1
+}
+
+// tiffOrientation busca la etiqueta de orientación en el IFD0 de una cabecera TIFF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == exifOrientationTag {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value < 1 || value > 8 {
+				return 1
+			}
+			return value
+		}
+	}
+	return 1
+}
+
+// SwapsAxes indica si la orientación intercambia ancho y alto (giros de 90° y trasposiciones)
+func SwapsAxes(orientation int) bool {
+	return orientation >= 5 && orientation <= 8
+}
+
+// ApplyOrientation gira o voltea la imagen para mostrarla como la vio la cámara
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dstW, dstH := w, h
+	if SwapsAxes(orientation) {
+		dstW, dstH = h, w
+	}
+
+	// sourceOf devuelve el píxel de origen de cada píxel de destino
+	var sourceOf func(x, y int) (int, int)
+	switch orientation {
+	case 2: // Volteo horizontal
+		sourceOf = func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3: // Giro de 180°
+		sourceOf = func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4: // Volteo vertical
+		sourceOf = func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5: // Trasposición
+		sourceOf = func(x, y int) (int, int) { return y, x }
+	case 6: // Giro de 90° a la derecha
+		sourceOf = func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7: // Trasposición inversa
+		sourceOf = func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8: // Giro de 90° a la izquierda
+		sourceOf = func(x, y int) (int, int) { return w - 1 - y, x }
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	parallelRows(dstH, func(y int) {
+		for x := 0; x < dstW; x++ {
+			sx, sy := sourceOf(x, y)
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	})
+	return dst
+}
@@ -0,0 +1,157 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// exifSegment arma un segmento APP1 con un IFD0 que solo lleva la orientación
+func exifSegment(order binary.AppendByteOrder, orientation uint16) []byte {
+	tiff := []byte("II*\x00")
+	if order == binary.BigEndian {
+		tiff = []byte("MM\x00*")
+	}
+	tiff = order.AppendUint32(tiff, 8)
+	tiff = order.AppendUint16(tiff, 2) // Dos entradas: otra etiqueta antes de la orientación
+	tiff = order.AppendUint16(tiff, 0x010F)
+	tiff = order.AppendUint16(tiff, 2)
+	tiff = order.AppendUint32(tiff, 4)
+	tiff = append(tiff, 'A', 'B', 'C', 0)
+	tiff = order.AppendUint16(tiff, exifOrientationTag)
+	tiff = order.AppendUint16(tiff, 3)
+	tiff = order.AppendUint32(tiff, 1)
+	tiff = order.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// jpegHeader arma el principio de un JPEG con los segmentos indicados antes del SOS
+func jpegHeader(segments ...[]byte) []byte {
+	data := []byte{0xFF, 0xD8}
+	data = append(data, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0, 1, 1, 0, 0, 1, 0, 1, 0, 0)
+	for _, segment := range segments {
+		data = append(data, segment...)
+	}
+	return append(data, 0xFF, 0xDA, 0x00, 0x02)
+}
+
+func TestOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"sin EXIF", jpegHeader(), 1},
+		{"no es JPEG", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"vacío", nil, 1},
+		{"valor fuera de rango", jpegHeader(exifSegment(binary.LittleEndian, 9)), 1},
+		{"EXIF después del SOS", append(jpegHeader(), exifSegment(binary.LittleEndian, 6)...), 1},
+		{"segmento truncado", jpegHeader(exifSegment(binary.BigEndian, 6))[:40], 1},
+		{"APP1 que no es EXIF", jpegHeader([]byte("\xFF\xE1\x00\x0Ahttp:\x00\x00\x00")), 1},
+		{"relleno entre marcadores", jpegHeader([]byte{0xFF}, exifSegment(binary.BigEndian, 3)), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Orientation(tt.data); got != tt.want {
+				t.Errorf("Orientation = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrientationValues(t *testing.T) {
+	for _, order := range []binary.AppendByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for value := 1; value <= 8; value++ {
+			if got := Orientation(jpegHeader(exifSegment(order, uint16(value)))); got != value {
+				t.Errorf("Orientation(%v, %d) = %d", order, value, got)
+			}
+		}
+	}
+}
+
+func TestSwapsAxes(t *testing.T) {
+	for orientation := 0; orientation <= 9; orientation++ {
+		want := orientation >= 5 && orientation <= 8
+		if got := SwapsAxes(orientation); got != want {
+			t.Errorf("SwapsAxes(%d) = %v, want %v", orientation, got, want)
+		}
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	// Imagen de 3x2 con un color distinto por píxel:
+	//   A B C
+	//   D E F
+	palette := map[byte]color.RGBA{
+		'A': {255, 0, 0, 255}, 'B': {0, 255, 0, 255}, 'C': {0, 0, 255, 255},
+		'D': {255, 255, 0, 255}, 'E': {0, 255, 255, 255}, 'F': {255, 0, 255, 255},
+	}
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i, name := range []byte("ABCDEF") {
+		src.SetRGBA(i%3, i/3, palette[name])
+	}
+
+	// Cómo debe verse la imagen tras aplicar cada orientación
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{0, []string{"ABC", "DEF"}},
+		{1, []string{"ABC", "DEF"}},
+		{2, []string{"CBA", "FED"}},
+		{3, []string{"FED", "CBA"}},
+		{4, []string{"DEF", "ABC"}},
+		{5, []string{"AD", "BE", "CF"}},
+		{6, []string{"DA", "EB", "FC"}},
+		{7, []string{"FC", "EB", "DA"}},
+		{8, []string{"CF", "BE", "AD"}},
+		{9, []string{"ABC", "DEF"}},
+	}
+	for _, tt := range tests {
+		img := ApplyOrientation(src, tt.orientation)
+		bounds := img.Bounds()
+		var got []string
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			var row []byte
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+				name := byte('?')
+				for n, p := range palette {
+					if p == c {
+						name = n
+					}
+				}
+				row = append(row, name)
+			}
+			got = append(got, string(row))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ApplyOrientation(%d) = %v, want %v", tt.orientation, got, tt.want)
+		}
+	}
+}
+
+func TestApplyOrientationSubImage(t *testing.T) {
+	// Una subimagen con origen distinto de (0, 0) se gira igual que una copia
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			src.SetRGBA(x, y, color.RGBA{uint8(x * 60), uint8(y * 60), 0, 255})
+		}
+	}
+	sub := src.SubImage(image.Rect(1, 1, 4, 3))
+	img := ApplyOrientation(sub, 6)
+	if got := img.Bounds(); got != image.Rect(0, 0, 2, 3) {
+		t.Fatalf("Bounds = %v, want (0,0)-(2,3)", got)
+	}
+	// La esquina superior izquierda es la inferior izquierda de la subimagen: (1, 2)
+	if got, want := img.At(0, 0), src.At(1, 2); got != want {
+		t.Errorf("At(0, 0) = %v, want %v", got, want)
+	}
+}
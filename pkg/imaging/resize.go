@@ -0,0 +1,318 @@
+// Package imaging redimensiona imágenes con filtros de calidad (Lanczos y promedio de área),
+// las ajusta a un tamaño por recorte o con bandas y corrige la orientación EXIF.
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Filter es el núcleo de remuestreo
+type Filter int
+
+const (
+	// Lanczos (a=3): el más nítido, adecuado para reducir y ampliar fotografías
+	Lanczos Filter = iota
+	// AreaAverage promedia los píxeles de origen que cubre cada píxel de destino: sin aliasing y más rápido
+	AreaAverage
+)
+
+// lanczosSupport es el radio del núcleo Lanczos en píxeles de origen (a escala 1)
+const lanczosSupport = 3.0
+
+// ParseFilter convierte el nombre de un filtro ("lanczos" o "area"); por defecto Lanczos
+func ParseFilter(name string) Filter {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "area", "box", "average":
+		return AreaAverage
+	}
+	return Lanczos
+}
+
+// Resize escala la imagen completa a width x height sin conservar el aspecto
+func Resize(src image.Image, width, height int, filter Filter) *image.RGBA {
+	rgba := toRGBA(src)
+	if rgba.Rect.Dx() == width && rgba.Rect.Dy() == height {
+		return rgba
+	}
+	return resample(rgba, width, height, filter)
+}
+
+// Sample reduce la imagen con promedio de área a una muestra de como mucho maxSide píxeles de lado,
+// conservando el aspecto y sin ampliar. A diferencia de Resize no convierte antes la imagen entera a RGBA:
+// la recorre por bandas de filas, así que una foto grande nunca se copia a resolución completa.
+func Sample(src image.Image, maxSide int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	scale := math.Min(1, float64(maxSide)/float64(max(srcW, srcH)))
+	width := max(1, int(math.Round(float64(srcW)*scale)))
+	height := max(1, int(math.Round(float64(srcH)*scale)))
+	if rgba, ok := src.(*image.RGBA); ok {
+		return Resize(rgba, width, height, AreaAverage)
+	}
+
+	hWeights := contributions(srcW, width, AreaAverage)
+	vWeights := contributions(srcH, height, AreaAverage)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	var band *image.RGBA
+	acc := make([]float32, width*4)
+	for y, c := range vWeights {
+		// Banda con las filas de origen que cubre esta fila de destino
+		rows := len(c.weights)
+		if band == nil || band.Rect.Dy() < rows {
+			band = image.NewRGBA(image.Rect(0, 0, srcW, rows))
+		}
+		draw.Draw(band, image.Rect(0, 0, srcW, rows), src, image.Pt(bounds.Min.X, bounds.Min.Y+c.start), draw.Src)
+
+		clear(acc)
+		for i, wy := range c.weights {
+			line := band.Pix[i*band.Stride : i*band.Stride+srcW*4]
+			for x, cx := range hWeights {
+				var r, g, b, a float32
+				for j, wx := range cx.weights {
+					p := (cx.start + j) * 4
+					r += wx * float32(line[p])
+					g += wx * float32(line[p+1])
+					b += wx * float32(line[p+2])
+					a += wx * float32(line[p+3])
+				}
+				acc[x*4] += wy * r
+				acc[x*4+1] += wy * g
+				acc[x*4+2] += wy * b
+				acc[x*4+3] += wy * a
+			}
+		}
+
+		out := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		for x := 0; x < width; x++ {
+			alpha := clamp8(acc[x*4+3])
+			out[x*4+3] = alpha
+			out[x*4] = min(clamp8(acc[x*4]), alpha)
+			out[x*4+1] = min(clamp8(acc[x*4+1]), alpha)
+			out[x*4+2] = min(clamp8(acc[x*4+2]), alpha)
+		}
+	}
+	return dst
+}
+
+// Fill recorta la zona region de la imagen y la escala a width x height
+func Fill(src image.Image, region image.Rectangle, width, height int, filter Filter) *image.RGBA {
+	region = region.Intersect(src.Bounds())
+	if region.Empty() {
+		region = src.Bounds()
+	}
+	return Resize(subImage(src, region), width, height, filter)
+}
+
+// Letterbox escala la imagen para que quepa entera en width x height y rellena el resto con background
+func Letterbox(src image.Image, width, height int, filter Filter, background color.Color) *image.RGBA {
+	bounds := src.Bounds()
+	scale := math.Min(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	fitW := max(1, int(math.Round(float64(bounds.Dx())*scale)))
+	fitH := max(1, int(math.Round(float64(bounds.Dy())*scale)))
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Rect, image.NewUniform(background), image.Point{}, draw.Src)
+	offset := image.Pt((width-fitW)/2, (height-fitH)/2)
+	draw.Draw(dst, image.Rectangle{Min: offset, Max: offset.Add(image.Pt(fitW, fitH))}, Resize(src, fitW, fitH, filter), image.Point{}, draw.Src)
+	return dst
+}
+
+// subImage devuelve la zona de la imagen sin copiarla cuando el tipo lo permite
+func subImage(src image.Image, region image.Rectangle) image.Image {
+	if sub, ok := src.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(region)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+	draw.Draw(dst, dst.Rect, src, region.Min, draw.Src)
+	return dst
+}
+
+// toRGBA convierte la imagen a RGBA premultiplicado. *image.RGBA se usa tal cual y el resto
+// (incluido el *image.YCbCr de los JPEG) pasa por image/draw, que tiene conversiones rápidas
+func toRGBA(src image.Image) *image.RGBA {
+	if rgba, ok := src.(*image.RGBA); ok {
+		return rgba
+	}
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Rect, src, bounds.Min, draw.Src)
+	return dst
+}
+
+// contribution son los píxeles de origen y sus pesos para un píxel de destino
+type contribution struct {
+	start   int
+	weights []float32
+}
+
+// resample escala en dos pasadas separables (horizontal y vertical) repartidas entre los núcleos.
+// Se trabaja sobre RGBA premultiplicado para que los bordes transparentes no oscurezcan el color.
+func resample(src *image.RGBA, width, height int, filter Filter) *image.RGBA {
+	srcW, srcH := src.Rect.Dx(), src.Rect.Dy()
+	hWeights := contributions(srcW, width, filter)
+	vWeights := contributions(srcH, height, filter)
+
+	// Pasada horizontal: srcH filas de width píxeles en coma flotante
+	tmp := make([]float32, srcH*width*4)
+	parallelRows(srcH, func(y int) {
+		row := src.Pix[y*src.Stride : y*src.Stride+srcW*4]
+		out := tmp[y*width*4 : (y+1)*width*4]
+		for x, c := range hWeights {
+			var r, g, b, a float32
+			for i, w := range c.weights {
+				p := (c.start + i) * 4
+				r += w * float32(row[p])
+				g += w * float32(row[p+1])
+				b += w * float32(row[p+2])
+				a += w * float32(row[p+3])
+			}
+			out[x*4], out[x*4+1], out[x*4+2], out[x*4+3] = r, g, b, a
+		}
+	})
+
+	// Pasada vertical directamente sobre el destino
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	parallelRows(height, func(y int) {
+		c := vWeights[y]
+		out := dst.Pix[y*dst.Stride : y*dst.Stride+width*4]
+		for x := 0; x < width; x++ {
+			var r, g, b, a float32
+			for i, w := range c.weights {
+				p := ((c.start+i)*width + x) * 4
+				r += w * tmp[p]
+				g += w * tmp[p+1]
+				b += w * tmp[p+2]
+				a += w * tmp[p+3]
+			}
+			alpha := clamp8(a)
+			out[x*4+3] = alpha
+			// En premultiplicado ningún canal puede superar al alfa (Lanczos produce sobreoscilaciones)
+			out[x*4] = min(clamp8(r), alpha)
+			out[x*4+1] = min(clamp8(g), alpha)
+			out[x*4+2] = min(clamp8(b), alpha)
+		}
+	})
+	return dst
+}
+
+// contributions calcula, para cada píxel de destino, qué píxeles de origen intervienen y con qué peso
+func contributions(srcLen, dstLen int, filter Filter) []contribution {
+	scale := float64(srcLen) / float64(dstLen)
+	result := make([]contribution, dstLen)
+
+	for i := range result {
+		if filter == AreaAverage {
+			result[i] = areaContribution(float64(i)*scale, float64(i+1)*scale, srcLen)
+			continue
+		}
+
+		// Al reducir, el núcleo se ensancha con la escala para filtrar todas las frecuencias que se pierden
+		filterScale := math.Max(scale, 1)
+		support := lanczosSupport * filterScale
+		center := (float64(i)+0.5)*scale - 0.5
+		start := int(math.Ceil(center - support))
+		end := int(math.Floor(center + support))
+
+		weights := make([]float32, 0, end-start+1)
+		var sum float64
+		first := -1
+		for j := start; j <= end; j++ {
+			w := lanczos((float64(j) - center) / filterScale)
+			if w == 0 {
+				continue
+			}
+			// Los bordes se extienden repitiendo el primer y el último píxel
+			k := min(max(j, 0), srcLen-1)
+			if first < 0 {
+				first = k
+			}
+			for k-first >= len(weights) {
+				weights = append(weights, 0)
+			}
+			weights[k-first] += float32(w)
+			sum += w
+		}
+		for j := range weights {
+			weights[j] /= float32(sum)
+		}
+		result[i] = contribution{start: first, weights: weights}
+	}
+	return result
+}
+
+// areaContribution pondera cada píxel de origen por la parte de [from, to) que cubre
+func areaContribution(from, to float64, srcLen int) contribution {
+	start := int(math.Floor(from))
+	end := min(int(math.Ceil(to)), srcLen)
+	if end <= start {
+		// Ampliación: el píxel de destino cae dentro de un único píxel de origen
+		return contribution{start: min(start, srcLen-1), weights: []float32{1}}
+	}
+	weights := make([]float32, end-start)
+	var sum float32
+	for j := start; j < end; j++ {
+		w := float32(math.Min(to, float64(j+1)) - math.Max(from, float64(j)))
+		weights[j-start] = w
+		sum += w
+	}
+	for j := range weights {
+		weights[j] /= sum
+	}
+	return contribution{start: start, weights: weights}
+}
+
+// lanczos es el núcleo sinc(x)·sinc(x/a) con a = 3
+func lanczos(x float64) float64 {
+	x = math.Abs(x)
+	if x >= lanczosSupport {
+		return 0
+	}
+	if x < 1e-8 {
+		return 1
+	}
+	px := math.Pi * x
+	return lanczosSupport * math.Sin(px) * math.Sin(px/lanczosSupport) / (px * px)
+}
+
+// clamp8 redondea y limita un canal a 0..255
+func clamp8(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+// parallelRows ejecuta fn para cada fila repartiendo bloques de filas entre los núcleos
+func parallelRows(rows int, fn func(y int)) {
+	workers := min(runtime.GOMAXPROCS(0), rows)
+	if workers <= 1 {
+		for y := 0; y < rows; y++ {
+			fn(y)
+		}
+		return
+	}
+	chunk := (rows + workers - 1) / workers
+	var wg sync.WaitGroup
+	for from := 0; from < rows; from += chunk {
+		to := min(from+chunk, rows)
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			for y := from; y < to; y++ {
+				fn(y)
+			}
+		}(from, to)
+	}
+	wg.Wait()
+}
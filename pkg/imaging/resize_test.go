@@ -0,0 +1,215 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// uniformImage crea una imagen RGBA de un solo color
+func uniformImage(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Rect, image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+// gradientImage crea una imagen en escala de grises con un degradado diagonal
+func gradientImage(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{uint8((x + y) * 255 / (width + height - 2))})
+		}
+	}
+	return img
+}
+
+// closeColor indica si dos colores difieren como mucho tolerance en cada canal
+func closeColor(a, b color.RGBA, tolerance int) bool {
+	diff := func(x, y uint8) bool { return int(x)-int(y) <= tolerance && int(y)-int(x) <= tolerance }
+	return diff(a.R, b.R) && diff(a.G, b.G) && diff(a.B, b.B) && diff(a.A, b.A)
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		want Filter
+	}{
+		{"", Lanczos},
+		{"lanczos", Lanczos},
+		{"desconocido", Lanczos},
+		{"area", AreaAverage},
+		{" Box ", AreaAverage},
+		{"AVERAGE", AreaAverage},
+	}
+	for _, tt := range tests {
+		if got := ParseFilter(tt.name); got != tt.want {
+			t.Errorf("ParseFilter(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	orange := color.RGBA{240, 120, 20, 255}
+	tests := []struct {
+		name          string
+		width, height int
+		filter        Filter
+	}{
+		{"reducción Lanczos", 160, 90, Lanczos},
+		{"reducción por área", 160, 90, AreaAverage},
+		{"reducción no entera", 333, 77, Lanczos},
+		{"ampliación Lanczos", 1000, 700, Lanczos},
+		{"ampliación por área", 1000, 700, AreaAverage},
+		{"cambio de aspecto", 100, 400, Lanczos},
+		{"un píxel", 1, 1, AreaAverage},
+	}
+	src := uniformImage(640, 360, orange)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := Resize(src, tt.width, tt.height, tt.filter)
+			if got := dst.Bounds(); got != image.Rect(0, 0, tt.width, tt.height) {
+				t.Fatalf("Bounds = %v", got)
+			}
+			// Un color liso no puede cambiar: los pesos suman 1 y los bordes se extienden
+			for _, p := range []image.Point{{0, 0}, {tt.width / 2, tt.height / 2}, {tt.width - 1, tt.height - 1}} {
+				if got := dst.RGBAAt(p.X, p.Y); !closeColor(got, orange, 1) {
+					t.Errorf("píxel %v = %v, want %v", p, got, orange)
+				}
+			}
+		})
+	}
+}
+
+func TestResizeSameSize(t *testing.T) {
+	src := uniformImage(10, 10, color.RGBA{1, 2, 3, 255})
+	if dst := Resize(src, 10, 10, Lanczos); dst != src {
+		t.Errorf("Resize al mismo tamaño debe devolver la misma imagen")
+	}
+	// Otros tipos se convierten a RGBA aunque no cambie el tamaño
+	gray := gradientImage(10, 10)
+	if got := Resize(gray, 10, 10, Lanczos).RGBAAt(9, 9); got.R != gray.GrayAt(9, 9).Y {
+		t.Errorf("RGBAAt(9, 9) = %v, want %v", got, gray.GrayAt(9, 9))
+	}
+}
+
+func TestResizeAreaAverage(t *testing.T) {
+	// Tablero de ajedrez de un píxel: reducido a la mitad cada píxel promedia dos blancos y dos negros
+	src := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if (x+y)%2 == 0 {
+				src.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+			} else {
+				src.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+			}
+		}
+	}
+	dst := Resize(src, 4, 4, AreaAverage)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if got := dst.RGBAAt(x, y); !closeColor(got, color.RGBA{128, 128, 128, 255}, 1) {
+				t.Fatalf("píxel (%d, %d) = %v, want gris medio", x, y, got)
+			}
+		}
+	}
+}
+
+func TestResizePremultiplied(t *testing.T) {
+	// Una banda opaca junto a una transparente: Lanczos oscila en el borde pero ningún canal supera al alfa
+	src := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	draw.Draw(src, image.Rect(0, 0, 32, 64), image.NewUniform(color.RGBA{255, 255, 255, 255}), image.Point{}, draw.Src)
+	dst := Resize(src, 24, 24, Lanczos)
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 24; x++ {
+			c := dst.RGBAAt(x, y)
+			if c.R > c.A || c.G > c.A || c.B > c.A {
+				t.Fatalf("píxel (%d, %d) = %v no es RGBA premultiplicado válido", x, y, c)
+			}
+		}
+	}
+	if c := dst.RGBAAt(23, 12); c.A != 0 {
+		t.Errorf("la zona transparente tiene alfa %d", c.A)
+	}
+}
+
+func TestSample(t *testing.T) {
+	tests := []struct {
+		name          string
+		src           image.Image
+		maxSide       int
+		width, height int
+	}{
+		{"horizontal", gradientImage(1600, 900), 256, 256, 144},
+		{"vertical", gradientImage(900, 1600), 64, 36, 64},
+		{"no amplía", gradientImage(100, 50), 256, 100, 50},
+		{"no queda vacía", gradientImage(2000, 2), 100, 100, 1},
+		{"RGBA pasa por Resize", uniformImage(400, 300, color.RGBA{10, 20, 30, 255}), 40, 40, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sample(tt.src, tt.maxSide)
+			if got.Bounds() != image.Rect(0, 0, tt.width, tt.height) {
+				t.Fatalf("Bounds = %v, want %dx%d", got.Bounds(), tt.width, tt.height)
+			}
+			// Por bandas debe dar lo mismo que convertir la imagen entera y reducirla por área
+			want := Resize(tt.src, tt.width, tt.height, AreaAverage)
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					if !closeColor(got.RGBAAt(x, y), want.RGBAAt(x, y), 1) {
+						t.Fatalf("píxel (%d, %d) = %v, want %v", x, y, got.RGBAAt(x, y), want.RGBAAt(x, y))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSampleSubImage(t *testing.T) {
+	// La muestra parte del origen de la imagen aunque no sea (0, 0)
+	src := image.NewGray(image.Rect(0, 0, 200, 100))
+	draw.Draw(src, image.Rect(100, 0, 200, 100), image.NewUniform(color.Gray{255}), image.Point{}, draw.Src)
+	got := Sample(src.SubImage(image.Rect(100, 0, 200, 100)), 10)
+	if c := got.RGBAAt(0, 0); c.R != 255 {
+		t.Errorf("RGBAAt(0, 0) = %v, want blanco", c)
+	}
+}
+
+func TestFillAndLetterbox(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	src := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(src, image.Rect(0, 0, 100, 100), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(src, image.Rect(100, 0, 200, 100), image.NewUniform(blue), image.Point{}, draw.Src)
+
+	// Recortar la mitad derecha deja solo azul
+	fill := Fill(src, image.Rect(100, 0, 200, 100), 50, 50, Lanczos)
+	if got := fill.RGBAAt(0, 0); !closeColor(got, blue, 1) {
+		t.Errorf("Fill: RGBAAt(0, 0) = %v, want %v", got, blue)
+	}
+	// Una región fuera de la imagen usa la imagen entera
+	fill = Fill(src, image.Rect(500, 500, 600, 600), 20, 10, AreaAverage)
+	if got := fill.RGBAAt(0, 5); !closeColor(got, red, 1) {
+		t.Errorf("Fill fuera de la imagen: RGBAAt(0, 5) = %v, want %v", got, red)
+	}
+
+	black := color.RGBA{0, 0, 0, 255}
+	box := Letterbox(src, 100, 100, AreaAverage, black)
+	tests := []struct {
+		point image.Point
+		want  color.RGBA
+	}{
+		{image.Pt(50, 0), black},
+		{image.Pt(50, 24), black},
+		{image.Pt(10, 50), red},
+		{image.Pt(90, 50), blue},
+		{image.Pt(50, 75), black},
+		{image.Pt(50, 99), black},
+	}
+	for _, tt := range tests {
+		if got := box.RGBAAt(tt.point.X, tt.point.Y); !closeColor(got, tt.want, 1) {
+			t.Errorf("Letterbox: píxel %v = %v, want %v", tt.point, got, tt.want)
+		}
+	}
+}